@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/JaimeStill/go-lit/internal/config"
+	"github.com/JaimeStill/go-lit/pkg/handlers"
+	"github.com/JaimeStill/go-lit/pkg/middleware"
+	"github.com/JaimeStill/go-lit/pkg/module"
+	"github.com/pelletier/go-toml/v2"
+)
+
+type maintenanceStatus struct {
+	Enabled bool `json:"enabled"`
+}
+
+// registerAdminRoutes registers the bearer-protected administrative routes
+// when admin authentication is enabled. GET /admin/maintenance reports
+// whether maintenance mode is on and PUT /admin/maintenance sets it from a
+// {"enabled": bool} body. GET /debug/modules lists the mounted modules and
+// their health. When debug_config is set, GET /debug/config returns the
+// effective configuration from current with secrets redacted.
+func registerAdminRoutes(router *module.Router, cfg *config.AdminConfig, maintenance *atomic.Bool, current func() *config.Config, logger *slog.Logger) {
+	if !cfg.Auth.Enabled {
+		return
+	}
+	auth := middleware.BearerAuth(cfg.Auth.Validator())
+
+	router.HandleNative("GET /debug/modules", auth(router.ModulesHandler()).ServeHTTP)
+
+	if cfg.DebugConfig {
+		router.HandleNative("GET /debug/config", auth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			doc, err := redactedConfig(current())
+			if err != nil {
+				handlers.RespondError(w, logger, http.StatusInternalServerError, err)
+				return
+			}
+			handlers.RespondJSON(w, http.StatusOK, doc)
+		})).ServeHTTP)
+	}
+
+	router.HandleNative("GET /admin/maintenance", auth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlers.RespondJSON(w, http.StatusOK, maintenanceStatus{Enabled: maintenance.Load()})
+	})).ServeHTTP)
+
+	router.HandleNative("PUT /admin/maintenance", auth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var status maintenanceStatus
+		if err := handlers.DecodeJSON(r, &status); err != nil {
+			handlers.RespondError(w, logger, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+			return
+		}
+
+		if maintenance.Swap(status.Enabled) != status.Enabled {
+			subject := ""
+			if p, ok := middleware.PrincipalFromContext(r.Context()); ok {
+				subject = p.Subject
+			}
+			logger.Warn("maintenance mode changed", "enabled", status.Enabled, "subject", subject)
+		}
+		handlers.RespondJSON(w, http.StatusOK, status)
+	})).ServeHTTP)
+}
+
+// redactedConfig returns cfg with secrets redacted, keyed by the TOML names
+// used in the configuration files.
+func redactedConfig(cfg *config.Config) (map[string]any, error) {
+	data, err := toml.Marshal(cfg.Redacted())
+	if err != nil {
+		return nil, fmt.Errorf("encode config: %w", err)
+	}
+	var doc map[string]any
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+	return doc, nil
+}
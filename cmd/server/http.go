@@ -22,14 +22,17 @@ func newHTTPServer(cfg *config.ServerConfig, handler http.Handler, logger *slog.
 		http: &http.Server{
 			Addr:         cfg.Addr(),
 			Handler:      handler,
-			ReadTimeout:  cfg.ReadTimeoutDuration(),
-			WriteTimeout: cfg.WriteTimeoutDuration(),
+			ReadTimeout:  cfg.ReadTimeout.Duration,
+			WriteTimeout: cfg.WriteTimeout.Duration,
 		},
 		logger:          logger.With("system", "http"),
-		shutdownTimeout: cfg.ShutdownTimeoutDuration(),
+		shutdownTimeout: cfg.ShutdownTimeout.Duration,
 	}
 }
 
+// Start begins listening and registers the shutdown hook that stops the
+// server. The listener is not a startup hook, and a shut down http.Server
+// cannot be reused, so Coordinator.Restart does not bring it back.
 func (s *httpServer) Start(lc *lifecycle.Coordinator) error {
 	go func() {
 		s.logger.Info("server listening", "addr", s.http.Addr)
@@ -38,18 +41,13 @@ func (s *httpServer) Start(lc *lifecycle.Coordinator) error {
 		}
 	}()
 
-	lc.OnShutdown(func() {
-		<-lc.Context().Done()
+	lc.OnShutdownE("http", func(ctx context.Context) error {
 		s.logger.Info("shutting down server")
 
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+		shutdownCtx, cancel := context.WithTimeout(ctx, s.shutdownTimeout)
 		defer cancel()
 
-		if err := s.http.Shutdown(shutdownCtx); err != nil {
-			s.logger.Error("server shutdown error", "error", err)
-		} else {
-			s.logger.Info("server shutdown complete")
-		}
+		return s.http.Shutdown(shutdownCtx)
 	})
 
 	return nil
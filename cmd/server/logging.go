@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"sync/atomic"
+
+	"github.com/JaimeStill/go-lit/internal/config"
+)
+
+// logControl changes the level and format of the logger created with it
+// while the server runs, so a reloaded logging configuration applies to
+// every logger derived from it.
+type logControl struct {
+	level  slog.LevelVar
+	format config.LogFormat
+	base   atomic.Pointer[baseHandler]
+	out    io.Writer
+}
+
+type baseHandler struct {
+	handler slog.Handler
+}
+
+func newLogger(cfg *config.LoggingConfig) (*slog.Logger, *logControl) {
+	lc := &logControl{out: os.Stdout}
+	lc.apply(cfg)
+	return slog.New(&logHandler{control: lc}), lc
+}
+
+// apply sets the level and, when it changed, the output format. Calls to
+// apply must not overlap.
+func (lc *logControl) apply(cfg *config.LoggingConfig) {
+	lc.level.Set(cfg.Level.ToSlogLevel())
+	if lc.format == cfg.Format && lc.base.Load() != nil {
+		return
+	}
+	lc.format = cfg.Format
+
+	opts := &slog.HandlerOptions{Level: &lc.level}
+	var handler slog.Handler
+	if cfg.Format == config.LogFormatJSON {
+		handler = slog.NewJSONHandler(lc.out, opts)
+	} else {
+		handler = slog.NewTextHandler(lc.out, opts)
+	}
+	lc.base.Store(&baseHandler{handler: handler})
+}
+
+// logHandler writes through the current base handler of its control.
+// Attributes and groups added with WithAttrs and WithGroup are replayed onto
+// a new base handler once, the first time it is used after a format change.
+type logHandler struct {
+	control *logControl
+	derive  []func(slog.Handler) slog.Handler
+	cache   atomic.Pointer[derivedHandler]
+}
+
+type derivedHandler struct {
+	base    *baseHandler
+	handler slog.Handler
+}
+
+func (h *logHandler) current() slog.Handler {
+	base := h.control.base.Load()
+	if c := h.cache.Load(); c != nil && c.base == base {
+		return c.handler
+	}
+	handler := base.handler
+	for _, derive := range h.derive {
+		handler = derive(handler)
+	}
+	h.cache.Store(&derivedHandler{base: base, handler: handler})
+	return handler
+}
+
+func (h *logHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.control.level.Level()
+}
+
+func (h *logHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.current().Handle(ctx, r)
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+}
+
+func (h *logHandler) with(derive func(slog.Handler) slog.Handler) *logHandler {
+	return &logHandler{control: h.control, derive: append(slices.Clip(h.derive), derive)}
+}
@@ -1,16 +1,19 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
-	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/JaimeStill/go-lit/internal/config"
+	"github.com/JaimeStill/go-lit/pkg/lifecycle"
 )
 
 func main() {
-	cfg, err := config.Load()
+	configPath := flag.String("config", "", "path to the base configuration file (default $SERVICE_CONFIG or config.toml)")
+	flag.Parse()
+
+	cfg, err := loadConfig(*configPath)
 	if err != nil {
 		log.Fatal("config load failed:", err)
 	}
@@ -20,18 +23,18 @@ func main() {
 		log.Fatal("service init failed:", err)
 	}
 
-	if err := srv.Start(); err != nil {
-		log.Fatal("service start failed:", err)
+	if err := lifecycle.Run(context.Background(), srv, cfg.ShutdownTimeout.Duration); err != nil {
+		log.Fatal("service stopped with error:", err)
 	}
 
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-	<-sigChan
+	log.Println("service stopped gracefully")
+}
 
-	if err := srv.Shutdown(cfg.ShutdownTimeoutDuration()); err != nil {
-		log.Fatal("shutdown failed:", err)
+// loadConfig loads the configuration from path when given, which takes
+// precedence over SERVICE_CONFIG.
+func loadConfig(path string) (*config.Config, error) {
+	if path != "" {
+		return config.LoadFrom(path)
 	}
-
-	log.Println("service stopped gracefully")
+	return config.Load()
 }
@@ -1,28 +1,58 @@
 package main
 
 import (
+	"fmt"
 	"log/slog"
+	"maps"
 	"net/http"
+	"strings"
+	"sync/atomic"
 
 	"github.com/JaimeStill/go-lit/internal/api"
 	"github.com/JaimeStill/go-lit/internal/config"
+	"github.com/JaimeStill/go-lit/internal/prompts"
+	"github.com/JaimeStill/go-lit/pkg/handlers"
 	"github.com/JaimeStill/go-lit/pkg/lifecycle"
 	"github.com/JaimeStill/go-lit/pkg/middleware"
 	"github.com/JaimeStill/go-lit/pkg/module"
+	"github.com/JaimeStill/go-lit/pkg/registry"
 	"github.com/JaimeStill/go-lit/web/app"
 	"github.com/JaimeStill/go-lit/web/scalar"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// Modules holds all application modules that are mounted to the router.
+// assetETagMaxSize is the largest static asset response given an ETag.
+const assetETagMaxSize = 8 << 20
+
+// Modules holds all application modules that are mounted to the router,
+// along with the handler for requests that match none of them.
 type Modules struct {
-	API    *module.Module
-	App    *module.Module
-	Scalar *module.Module
+	API      *module.Module
+	App      *module.Module
+	Scalar   *module.Module
+	NotFound http.Handler
+
+	// APIReload applies reloaded settings to the API module's middleware.
+	APIReload *api.Reloadable
 }
 
 // NewModules creates and configures all application modules.
-func NewModules(cfg *config.Config, logger *slog.Logger) (*Modules, error) {
-	apiModule, err := api.NewModule(cfg, logger)
+// Shared services are provided to the registry before any module is
+// constructed, and every resolution is verified once construction completes.
+// Each registry consumer then gets a startup hook with lc, ordered after the
+// consumers whose services it resolved.
+// Every module answers 503 while maintenance is set; the app and scalar
+// modules show browsers a maintenance page instead of the JSON payload.
+func NewModules(cfg *config.Config, logger *slog.Logger, tp trace.TracerProvider, lc *lifecycle.Coordinator, maintenance *atomic.Bool) (*Modules, error) {
+	reg := registry.New()
+	registry.Provide(reg, cfg)
+	registry.Provide(reg, logger)
+	registry.Provide(reg, tp)
+	registry.Provide(reg, prompts.NewStore())
+	apiReload := api.NewReloadable(&cfg.API)
+	registry.Provide(reg, apiReload)
+
+	apiModule, err := api.NewModule(reg.Consumer("api"))
 	if err != nil {
 		return nil, err
 	}
@@ -33,12 +63,39 @@ func NewModules(cfg *config.Config, logger *slog.Logger) (*Modules, error) {
 	}
 	appModule.Use(middleware.Logger(logger))
 
+	notFoundPage, err := app.NotFoundHandler("/app")
+	if err != nil {
+		return nil, err
+	}
+
 	scalarModule := scalar.NewModule("/scalar")
 
+	retryAfter := cfg.Admin.MaintenanceRetryAfter.Duration
+	page := middleware.WithMaintenancePage(app.MaintenancePage())
+	apiModule.Use(middleware.Maintenance(maintenance, retryAfter))
+	appModule.Use(middleware.Maintenance(maintenance, retryAfter, page))
+	scalarModule.Use(middleware.Maintenance(maintenance, retryAfter, page))
+
+	// Bundle file names are not content-hashed, so assets are revalidated
+	// by ETag rather than marked immutable.
+	revalidate := middleware.CacheRule{Prefix: "/", CacheControl: "no-cache"}
+	appModule.Use(middleware.ConditionalGet(assetETagMaxSize, revalidate))
+	scalarModule.Use(middleware.ConditionalGet(assetETagMaxSize, revalidate))
+
+	if err := reg.Verify(); err != nil {
+		return nil, fmt.Errorf("unresolved dependencies: %w", err)
+	}
+	if err := reg.Start(lc); err != nil {
+		return nil, fmt.Errorf("module startup order: %w", err)
+	}
+
 	return &Modules{
-		API:    apiModule,
-		App:    appModule,
-		Scalar: scalarModule,
+		API:      apiModule,
+		App:      appModule,
+		Scalar:   scalarModule,
+		NotFound: notFoundHandler(notFoundPage),
+
+		APIReload: apiReload,
 	}, nil
 }
 
@@ -47,25 +104,92 @@ func (m *Modules) Mount(router *module.Router) {
 	router.Mount(m.API)
 	router.Mount(m.App)
 	router.Mount(m.Scalar)
+	router.NotFound(m.NotFound)
+}
+
+// notFoundHandler renders the app's not-found page for browser requests and
+// responds with a JSON error otherwise.
+func notFoundHandler(page http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.Header.Get("Accept"), "text/html") {
+			page.ServeHTTP(w, r)
+			return
+		}
+		handlers.RespondJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	})
 }
 
-func buildRouter(lc *lifecycle.Coordinator) *module.Router {
+// LogRoutes logs the routes each module records at debug level, with paths
+// including the module prefix.
+func (m *Modules) LogRoutes(logger *slog.Logger) {
+	for _, mod := range []*module.Module{m.API, m.App, m.Scalar} {
+		for _, route := range mod.Routes() {
+			logger.Debug(
+				"registered route",
+				"method", route.Method,
+				"host", route.Host,
+				"path", mod.Prefix()+route.Path,
+				"tags", route.GroupTags,
+				"documented", route.HasOpenAPI,
+			)
+		}
+	}
+}
+
+// buildRouter creates the router with its native health and readiness
+// routes. /healthz fails only when a lifecycle liveness check does, while
+// readiness also fails during maintenance, so load balancers drain the
+// instance while /healthz keeps it alive.
+func buildRouter(lc *lifecycle.Coordinator, maintenance *atomic.Bool) *module.Router {
 	router := module.NewRouter()
 
+	lc.RegisterChecker("serving", lifecycle.ReadinessFunc(func() bool {
+		return !maintenance.Load()
+	}))
+
 	router.HandleNative("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
+		failed := lc.Liveness(r.Context())
+		if len(failed) == 0 {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK"))
+			return
+		}
+
+		failing := make(map[string]string, len(failed))
+		for name, err := range failed {
+			failing[name] = err.Error()
+		}
+		handlers.RespondJSON(w, http.StatusServiceUnavailable, livenessReport{Failing: failing})
 	})
 
 	router.HandleNative("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
-		if !lc.Ready() {
-			w.WriteHeader(http.StatusServiceUnavailable)
-			w.Write([]byte("NOT READY"))
-			return
+		checks := router.Readiness()
+		maps.Copy(checks, lc.Report())
+
+		report := readinessReport{Ready: true, Checks: checks}
+		for _, ready := range checks {
+			report.Ready = report.Ready && ready
+		}
+
+		code := http.StatusOK
+		if !report.Ready {
+			code = http.StatusServiceUnavailable
 		}
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("READY"))
+		handlers.RespondJSON(w, code, report)
 	})
 
 	return router
 }
+
+// readinessReport is the /readyz response body: overall readiness and the
+// state of each module and lifecycle check by name.
+type readinessReport struct {
+	Ready  bool            `json:"ready"`
+	Checks map[string]bool `json:"checks"`
+}
+
+// livenessReport is the /healthz response body when liveness checks fail:
+// the error of each failing check by name.
+type livenessReport struct {
+	Failing map[string]string `json:"failing"`
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/JaimeStill/go-lit/internal/api"
+	"github.com/JaimeStill/go-lit/internal/config"
+)
+
+// hotReloadable are the configuration keys, or key prefixes ending in ".",
+// that apply without a restart.
+var hotReloadable = []string{"logging.", "api.cors.", "api.rate_limit."}
+
+// reloader applies configuration reloaded on SIGHUP or when the
+// configuration files change. Settings that need a restart are logged and
+// left as they were.
+type reloader struct {
+	mu        sync.Mutex
+	current   *config.Config
+	logging   *logControl
+	apiReload *api.Reloadable
+	logger    *slog.Logger
+}
+
+// run watches the configuration files and handles SIGHUP until ctx is done.
+func (r *reloader) run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	go config.Watch(ctx, r.current.Source(), r.apply, r.reject)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.logger.Info("reloading configuration", "trigger", "SIGHUP")
+			cfg, err := config.LoadFrom(r.current.Source())
+			if err != nil {
+				r.reject(err)
+				continue
+			}
+			r.apply(cfg)
+		}
+	}
+}
+
+// Current returns the configuration in effect, including reloaded settings.
+func (r *reloader) Current() *config.Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+func (r *reloader) reject(err error) {
+	r.logger.Error("configuration reload rejected, keeping current configuration", "error", err)
+}
+
+// apply applies the hot-reloadable settings in which next differs from the
+// current configuration.
+func (r *reloader) apply(next *config.Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var applied, ignored []string
+	for _, key := range config.Diff(r.current, next) {
+		if isHotReloadable(key) {
+			applied = append(applied, key)
+		} else {
+			ignored = append(ignored, key)
+		}
+	}
+	if len(applied) == 0 && len(ignored) == 0 {
+		return
+	}
+
+	if len(ignored) > 0 {
+		r.logger.Warn("configuration changes require a restart", "keys", ignored)
+	}
+	if len(applied) == 0 {
+		return
+	}
+
+	updated := *r.current
+	updated.Logging = next.Logging
+	updated.API.CORS = next.API.CORS
+	updated.API.RateLimit = next.API.RateLimit
+
+	r.logging.apply(&updated.Logging)
+	r.apiReload.Apply(&updated.API)
+	r.current = &updated
+	r.logger.Info("configuration reloaded", "keys", applied)
+}
+
+func isHotReloadable(key string) bool {
+	for _, prefix := range hotReloadable {
+		if strings.HasPrefix(key+".", prefix) {
+			return true
+		}
+	}
+	return false
+}
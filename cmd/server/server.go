@@ -1,34 +1,63 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
-	"os"
+	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/JaimeStill/go-lit/internal/config"
 	"github.com/JaimeStill/go-lit/pkg/lifecycle"
+	"github.com/JaimeStill/go-lit/pkg/middleware"
 )
 
+// startupProgressInterval is how often Start logs the startup hooks it is
+// still waiting on.
+const startupProgressInterval = 5 * time.Second
+
 // Server coordinates the lifecycle of all subsystems.
 type Server struct {
 	lifecycle *lifecycle.Coordinator
 	logger    *slog.Logger
 	modules   *Modules
 	http      *httpServer
+	cfg       *config.ServerConfig
 }
 
 // NewServer creates and initializes the service with all subsystems.
 func NewServer(cfg *config.Config) (*Server, error) {
 	lc := lifecycle.New()
-	logger := newLogger(&cfg.Logging)
+	logger, logging := newLogger(&cfg.Logging)
+	logger.Info("configuration loaded", "source", cfg.Source(), "overlays", cfg.Overlays(), "profile", cfg.Profile)
+
+	tp, err := newTracerProvider(&cfg.Telemetry, cfg.Version, lc)
+	if err != nil {
+		return nil, err
+	}
 
-	modules, err := NewModules(cfg, logger)
+	maintenance := new(atomic.Bool)
+	modules, err := NewModules(cfg, logger, tp, lc, maintenance)
 	if err != nil {
 		return nil, err
 	}
 
-	router := buildRouter(lc)
+	reload := &reloader{current: cfg, logging: logging, apiReload: modules.APIReload, logger: logger}
+	lc.OnShutdown(func() { reload.run(lc.Context()) })
+
+	router := buildRouter(lc, maintenance)
+	registerAdminRoutes(router, &cfg.Admin, maintenance, reload.Current, logger)
 	modules.Mount(router)
+	modules.LogRoutes(logger)
+
+	if logger.Enabled(context.Background(), slog.LevelDebug) {
+		if doc, err := redactedConfig(cfg); err == nil {
+			logger.Debug("effective configuration", "config", doc)
+		}
+	}
 
 	logger.Info(
 		"server initialized",
@@ -40,11 +69,17 @@ func NewServer(cfg *config.Config) (*Server, error) {
 		lifecycle: lc,
 		logger:    logger,
 		modules:   modules,
-		http:      newHTTPServer(&cfg.Server, router, logger),
+		http:      newHTTPServer(&cfg.Server, withProxySupport(&cfg.Server, middleware.Compress()(router)), logger),
+		cfg:       &cfg.Server,
 	}, nil
 }
 
-// Start begins all subsystems and returns when they are ready.
+// Start begins all subsystems and returns when they are ready, logging the
+// startup hooks still running every few seconds. If startup outlasts the
+// configured timeout, Start returns an error when the timeout action is
+// abort; when it is degrade, Start returns with the service not ready, and
+// it becomes ready once the remaining hooks finish. A failed startup hook
+// always fails Start.
 func (s *Server) Start() error {
 	s.logger.Info("starting service")
 
@@ -52,31 +87,87 @@ func (s *Server) Start() error {
 		return err
 	}
 
+	ctx := context.Background()
+	if timeout := s.cfg.StartupTimeout.Duration; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	stop := s.logStartupProgress()
+	err := s.lifecycle.WaitForStartupContext(ctx)
+	if err == nil {
+		stop()
+		s.logger.Info("all subsystems ready")
+		return nil
+	}
+
+	if s.cfg.StartupTimeoutAction == config.StartupAbort || !errors.Is(err, context.DeadlineExceeded) {
+		stop()
+		return fmt.Errorf("startup: %w", err)
+	}
+
+	s.logger.Warn("startup timed out, continuing degraded", "error", err)
 	go func() {
 		s.lifecycle.WaitForStartup()
+		stop()
 		s.logger.Info("all subsystems ready")
 	}()
-
 	return nil
 }
 
-// Shutdown gracefully stops all subsystems within the provided context deadline.
+// logStartupProgress logs how many startup hooks have completed and which
+// are still running every startupProgressInterval until stop is called.
+func (s *Server) logStartupProgress() (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(startupProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				total, pending := s.lifecycle.StartupProgress()
+				s.logger.Info(
+					"waiting for startup hooks",
+					"progress", fmt.Sprintf("%d/%d", total-len(pending), total),
+					"pending", pending,
+				)
+			}
+		}
+	}()
+	return sync.OnceFunc(func() { close(done) })
+}
+
+// Shutdown gracefully stops all subsystems within the provided timeout,
+// logging the outcome of each shutdown hook.
 func (s *Server) Shutdown(timeout time.Duration) error {
 	s.logger.Info("initiating shutdown")
-	return s.lifecycle.Shutdown(timeout)
+	results, err := s.lifecycle.ShutdownResults(timeout)
+
+	for _, r := range results {
+		switch {
+		case r.Err == nil:
+			s.logger.Info("shutdown hook complete", "hook", r.Name, "duration", r.Duration)
+		case errors.Is(r.Err, lifecycle.ErrShutdownTimeout):
+			s.logger.Error("shutdown hook still running", "hook", r.Name, "duration", r.Duration)
+		default:
+			s.logger.Error("shutdown hook failed", "hook", r.Name, "duration", r.Duration, "error", r.Err)
+		}
+	}
+	return err
 }
 
-func newLogger(cfg *config.LoggingConfig) *slog.Logger {
-	opts := &slog.HandlerOptions{
-		Level: cfg.Level.ToSlogLevel(),
+// withProxySupport wraps the router to accept the configured
+// X-Forwarded-Prefix values and to resolve client addresses forwarded by
+// trusted proxies, skipping each when it is not configured.
+func withProxySupport(cfg *config.ServerConfig, router http.Handler) http.Handler {
+	if len(cfg.ForwardedPrefixes) > 0 {
+		router = middleware.ForwardedPrefix(cfg.ForwardedPrefixes)(router)
 	}
-
-	var handler slog.Handler
-	if cfg.Format == config.LogFormatJSON {
-		handler = slog.NewJSONHandler(os.Stdout, opts)
-	} else {
-		handler = slog.NewTextHandler(os.Stdout, opts)
+	if len(cfg.TrustedProxies) > 0 {
+		router = middleware.RealIP(cfg.TrustedProxyPrefixes())(router)
 	}
-
-	return slog.New(handler)
+	return router
 }
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/JaimeStill/go-lit/internal/config"
+	"github.com/JaimeStill/go-lit/pkg/lifecycle"
+)
+
+// newTracerProvider creates the tracer provider described by cfg, exporting
+// batched spans over OTLP/HTTP and flushing them at shutdown. A no-op
+// provider is returned when telemetry is disabled.
+func newTracerProvider(cfg *config.TelemetryConfig, version string, lc *lifecycle.Coordinator) (trace.TracerProvider, error) {
+	if !cfg.Enabled {
+		return noop.NewTracerProvider(), nil
+	}
+
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("telemetry endpoint: %w", err)
+	}
+	if strings.Trim(endpoint.Path, "/") == "" {
+		endpoint.Path = "/v1/traces"
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(endpoint.String()))
+	if err != nil {
+		return nil, fmt.Errorf("create trace exporter: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", cfg.ServiceName),
+			attribute.String("service.version", version),
+		)),
+	)
+
+	lc.OnShutdownE("telemetry", tp.Shutdown)
+
+	return tp, nil
+}
@@ -0,0 +1,56 @@
+package agents
+
+import (
+	"slices"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/config"
+)
+
+// Defaults are the server settings every execution starts from before the
+// client's agent configuration is merged over them. Empty BaseURL and Model
+// and a zero Timeout keep the go-agents defaults. MaxImages falls back to
+// MaxVisionImages when zero and a zero MaxImageSize accepts images of any
+// size. When AllowedModels is not empty, a request naming a model that is not
+// listed is rejected; a request naming none uses Model, and is rejected when
+// Model is empty too.
+type Defaults struct {
+	BaseURL       string
+	Model         string
+	Timeout       time.Duration
+	MaxImages     int
+	MaxImageSize  int64
+	AllowedModels []string
+}
+
+// agentConfig returns the go-agents default configuration with d applied.
+func (d *Defaults) agentConfig() config.AgentConfig {
+	cfg := config.DefaultAgentConfig()
+	if d.BaseURL != "" {
+		cfg.Provider.BaseURL = d.BaseURL
+	}
+	if d.Model != "" {
+		cfg.Model.Name = d.Model
+	}
+	if d.Timeout > 0 {
+		cfg.Client.Timeout = config.Duration(d.Timeout)
+	}
+	return cfg
+}
+
+// maxImages returns the number of images accepted by a vision request.
+func (d *Defaults) maxImages() int {
+	if d.MaxImages > 0 {
+		return d.MaxImages
+	}
+	return MaxVisionImages
+}
+
+// allows reports whether a request may use model. An empty model is only
+// allowed when every model is.
+func (d *Defaults) allows(model string) bool {
+	if len(d.AllowedModels) == 0 {
+		return true
+	}
+	return model != "" && slices.Contains(d.AllowedModels, model)
+}
@@ -0,0 +1,70 @@
+package agents
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// DryRunHeader requests a dry run when set to a truthy value.
+// It is the only dry-run switch for multipart vision requests.
+const DryRunHeader = "X-Dry-Run"
+
+// charsPerToken approximates the average characters per token used
+// to estimate prompt size without invoking a provider tokenizer.
+const charsPerToken = 4
+
+// DryRunReport describes how the server would process a request
+// without executing it against the provider.
+type DryRunReport struct {
+	Protocol        string         `json:"protocol" openapi:"description=Protocol that would execute (chat or vision)"`
+	Template        string         `json:"template,omitempty" openapi:"description=Prompt template rendered as the prompt"`
+	Agent           string         `json:"agent,omitempty" openapi:"description=Resolved agent name"`
+	Provider        string         `json:"provider,omitempty" openapi:"description=Resolved provider name"`
+	BaseURL         string         `json:"base_url,omitempty" openapi:"description=Resolved provider base URL"`
+	Model           string         `json:"model,omitempty" openapi:"description=Resolved model name"`
+	Options         map[string]any `json:"options,omitempty" openapi:"description=Resolved model options for the protocol"`
+	PromptChars     int            `json:"prompt_chars" openapi:"description=Prompt length in characters"`
+	Images          int            `json:"images,omitempty" openapi:"description=Number of images (vision only)"`
+	EstimatedTokens int            `json:"estimated_tokens" openapi:"description=Estimated prompt tokens, including the system prompt"`
+	Status          int            `json:"status" openapi:"description=Status code the real request would return before streaming"`
+	Error           string         `json:"error,omitempty" openapi:"description=Reason the real request would be rejected"`
+}
+
+func newDryRunReport(exec *execution, err error) *DryRunReport {
+	report := &DryRunReport{
+		Protocol:    exec.protocol,
+		Template:    exec.template,
+		PromptChars: len(exec.prompt),
+		Images:      len(exec.images),
+		Status:      http.StatusOK,
+	}
+
+	if cfg := exec.config; cfg.Provider != nil {
+		report.Agent = cfg.Name
+		report.Provider = cfg.Provider.Name
+		report.BaseURL = cfg.Provider.BaseURL
+		if cfg.Model != nil {
+			report.Model = cfg.Model.Name
+			report.Options = cfg.Model.Capabilities[exec.protocol]
+		}
+		report.EstimatedTokens = estimateTokens(cfg.SystemPrompt) + estimateTokens(exec.prompt)
+	} else {
+		report.EstimatedTokens = estimateTokens(exec.prompt)
+	}
+
+	if err != nil {
+		report.Status = MapHTTPStatus(err)
+		report.Error = err.Error()
+	}
+
+	return report
+}
+
+func estimateTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+func isDryRun(r *http.Request) bool {
+	dryRun, _ := strconv.ParseBool(r.Header.Get(DryRunHeader))
+	return dryRun
+}
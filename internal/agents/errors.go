@@ -3,17 +3,26 @@ package agents
 import (
 	"errors"
 	"net/http"
+
+	"github.com/JaimeStill/go-lit/internal/prompts"
+	"github.com/JaimeStill/go-lit/pkg/handlers"
 )
 
 var (
-	ErrExecution      = errors.New("execution error")
-	ErrInvalidConfig  = errors.New("invalid configuration")
-	ErrInvalidRequest = errors.New("invalid request")
+	ErrExecution       = errors.New("execution error")
+	ErrInvalidConfig   = errors.New("invalid configuration")
+	ErrInvalidRequest  = errors.New("invalid request")
+	ErrModelNotAllowed = errors.New("model not allowed")
+	ErrTemplate        = errors.New("template error")
 )
 
 func MapHTTPStatus(err error) int {
 	switch {
-	case errors.Is(err, ErrInvalidConfig), errors.Is(err, ErrInvalidRequest):
+	case errors.Is(err, handlers.ErrBodyTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrTemplate):
+		return prompts.MapHTTPStatus(err)
+	case errors.Is(err, ErrInvalidConfig), errors.Is(err, ErrInvalidRequest), errors.Is(err, ErrModelNotAllowed):
 		return http.StatusBadRequest
 	case errors.Is(err, ErrExecution):
 		return http.StatusInternalServerError
@@ -11,82 +11,172 @@ import (
 	"github.com/JaimeStill/go-agents/pkg/response"
 	"github.com/JaimeStill/go-lit/pkg/handlers"
 	"github.com/JaimeStill/go-lit/pkg/routes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const maxFormMemory = 32 << 20
 
+// TemplateRenderer renders a named prompt template with variables.
+type TemplateRenderer interface {
+	Render(name string, variables map[string]any) (string, error)
+}
+
 type Handler struct {
-	logger *slog.Logger
+	logger    *slog.Logger
+	templates TemplateRenderer
+	defaults  Defaults
 }
 
-func NewHandler(logger *slog.Logger) *Handler {
-	return &Handler{logger: logger}
+func NewHandler(logger *slog.Logger, templates TemplateRenderer, defaults Defaults) *Handler {
+	return &Handler{
+		logger:    logger,
+		templates: templates,
+		defaults:  defaults,
+	}
 }
 
 func (h *Handler) Routes() routes.Group {
 	return routes.Group{
-		Prefix: "",
-		Tags:   []string{"Execution"},
+		Prefix:      "",
+		Tags:        []string{"Execution"},
+		Description: "Streaming chat and vision execution",
+		Schemas:     Schemas,
 		Routes: []routes.Route{
 			{Method: "POST", Pattern: "/chat", Handler: h.ChatStream, OpenAPI: Spec.ChatStream},
-			{Method: "POST", Pattern: "/vision", Handler: h.VisionStream, OpenAPI: Spec.VisionStream},
+			{Method: "POST", Pattern: "/vision", Handler: h.VisionStream, OpenAPI: visionStreamOperation(h.defaults.maxImages())},
 		},
 	}
 }
 
 func (h *Handler) ChatStream(w http.ResponseWriter, r *http.Request) {
 	var req ChatStreamRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, fmt.Errorf("%w: %v", ErrInvalidRequest, err))
+	if err := handlers.DecodeJSON(r, &req); err != nil {
+		err = fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
 
-	if req.Prompt == "" {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, fmt.Errorf("%w: prompt is required", ErrInvalidRequest))
+	exec, err := h.prepareChat(&req)
+	if req.DryRun || isDryRun(r) {
+		handlers.RespondJSON(w, http.StatusOK, newDryRunReport(exec, err))
 		return
 	}
-
-	cfg := config.DefaultAgentConfig()
-	cfg.Merge(&req.Config)
-
-	a, err := agent.New(&cfg)
 	if err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, fmt.Errorf("%w: %v", ErrInvalidConfig, err))
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
 
-	chunks, err := a.ChatStream(r.Context(), req.Prompt)
+	ctx, span := startSpan(r.Context(), exec)
+	defer span.End()
+
+	chunks, err := exec.agent.ChatStream(ctx, exec.prompt)
 	if err != nil {
+		recordError(span, err)
 		handlers.RespondError(w, h.logger, http.StatusInternalServerError, fmt.Errorf("%w: %v", ErrExecution, err))
 		return
 	}
 
-	h.writeSSEStream(w, r, chunks)
+	h.writeSSEStream(w, r.WithContext(ctx), chunks)
 }
 
 func (h *Handler) VisionStream(w http.ResponseWriter, r *http.Request) {
-	form, err := ParseVisionForm(r, maxFormMemory)
+	exec, err := h.prepareVision(r)
+	if isDryRun(r) {
+		handlers.RespondJSON(w, http.StatusOK, newDryRunReport(exec, err))
+		return
+	}
 	if err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, fmt.Errorf("%w: %v", ErrInvalidRequest, err))
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
 
-	cfg := config.DefaultAgentConfig()
-	cfg.Merge(&form.Config)
+	ctx, span := startSpan(r.Context(), exec)
+	defer span.End()
 
-	a, err := agent.New(&cfg)
+	chunks, err := exec.agent.VisionStream(ctx, exec.prompt, exec.images)
 	if err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, fmt.Errorf("%w: %v", ErrInvalidConfig, err))
+		recordError(span, err)
+		handlers.RespondError(w, h.logger, http.StatusInternalServerError, fmt.Errorf("%w: %v", ErrExecution, err))
 		return
 	}
 
-	chunks, err := a.VisionStream(r.Context(), form.Prompt, form.Images)
+	h.writeSSEStream(w, r.WithContext(ctx), chunks)
+}
+
+// execution is the resolved outcome of the pre-execution pipeline.
+// It is shared by the streaming and dry-run paths so both observe
+// identical parse, merge, and validation decisions.
+type execution struct {
+	protocol string
+	template string
+	config   config.AgentConfig
+	agent    agent.Agent
+	prompt   string
+	images   []string
+}
+
+func (h *Handler) prepareChat(req *ChatStreamRequest) (*execution, error) {
+	exec := &execution{protocol: "chat", template: req.Template, prompt: req.Prompt}
+
+	if req.Template != "" {
+		if req.Prompt != "" {
+			return exec, fmt.Errorf("%w: prompt and template are mutually exclusive", ErrInvalidRequest)
+		}
+
+		prompt, err := h.templates.Render(req.Template, req.Variables)
+		if err != nil {
+			return exec, fmt.Errorf("%w: %w", ErrTemplate, err)
+		}
+		exec.prompt = prompt
+	}
+
+	if exec.prompt == "" {
+		return exec, fmt.Errorf("%w: prompt is required", ErrInvalidRequest)
+	}
+
+	return exec, exec.resolve(&h.defaults, &req.Config)
+}
+
+func (h *Handler) prepareVision(r *http.Request) (*execution, error) {
+	exec := &execution{protocol: "vision"}
+
+	form, err := ParseVisionForm(r, maxFormMemory, h.defaults.maxImages(), h.defaults.MaxImageSize)
 	if err != nil {
-		handlers.RespondError(w, h.logger, http.StatusInternalServerError, fmt.Errorf("%w: %v", ErrExecution, err))
-		return
+		return exec, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+
+	exec.prompt = form.Prompt
+	exec.images = form.Images
+
+	return exec, exec.resolve(&h.defaults, &form.Config)
+}
+
+// resolve merges the client's overlay over the server defaults and builds
+// the agent. The merged model is checked against the defaults' allow-list,
+// so a request that names no model is held to it through the default, and
+// is rejected when there is no default to fall back on.
+func (e *execution) resolve(defaults *Defaults, overlay *config.AgentConfig) error {
+	e.config = defaults.agentConfig()
+	e.config.Merge(overlay)
+
+	var name string
+	if e.config.Model != nil {
+		name = e.config.Model.Name
+	}
+	if !defaults.allows(name) {
+		if name == "" {
+			return fmt.Errorf("%w: no model named", ErrModelNotAllowed)
+		}
+		return fmt.Errorf("%w: %q", ErrModelNotAllowed, name)
+	}
+
+	a, err := agent.New(&e.config)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
 	}
 
-	h.writeSSEStream(w, r, chunks)
+	e.agent = a
+	return nil
 }
 
 func (h *Handler) writeSSEStream(w http.ResponseWriter, r *http.Request, stream <-chan *response.StreamingChunk) {
@@ -101,6 +191,7 @@ func (h *Handler) writeSSEStream(w http.ResponseWriter, r *http.Request, stream
 
 	for chunk := range stream {
 		if chunk.Error != nil {
+			recordError(trace.SpanFromContext(r.Context()), chunk.Error)
 			data, _ := json.Marshal(map[string]string{"error": chunk.Error.Error()})
 			fmt.Fprintf(w, "data: %s\n\n", data)
 			if f, ok := w.(http.Flusher); ok {
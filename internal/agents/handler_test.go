@@ -0,0 +1,198 @@
+package agents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeTemplates map[string]string
+
+func (f fakeTemplates) Render(name string, variables map[string]any) (string, error) {
+	if prompt, ok := f[name]; ok {
+		return prompt, nil
+	}
+	return "", errors.New("not found")
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func newTestHandler(defaults Defaults) *Handler {
+	return NewHandler(discardLogger(), fakeTemplates{}, defaults)
+}
+
+func dryRun(t *testing.T, h *Handler, body string) DryRunReport {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ChatStream(rec, httptest.NewRequest("POST", "/chat", strings.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 for a dry run; body %s", rec.Code, rec.Body)
+	}
+	var report DryRunReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	return report
+}
+
+func TestChatDryRun(t *testing.T) {
+	defaults := Defaults{
+		BaseURL:       "http://ollama:11434",
+		Model:         "llama3",
+		Timeout:       time.Minute,
+		AllowedModels: []string{"llama3", "gemma3"},
+	}
+
+	tests := []struct {
+		name     string
+		defaults Defaults
+		body     string
+		status   int
+		baseURL  string
+		model    string
+		errText  string
+	}{
+		{
+			name:    "untouched",
+			body:    `{"prompt":"hi","dry_run":true,"config":{"provider":{"base_url":"http://other:11434"},"model":{"name":"gemma3"}}}`,
+			status:  http.StatusOK,
+			baseURL: "http://other:11434",
+			model:   "gemma3",
+		},
+		{
+			name:    "filled from server defaults",
+			body:    `{"prompt":"hi","dry_run":true}`,
+			status:  http.StatusOK,
+			baseURL: "http://ollama:11434",
+			model:   "llama3",
+		},
+		{
+			name:     "no model with allowed list and no default",
+			defaults: Defaults{AllowedModels: []string{"llama3"}},
+			body:     `{"prompt":"hi","dry_run":true}`,
+			status:   http.StatusBadRequest,
+			baseURL:  "http://localhost:11434",
+			errText:  "model not allowed: no model named",
+		},
+		{
+			name:     "no model with allowed list and an allowed default",
+			defaults: Defaults{Model: "llama3", AllowedModels: []string{"llama3"}},
+			body:     `{"prompt":"hi","dry_run":true}`,
+			status:   http.StatusOK,
+			baseURL:  "http://localhost:11434",
+			model:    "llama3",
+		},
+		{
+			name:     "no model and no allowed list",
+			defaults: Defaults{AllowedModels: []string{}},
+			body:     `{"prompt":"hi","dry_run":true}`,
+			status:   http.StatusOK,
+			baseURL:  "http://localhost:11434",
+		},
+		{
+			name:    "rejected model",
+			body:    `{"prompt":"hi","dry_run":true,"config":{"model":{"name":"gpt-x"}}}`,
+			status:  http.StatusBadRequest,
+			baseURL: "http://ollama:11434",
+			model:   "gpt-x",
+			errText: `model not allowed: "gpt-x"`,
+		},
+		{
+			name:    "rejected request",
+			body:    `{"dry_run":true}`,
+			status:  http.StatusBadRequest,
+			errText: "invalid request: prompt is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := defaults
+			if tt.defaults.AllowedModels != nil {
+				d = tt.defaults
+			}
+			report := dryRun(t, newTestHandler(d), tt.body)
+
+			if report.Status != tt.status {
+				t.Errorf("Status = %d, want %d", report.Status, tt.status)
+			}
+			if report.BaseURL != tt.baseURL {
+				t.Errorf("BaseURL = %q, want %q", report.BaseURL, tt.baseURL)
+			}
+			if report.Model != tt.model {
+				t.Errorf("Model = %q, want %q", report.Model, tt.model)
+			}
+			if report.Error != tt.errText {
+				t.Errorf("Error = %q, want %q", report.Error, tt.errText)
+			}
+		})
+	}
+}
+
+func TestChatRejectsDisallowedModel(t *testing.T) {
+	h := newTestHandler(Defaults{AllowedModels: []string{"llama3"}})
+
+	rec := httptest.NewRecorder()
+	body := `{"prompt":"hi","config":{"model":{"name":"gpt-x"}}}`
+	h.ChatStream(rec, httptest.NewRequest("POST", "/chat", strings.NewReader(body)))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestVisionDryRunImageLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults Defaults
+		images   int
+		status   int
+	}{
+		{"within limits", Defaults{MaxImages: 2, MaxImageSize: 1024}, 2, http.StatusOK},
+		{"too many images", Defaults{MaxImages: 1}, 2, http.StatusBadRequest},
+		{"image too large", Defaults{MaxImageSize: 8}, 1, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body strings.Builder
+			form := multipart.NewWriter(&body)
+			form.WriteField("config", "{}")
+			form.WriteField("prompt", "describe")
+			for i := range tt.images {
+				header := textproto.MIMEHeader{}
+				header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="images[]"; filename="%d.png"`, i))
+				header.Set("Content-Type", "image/png")
+				part, _ := form.CreatePart(header)
+				part.Write([]byte("0123456789abcdef"))
+			}
+			form.Close()
+
+			req := httptest.NewRequest("POST", "/vision", strings.NewReader(body.String()))
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			req.Header.Set(DryRunHeader, "true")
+			rec := httptest.NewRecorder()
+			newTestHandler(tt.defaults).VisionStream(rec, req)
+
+			var report DryRunReport
+			if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+				t.Fatalf("decode report: %v", err)
+			}
+			if report.Status != tt.status {
+				t.Errorf("Status = %d, want %d (%s)", report.Status, tt.status, report.Error)
+			}
+		})
+	}
+}
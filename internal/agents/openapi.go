@@ -1,74 +1,100 @@
 package agents
 
-import "github.com/JaimeStill/go-lit/pkg/openapi"
+import (
+	"github.com/JaimeStill/go-agents/pkg/response"
+	"github.com/JaimeStill/go-lit/pkg/openapi"
+)
+
+var sseHeaders = openapi.Headers(
+	openapi.HeaderString("Cache-Control", "no-cache for event streams"),
+)
+
+var dryRunParam = openapi.HeaderParam(DryRunHeader, "Set to true to validate and estimate the request without executing it", false)
 
 var Spec = struct {
-	ChatStream   *openapi.Operation
-	VisionStream *openapi.Operation
+	ChatStream *openapi.Operation
 }{
-	ChatStream: &openapi.Operation{
-		Summary:     "Stream chat response",
-		Description: "Execute a chat prompt and stream the response via SSE",
-		RequestBody: openapi.RequestBodyJSON("ChatStreamRequest", true),
-		Responses: map[int]*openapi.Response{
-			200: {
-				Description: "SSE stream of chat response chunks",
-				Content: map[string]*openapi.MediaType{
-					"text/event-stream": {},
-				},
-			},
-			400: openapi.ResponseJSON("Invalid request", "Error"),
-			500: openapi.ResponseJSON("Execution error", "Error"),
-		},
-	},
-	VisionStream: &openapi.Operation{
-		Summary:     "Stream vision response",
-		Description: "Execute a vision prompt with images and stream the response via SSE",
-		RequestBody: &openapi.RequestBody{
+	ChatStream: openapi.NewOperation("Stream chat response").
+		ID("chatStream").
+		Description("Execute a chat prompt and stream the response via SSE. When dry_run is set, returns a DryRunReport instead of executing.").
+		Param(dryRunParam).
+		RequestJSON("ChatStreamRequest", true).
+		Response(200, streamResponse("SSE stream of chat response chunks, or a DryRunReport for dry runs")).
+		ResponseJSON(404, "Prompt template not found", "Error").
+		ResponseJSON(422, "Template variables missing or invalid", "Error").
+		ErrorResponses(400, 500).
+		Build(),
+}
+
+// visionStreamOperation documents VisionStream accepting at most maxImages
+// images.
+func visionStreamOperation(maxImages int) *openapi.Operation {
+	return openapi.NewOperation("Stream vision response").
+		ID("visionStream").
+		Description("Execute a vision prompt with images and stream the response via SSE. When X-Dry-Run is set, returns a DryRunReport instead of executing.").
+		Param(dryRunParam).
+		RequestBody(&openapi.RequestBody{
 			Required: true,
 			Content: map[string]*openapi.MediaType{
 				"multipart/form-data": {
 					Schema: &openapi.Schema{
 						Type: "object",
 						Properties: map[string]*openapi.Schema{
-							"config":   {Type: "string", Description: "JSON-encoded AgentConfig"},
-							"prompt":   {Type: "string", Description: "Vision prompt"},
-							"images[]": {Type: "array", Items: &openapi.Schema{Type: "string", Format: "binary"}},
+							"config": {Type: "string", Description: "JSON-encoded AgentConfig"},
+							"prompt": {Type: "string", Description: "Vision prompt"},
+							"images[]": {
+								Type:     "array",
+								Items:    &openapi.Schema{Type: "string", Format: "binary"},
+								MaxItems: &maxImages,
+							},
 						},
 						Required: []string{"config", "prompt", "images[]"},
 					},
 				},
 			},
-		},
-		Responses: map[int]*openapi.Response{
-			200: {
-				Description: "SSE stream of vision response chunks",
-				Content: map[string]*openapi.MediaType{
-					"text/event-stream": {},
-				},
-			},
-			400: openapi.ResponseJSON("Invalid request", "Error"),
-			500: openapi.ResponseJSON("Execution error", "Error"),
-		},
-	},
+		}).
+		Response(200, streamResponse("SSE stream of vision response chunks, or a DryRunReport for dry runs")).
+		ErrorResponses(400, 500).
+		Build()
+}
+
+// streamResponse documents a 200 response that is either an SSE stream of
+// chunks or, for dry runs, a JSON DryRunReport.
+func streamResponse(description string) *openapi.Response {
+	r := openapi.ResponseSSE(description, "StreamingChunk")
+	r.Headers = sseHeaders
+	r.Content["application/json"] = &openapi.MediaType{Schema: openapi.SchemaRef("DryRunReport")}
+	return r
 }
 
 var Schemas = map[string]*openapi.Schema{
-	"ChatStreamRequest": {
-		Type:     "object",
-		Required: []string{"prompt"},
-		Properties: map[string]*openapi.Schema{
-			"config": {
-				Type:        "object",
-				Description: "Agent configuration (go-agents AgentConfig)",
-			},
-			"prompt": {Type: "string", Description: "User prompt"},
-		},
-	},
-	"Error": {
-		Type: "object",
-		Properties: map[string]*openapi.Schema{
-			"error": {Type: "string"},
-		},
-	},
+	"ChatStreamRequest": chatStreamRequestSchema(),
+	"DryRunReport":      openapi.SchemaFrom[DryRunReport](),
+	"StreamingChunk":    withDescription(openapi.SchemaFrom[response.StreamingChunk](), "Incremental response chunk. A failed stream ends with an event whose data is an Error."),
+}
+
+// chatStreamRequestSchema documents the chat request. The agent configuration
+// is merged onto the defaults, so none of its nested fields are required.
+func chatStreamRequestSchema() *openapi.Schema {
+	s := withDescription(
+		openapi.SchemaFrom[ChatStreamRequest](),
+		"Exactly one of prompt or template must be provided",
+	)
+	clearRequired(s.Properties["config"])
+	return s
+}
+
+func clearRequired(s *openapi.Schema) {
+	if s == nil {
+		return
+	}
+	s.Required = nil
+	for _, prop := range s.Properties {
+		clearRequired(prop)
+	}
+}
+
+func withDescription(s *openapi.Schema, description string) *openapi.Schema {
+	s.Description = description
+	return s
 }
@@ -10,13 +10,21 @@ import (
 	"strings"
 
 	"github.com/JaimeStill/go-agents/pkg/config"
+	"github.com/JaimeStill/go-lit/pkg/handlers"
 )
 
 type ChatStreamRequest struct {
-	Config config.AgentConfig `json:"config"`
-	Prompt string             `json:"prompt"`
+	Config    config.AgentConfig `json:"config" openapi:"optional,description=Agent configuration (go-agents AgentConfig)"`
+	Prompt    string             `json:"prompt" openapi:"optional,description=User prompt"`
+	Template  string             `json:"template,omitempty" openapi:"description=Name of a stored prompt template rendered as the prompt"`
+	Variables map[string]any     `json:"variables,omitempty" openapi:"description=Values for the template's declared variables"`
+	DryRun    bool               `json:"dry_run,omitempty" openapi:"description=Return a DryRunReport without executing"`
 }
 
+// MaxVisionImages is the number of images accepted by a vision request when
+// Defaults.MaxImages is not set.
+const MaxVisionImages = 4
+
 type VisionForm struct {
 	Config  config.AgentConfig
 	Prompt  string
@@ -25,9 +33,12 @@ type VisionForm struct {
 	Token   string
 }
 
-func ParseVisionForm(r *http.Request, maxMemory int64) (*VisionForm, error) {
+// ParseVisionForm parses a multipart vision request, accepting at most
+// maxImages images of at most maxImageSize bytes each; a zero maxImageSize
+// accepts images of any size.
+func ParseVisionForm(r *http.Request, maxMemory int64, maxImages int, maxImageSize int64) (*VisionForm, error) {
 	if err := r.ParseMultipartForm(maxMemory); err != nil {
-		return nil, fmt.Errorf("parsing multipart form: %w", err)
+		return nil, fmt.Errorf("parsing multipart form: %w", handlers.BodyError(err))
 	}
 
 	configJSON := r.FormValue("config")
@@ -45,9 +56,15 @@ func ParseVisionForm(r *http.Request, maxMemory int64) (*VisionForm, error) {
 	if len(files) == 0 {
 		files = r.MultipartForm.File["images"]
 	}
+	if len(files) > maxImages {
+		return nil, fmt.Errorf("at most %d images are allowed, got %d", maxImages, len(files))
+	}
 
 	images := make([]string, 0, len(files))
 	for _, fh := range files {
+		if maxImageSize > 0 && fh.Size > maxImageSize {
+			return nil, fmt.Errorf("image %s is %d bytes, at most %d are allowed", fh.Filename, fh.Size, maxImageSize)
+		}
 		dataURI, err := fileToDataURI(fh)
 		if err != nil {
 			return nil, fmt.Errorf("processing image %s: %w", fh.Filename, err)
@@ -0,0 +1,107 @@
+package agents
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/go-lit/internal/prompts"
+)
+
+// fakeProvider serves an OpenAI-compatible streaming chat endpoint that
+// echoes the last user message back as a single chunk.
+func fakeProvider(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		var body struct {
+			Messages []struct {
+				Content any `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Messages) == 0 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		prompt := fmt.Sprint(body.Messages[len(body.Messages)-1].Content)
+
+		chunk, _ := json.Marshal(map[string]any{
+			"id":     "1",
+			"object": "chat.completion.chunk",
+			"model":  "fake",
+			"choices": []map[string]any{
+				{"index": 0, "delta": map[string]any{"role": "assistant", "content": "echo: " + prompt}},
+			},
+		})
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "data: %s\n\ndata: [DONE]\n\n", chunk)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestChatTemplateEndToEnd(t *testing.T) {
+	provider := fakeProvider(t)
+
+	store := prompts.NewStore()
+	_, err := store.Create(prompts.TemplateCommand{
+		Name: "explain",
+		Body: "Explain {{.Topic | upper}}",
+		Variables: []prompts.Variable{
+			{Name: "Topic", Type: prompts.TypeString, Required: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	h := NewHandler(discardLogger(), store, Defaults{BaseURL: provider.URL, Model: "fake"})
+
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		contains []string
+	}{
+		{
+			name:     "rendered and streamed",
+			body:     `{"template":"explain","variables":{"Topic":"tls"}}`,
+			status:   http.StatusOK,
+			contains: []string{"echo: Explain TLS", "data: [DONE]"},
+		},
+		{
+			name:     "missing variable",
+			body:     `{"template":"explain"}`,
+			status:   http.StatusUnprocessableEntity,
+			contains: []string{"variables.Topic"},
+		},
+		{
+			name:     "unknown template",
+			body:     `{"template":"nope"}`,
+			status:   http.StatusNotFound,
+			contains: []string{"template not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ChatStream(rec, httptest.NewRequest("POST", "/chat", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d; body %s", rec.Code, tt.status, rec.Body)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(rec.Body.String(), want) {
+					t.Errorf("body %q does not contain %q", rec.Body, want)
+				}
+			}
+		})
+	}
+}
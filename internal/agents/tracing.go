@@ -0,0 +1,32 @@
+package agents
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/JaimeStill/go-lit/internal/agents"
+
+// startSpan starts a span around an upstream agent call as a child of the
+// request's span, using the provider that created it. Untraced requests get
+// a non-recording span.
+func startSpan(ctx context.Context, exec *execution) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{attribute.String("agent.name", exec.config.Name)}
+	if p := exec.config.Provider; p != nil {
+		attrs = append(attrs, attribute.String("agent.provider", p.Name))
+	}
+
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName)
+	return tracer.Start(ctx, "agent."+exec.protocol,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -1,33 +1,100 @@
 package api
 
 import (
+	"fmt"
 	"log/slog"
 	"net/http"
 
 	"github.com/JaimeStill/go-lit/internal/config"
+	"github.com/JaimeStill/go-lit/internal/prompts"
 	"github.com/JaimeStill/go-lit/pkg/middleware"
 	"github.com/JaimeStill/go-lit/pkg/module"
 	"github.com/JaimeStill/go-lit/pkg/openapi"
+	"github.com/JaimeStill/go-lit/pkg/registry"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // NewModule creates the API module with domain handlers and middleware.
-func NewModule(cfg *config.Config, logger *slog.Logger) (*module.Module, error) {
+// It resolves the configuration, logger, tracer provider, reloadable
+// middleware, and domain services from the registry.
+func NewModule(reg *registry.Registry) (*module.Module, error) {
+	cfg, err := registry.Resolve[*config.Config](reg)
+	if err != nil {
+		return nil, err
+	}
+	logger, err := registry.Resolve[*slog.Logger](reg)
+	if err != nil {
+		return nil, err
+	}
+	promptStore, err := registry.Resolve[*prompts.Store](reg)
+	if err != nil {
+		return nil, err
+	}
+	tp, err := registry.Resolve[trace.TracerProvider](reg)
+	if err != nil {
+		return nil, err
+	}
+	reloadable, err := registry.Resolve[*Reloadable](reg)
+	if err != nil {
+		return nil, err
+	}
+
 	spec := openapi.NewSpec(cfg.API.OpenAPI.Title, cfg.Version)
 	spec.SetDescription(cfg.API.OpenAPI.Description)
 	spec.AddServer(cfg.Domain)
 
 	mux := http.NewServeMux()
-	registerRoutes(mux, spec, cfg, logger)
-
-	specBytes, err := openapi.MarshalJSON(spec)
+	table, err := registerRoutes(mux, spec, cfg, logger, tp, promptStore, reloadable)
 	if err != nil {
+		return nil, fmt.Errorf("register routes: %w", err)
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+
+	for _, ref := range spec.Operations() {
+		logger.Debug("documented endpoint", "method", ref.Method, "path", ref.Path, "operation", ref.Operation.OperationID)
+	}
+
+	if err := serveSpec(mux, "", spec); err != nil {
 		return nil, err
 	}
-	mux.HandleFunc("GET /openapi.json", openapi.ServeSpec(specBytes))
 
-	m := module.New(cfg.API.BasePath, mux)
-	m.Use(middleware.CORS(&cfg.API.CORS))
+	m := module.New(cfg.API.BasePath, jsonNotFound(mux),
+		module.WithName("api"),
+		module.WithDescription(cfg.API.OpenAPI.Description),
+		module.WithLogger(logger),
+	)
+	m.SetRoutes(table)
+	m.Use(middleware.Recover(logger))
+	m.Use(middleware.RequestID())
+	m.UseNamed("middleware.CORS", reloadable.cors.Middleware())
 	m.Use(middleware.Logger(logger))
+	m.Use(middleware.MaxBytes(cfg.Server.MaxBodySizeBytes()))
+	m.Use(middleware.Timeout(cfg.Server.RequestTimeout.Duration))
 
 	return m, nil
 }
+
+// serveSpec serves spec as openapi.json and openapi.yaml under prefix.
+// Versioned route groups can publish a document per version by serving
+// routes.VersionSpec(spec, version) under the version's prefix.
+// The documents carry precomputed ETags, so ConditionalGet buffers nothing
+// and only marks them no-cache, making clients revalidate on every load.
+func serveSpec(mux *http.ServeMux, prefix string, spec *openapi.Spec) error {
+	revalidate := middleware.ConditionalGet(0, middleware.CacheRule{Prefix: "/", CacheControl: "no-cache"})
+
+	specJSON, err := openapi.MarshalJSON(spec)
+	if err != nil {
+		return err
+	}
+	mux.Handle("GET "+prefix+"/openapi.json", revalidate(openapi.ServeSpec(specJSON)))
+
+	specYAML, err := openapi.MarshalYAML(spec)
+	if err != nil {
+		return err
+	}
+	mux.Handle("GET "+prefix+"/openapi.yaml", revalidate(openapi.ServeSpecYAML(specYAML)))
+	return nil
+}
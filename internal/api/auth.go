@@ -0,0 +1,59 @@
+package api
+
+import (
+	"maps"
+	"net/http"
+
+	"github.com/JaimeStill/go-lit/internal/config"
+	"github.com/JaimeStill/go-lit/pkg/middleware"
+	"github.com/JaimeStill/go-lit/pkg/openapi"
+)
+
+// authentication returns the authentication middleware enabled in cfg and
+// the security schemes they enforce, keyed by scheme name.
+func authentication(cfg *config.APIConfig) ([]func(http.Handler) http.Handler, map[string]*openapi.SecurityScheme, error) {
+	var mw []func(http.Handler) http.Handler
+	schemes := make(map[string]*openapi.SecurityScheme)
+
+	if cfg.Auth.Enabled {
+		mw = append(mw, middleware.BearerAuth(cfg.Auth.Validator()))
+		schemes["bearerAuth"] = openapi.BearerAuth()
+	}
+	if cfg.APIKeys.Enabled {
+		store, err := cfg.APIKeys.Store()
+		if err != nil {
+			return nil, nil, err
+		}
+		mw = append(mw, middleware.APIKey(store))
+		schemes["apiKeyAuth"] = openapi.APIKeyHeader(middleware.APIKeyHeader)
+	}
+	return mw, schemes, nil
+}
+
+// documentSecurity declares schemes as a single document-level requirement,
+// since every enabled scheme must pass, and adds an Unauthorized response to
+// each operation that does not opt out of it.
+func documentSecurity(spec *openapi.Spec, schemes map[string]*openapi.SecurityScheme) {
+	if len(schemes) == 0 {
+		return
+	}
+
+	req := make(openapi.SecurityRequirement, len(schemes))
+	for name := range schemes {
+		req[name] = []string{}
+	}
+	spec.Components.AddSecuritySchemes(schemes)
+	spec.AddSecurity(req)
+	spec.Components.AddResponses(map[string]*openapi.Response{
+		"Unauthorized": openapi.ResponseJSON("Missing or invalid credentials", "Error"),
+	})
+
+	for _, ref := range spec.Operations() {
+		op := ref.Operation
+		if op.Security != nil && len(op.Security) == 0 {
+			continue
+		}
+		op.Responses = maps.Clone(op.Responses)
+		op.Responses[401] = openapi.ResponseRef("Unauthorized")
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/JaimeStill/go-lit/pkg/handlers"
+)
+
+// jsonNotFound serves mux, answering requests that match no route with a JSON
+// error instead of the mux's plain text 404. The router's NotFound handler is
+// never reached once the module prefix matches, so the module answers its own
+// misses. Requests matching a route under another method still receive the
+// mux's 405 with its Allow header.
+func jsonNotFound(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		mux.ServeHTTP(&notFoundWriter{ResponseWriter: w}, r)
+	})
+}
+
+// notFoundWriter replaces a 404 response with a JSON error, discarding the
+// body written with it.
+type notFoundWriter struct {
+	http.ResponseWriter
+	replaced bool
+}
+
+func (w *notFoundWriter) WriteHeader(status int) {
+	if status != http.StatusNotFound {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.replaced = true
+	handlers.RespondJSON(w.ResponseWriter, http.StatusNotFound, map[string]string{"error": "not found"})
+}
+
+func (w *notFoundWriter) Write(b []byte) (int, error) {
+	if w.replaced {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *notFoundWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
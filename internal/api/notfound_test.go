@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJSONNotFound(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /prompts", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := jsonNotFound(mux)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"matched route", "GET", "/prompts", http.StatusOK, "", ""},
+		{"unknown path", "GET", "/nothing", http.StatusNotFound, "application/json", "{\"error\":\"not found\"}\n"},
+		{"other method", "DELETE", "/prompts", http.StatusMethodNotAllowed, "text/plain; charset=utf-8", "Method Not Allowed\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			if got := rec.Body.String(); got != tt.body {
+				t.Errorf("body = %q, want %q", got, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"github.com/JaimeStill/go-lit/internal/config"
+	"github.com/JaimeStill/go-lit/pkg/middleware"
+)
+
+// Reloadable holds the API middleware whose settings apply without a
+// restart: CORS and rate limiting. Providing one to the registry lets the
+// caller apply a reloaded configuration to the running module.
+type Reloadable struct {
+	cors      *middleware.Swappable
+	rateLimit *middleware.Swappable
+}
+
+// NewReloadable creates the reloadable middleware from cfg.
+func NewReloadable(cfg *config.APIConfig) *Reloadable {
+	return &Reloadable{
+		cors:      middleware.NewSwappable(middleware.CORS(&cfg.CORS)),
+		rateLimit: middleware.NewSwappable(middleware.RateLimit(&cfg.RateLimit)),
+	}
+}
+
+// Apply rebuilds the CORS and rate limit middleware from cfg. Rate limit
+// buckets start full again.
+func (r *Reloadable) Apply(cfg *config.APIConfig) {
+	r.cors.Swap(middleware.CORS(&cfg.CORS))
+	r.rateLimit.Swap(middleware.RateLimit(&cfg.RateLimit))
+}
@@ -3,20 +3,79 @@ package api
 import (
 	"log/slog"
 	"net/http"
+	"slices"
 
 	"github.com/JaimeStill/go-lit/internal/agents"
 	"github.com/JaimeStill/go-lit/internal/config"
+	"github.com/JaimeStill/go-lit/internal/prompts"
+	"github.com/JaimeStill/go-lit/pkg/middleware"
 	"github.com/JaimeStill/go-lit/pkg/openapi"
 	"github.com/JaimeStill/go-lit/pkg/routes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func registerRoutes(mux *http.ServeMux, spec *openapi.Spec, cfg *config.Config, logger *slog.Logger) {
-	handler := agents.NewHandler(logger)
+func registerRoutes(mux *http.ServeMux, spec *openapi.Spec, cfg *config.Config, logger *slog.Logger, tp trace.TracerProvider, promptStore *prompts.Store, reloadable *Reloadable) (routes.RouteTable, error) {
+	promptsHandler := prompts.NewHandler(promptStore, logger, cfg.API.Pagination)
+	agentsHandler := agents.NewHandler(logger, promptStore, agentDefaults(&cfg.Agents))
 
-	routes.Register(
-		mux,
-		cfg.API.BasePath,
-		spec,
-		handler.Routes(),
-	)
+	agentsGroup := agentsHandler.Routes()
+	promptsGroup := promptsHandler.Routes()
+	all := []*routes.Group{&agentsGroup, &promptsGroup}
+
+	if cfg.Telemetry.Enabled {
+		tracing := middleware.Tracing(tp)
+		for _, g := range all {
+			g.Middleware = slices.Insert(g.Middleware, 0, tracing)
+		}
+	}
+
+	auth, schemes, err := authentication(&cfg.API)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range all {
+		g.Middleware = append(g.Middleware, auth...)
+	}
+
+	// Rate limiting runs after authentication so buckets are keyed by the
+	// authenticated caller rather than by anything the client can vary.
+	agentsGroup.Middleware = append(agentsGroup.Middleware, reloadable.rateLimit.Middleware())
+
+	// Validation runs after authentication and rate limiting so bodies are
+	// only decoded for callers allowed through, and before the concurrency
+	// limit so invalid requests never hold a slot.
+	validate := middleware.ValidateRequests(spec)
+	for _, g := range all {
+		g.Middleware = append(g.Middleware, validate)
+	}
+
+	if cfg.API.Concurrency.Enabled {
+		agentsGroup.Middleware = append(agentsGroup.Middleware, middleware.ConcurrencyLimit(
+			cfg.API.Concurrency.MaxInFlight,
+			cfg.API.Concurrency.QueueTimeout.Duration,
+		))
+	}
+
+	groups := []routes.Group{agentsGroup, promptsGroup}
+	for _, route := range routes.Disabled(groups...) {
+		logger.Info("route disabled by feature flag", "route", route)
+	}
+
+	if err := routes.RegisterMerged(mux, cfg.API.BasePath, spec, groups...); err != nil {
+		return nil, err
+	}
+	documentSecurity(spec, schemes)
+	return routes.Table(groups...), nil
+}
+
+// agentDefaults returns the execution defaults configured in cfg.
+func agentDefaults(cfg *config.AgentsConfig) agents.Defaults {
+	return agents.Defaults{
+		BaseURL:       cfg.BaseURL,
+		Model:         cfg.Model,
+		Timeout:       cfg.RequestTimeout.Duration,
+		MaxImages:     cfg.MaxImages,
+		MaxImageSize:  cfg.MaxImageSizeBytes(),
+		AllowedModels: cfg.AllowedModels,
+	}
 }
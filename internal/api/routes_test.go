@@ -0,0 +1,68 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/go-lit/internal/config"
+	"github.com/JaimeStill/go-lit/internal/prompts"
+	"github.com/JaimeStill/go-lit/pkg/openapi"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestAuthenticationRunsBeforeValidation(t *testing.T) {
+	t.Setenv(config.EnvServiceEnv, "")
+	t.Setenv(config.EnvConfigStrict, "")
+
+	path := filepath.Join(t.TempDir(), config.BaseConfigFile)
+	body := "[api.auth]\nenabled = true\n\n[[api.auth.tokens]]\nsubject = \"svc\"\ntoken = \"secret\"\n"
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v", err)
+	}
+
+	mux := http.NewServeMux()
+	spec := openapi.NewSpec("test", "0.1.0")
+	logger := slog.New(slog.DiscardHandler)
+	if _, err := registerRoutes(mux, spec, cfg, logger, noop.NewTracerProvider(), prompts.NewStore(), NewReloadable(&cfg.API)); err != nil {
+		t.Fatalf("registerRoutes() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		status int
+		body   string
+	}{
+		{"unauthenticated", "", http.StatusUnauthorized, "missing bearer token"},
+		{"authenticated", "secret", http.StatusBadRequest, "request validation failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/prompts", strings.NewReader(`{"name": 5}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if !strings.Contains(rec.Body.String(), tt.body) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/JaimeStill/go-lit/pkg/envvar"
+	"github.com/JaimeStill/go-lit/pkg/middleware"
+)
+
+const (
+	// EnvAdminMaintenanceRetryAfter overrides the Retry-After sent during maintenance.
+	EnvAdminMaintenanceRetryAfter = "ADMIN_MAINTENANCE_RETRY_AFTER"
+
+	// EnvAdminDebugConfig overrides whether GET /debug/config is served.
+	EnvAdminDebugConfig = "ADMIN_DEBUG_CONFIG"
+)
+
+var adminAuthEnv = &middleware.AuthEnv{
+	Enabled: "ADMIN_AUTH_ENABLED",
+	Tokens:  "ADMIN_AUTH_TOKENS",
+}
+
+// AdminConfig contains configuration for the administrative routes under
+// /admin, which are registered only while Auth is enabled and require one of
+// its bearer tokens. MaintenanceRetryAfter is how long clients are told to
+// wait, as a duration such as "5m", while maintenance mode is on.
+// DebugConfig additionally serves the effective configuration, with secrets
+// redacted, at /debug/config.
+type AdminConfig struct {
+	Auth                  middleware.AuthConfig `toml:"auth"`
+	MaintenanceRetryAfter Duration              `toml:"maintenance_retry_after"`
+	DebugConfig           bool                  `toml:"debug_config"`
+}
+
+// Finalize applies defaults, loads environment overrides, and validates the admin configuration.
+func (c *AdminConfig) Finalize() error {
+	c.loadDefaults()
+	if err := c.loadEnv(); err != nil {
+		return err
+	}
+
+	if err := c.Auth.Finalize(adminAuthEnv); err != nil {
+		return fmt.Errorf("auth: %w", err)
+	}
+	return c.validate()
+}
+
+// Merge applies values from overlay configuration that differ from zero values.
+func (c *AdminConfig) Merge(overlay *AdminConfig) {
+	c.Auth.Merge(&overlay.Auth)
+	c.DebugConfig = overlay.DebugConfig
+
+	c.MaintenanceRetryAfter.Merge(overlay.MaintenanceRetryAfter)
+}
+
+func (c *AdminConfig) loadDefaults() {
+	c.MaintenanceRetryAfter.SetDefault(5 * time.Minute)
+}
+
+func (c *AdminConfig) loadEnv() error {
+	var r envvar.Reader
+
+	if v := r.Get(EnvAdminMaintenanceRetryAfter); v != "" {
+		if err := parseEnvDuration(&c.MaintenanceRetryAfter, EnvAdminMaintenanceRetryAfter, v); err != nil {
+			return fmt.Errorf("invalid maintenance_retry_after: %w", err)
+		}
+	}
+	if v := r.Get(EnvAdminDebugConfig); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			c.DebugConfig = enabled
+		}
+	}
+
+	return r.Err()
+}
+
+func (c *AdminConfig) validate() error {
+	if c.MaintenanceRetryAfter.Duration < 0 {
+		return fmt.Errorf("invalid maintenance_retry_after: %s (must not be negative)", c.MaintenanceRetryAfter)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JaimeStill/go-lit/pkg/envvar"
+)
+
+const (
+	// EnvAgentsBaseURL overrides the default provider base URL.
+	EnvAgentsBaseURL = "AGENTS_BASE_URL"
+
+	// EnvAgentsModel overrides the default model.
+	EnvAgentsModel = "AGENTS_MODEL"
+
+	// EnvAgentsRequestTimeout overrides the default provider request timeout.
+	EnvAgentsRequestTimeout = "AGENTS_REQUEST_TIMEOUT"
+
+	// EnvAgentsMaxImages overrides the number of images accepted by a vision request.
+	EnvAgentsMaxImages = "AGENTS_MAX_IMAGES"
+
+	// EnvAgentsMaxImageSize overrides the size limit of each vision image.
+	EnvAgentsMaxImageSize = "AGENTS_MAX_IMAGE_SIZE"
+
+	// EnvAgentsAllowedModels overrides the models clients may select (comma-separated).
+	EnvAgentsAllowedModels = "AGENTS_ALLOWED_MODELS"
+)
+
+// AgentsConfig contains the server defaults for chat and vision executions,
+// applied before the agent configuration sent by the client.
+// BaseURL and Model are the default provider base URL and model; when empty,
+// the go-agents defaults are kept. RequestTimeout bounds each provider
+// request unless the client sets its own. MaxImages and MaxImageSize limit
+// the images of a vision request, the size written as a byte count with an
+// optional B, KB, MB, or GB suffix such as "10MB"; "0" disables the size
+// limit. AllowedModels, when not empty, lists the only models a request may
+// name; requests naming none use Model.
+type AgentsConfig struct {
+	BaseURL        string   `toml:"base_url"`
+	Model          string   `toml:"model"`
+	RequestTimeout Duration `toml:"request_timeout"`
+	MaxImages      int      `toml:"max_images"`
+	MaxImageSize   string   `toml:"max_image_size"`
+	AllowedModels  []string `toml:"allowed_models"`
+}
+
+// MaxImageSizeBytes parses and returns the maximum vision image size in bytes.
+func (c *AgentsConfig) MaxImageSizeBytes() int64 {
+	n, _ := parseByteSize(c.MaxImageSize)
+	return n
+}
+
+// Finalize applies defaults, loads environment overrides, and validates the
+// agents configuration.
+func (c *AgentsConfig) Finalize() error {
+	c.loadDefaults()
+
+	var errs fieldErrors
+	errs.addSection("", c.loadEnv())
+	errs.addSection("", c.validate())
+	return errs.err()
+}
+
+// Merge applies values from overlay configuration that differ from zero values.
+func (c *AgentsConfig) Merge(overlay *AgentsConfig) {
+	if overlay.BaseURL != "" {
+		c.BaseURL = overlay.BaseURL
+	}
+	if overlay.Model != "" {
+		c.Model = overlay.Model
+	}
+	c.RequestTimeout.Merge(overlay.RequestTimeout)
+	if overlay.MaxImages > 0 {
+		c.MaxImages = overlay.MaxImages
+	}
+	if overlay.MaxImageSize != "" {
+		c.MaxImageSize = overlay.MaxImageSize
+	}
+	if len(overlay.AllowedModels) > 0 {
+		c.AllowedModels = overlay.AllowedModels
+	}
+}
+
+func (c *AgentsConfig) loadDefaults() {
+	c.RequestTimeout.SetDefault(2 * time.Minute)
+	if c.MaxImages == 0 {
+		c.MaxImages = 4
+	}
+	if c.MaxImageSize == "" {
+		c.MaxImageSize = "10MB"
+	}
+}
+
+func (c *AgentsConfig) loadEnv() error {
+	var r envvar.Reader
+	var errs fieldErrors
+
+	if v := r.Get(EnvAgentsBaseURL); v != "" {
+		c.BaseURL = v
+	}
+	if v := r.Get(EnvAgentsModel); v != "" {
+		c.Model = v
+	}
+	if v := r.Get(EnvAgentsRequestTimeout); v != "" {
+		errs.add("request_timeout", parseEnvDuration(&c.RequestTimeout, EnvAgentsRequestTimeout, v))
+	}
+	if v := r.Get(EnvAgentsMaxImages); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.MaxImages = n
+		}
+	}
+	if v := r.Get(EnvAgentsMaxImageSize); v != "" {
+		c.MaxImageSize = v
+	}
+	if v := r.Get(EnvAgentsAllowedModels); v != "" {
+		models := strings.Split(v, ",")
+		c.AllowedModels = make([]string, 0, len(models))
+		for _, model := range models {
+			if trimmed := strings.TrimSpace(model); trimmed != "" {
+				c.AllowedModels = append(c.AllowedModels, trimmed)
+			}
+		}
+	}
+
+	errs.add("", r.Err())
+	return errs.err()
+}
+
+func (c *AgentsConfig) validate() error {
+	var errs fieldErrors
+
+	if c.BaseURL != "" {
+		if u, err := url.Parse(c.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.add("base_url", fmt.Errorf("%q must be an http or https URL", c.BaseURL))
+		}
+	}
+	if c.Model != "" && len(c.AllowedModels) > 0 && !slices.Contains(c.AllowedModels, c.Model) {
+		errs.add("model", fmt.Errorf("%q must be one of allowed_models", c.Model))
+	}
+	if c.RequestTimeout.Duration <= 0 {
+		errs.add("request_timeout", fmt.Errorf("%s must be positive", c.RequestTimeout))
+	}
+	if c.MaxImages < 1 {
+		errs.add("max_images", fmt.Errorf("%d must be at least 1", c.MaxImages))
+	}
+	if _, err := parseByteSize(c.MaxImageSize); err != nil {
+		errs.add("max_image_size", err)
+	}
+
+	return errs.err()
+}
@@ -1,11 +1,12 @@
 package config
 
 import (
-	"fmt"
-	"os"
+	"maps"
 
+	"github.com/JaimeStill/go-lit/pkg/envvar"
 	"github.com/JaimeStill/go-lit/pkg/middleware"
 	"github.com/JaimeStill/go-lit/pkg/openapi"
+	"github.com/JaimeStill/go-lit/pkg/pagination"
 )
 
 var corsEnv = &middleware.CORSEnv{
@@ -17,30 +18,70 @@ var corsEnv = &middleware.CORSEnv{
 	MaxAge:           "API_CORS_MAX_AGE",
 }
 
+var rateLimitEnv = &middleware.RateLimitEnv{
+	Enabled: "API_RATE_LIMIT_ENABLED",
+	Rate:    "API_RATE_LIMIT_RATE",
+	Burst:   "API_RATE_LIMIT_BURST",
+	MaxKeys: "API_RATE_LIMIT_MAX_KEYS",
+}
+
+var concurrencyEnv = &middleware.ConcurrencyEnv{
+	Enabled:      "API_CONCURRENCY_ENABLED",
+	MaxInFlight:  "API_CONCURRENCY_MAX_IN_FLIGHT",
+	QueueTimeout: "API_CONCURRENCY_QUEUE_TIMEOUT",
+}
+
+var authEnv = &middleware.AuthEnv{
+	Enabled: "API_AUTH_ENABLED",
+	Tokens:  "API_AUTH_TOKENS",
+}
+
+var apiKeyEnv = &middleware.APIKeyEnv{
+	Enabled:  "API_KEYS_ENABLED",
+	KeysFile: "API_KEYS_FILE",
+}
+
 var openAPIEnv = &openapi.ConfigEnv{
 	Title:       "API_OPENAPI_TITLE",
 	Description: "API_OPENAPI_DESCRIPTION",
 }
 
+var paginationEnv = &pagination.ConfigEnv{
+	DefaultPageSize: "API_PAGINATION_DEFAULT_PAGE_SIZE",
+	MaxPageSize:     "API_PAGINATION_MAX_PAGE_SIZE",
+}
+
 // APIConfig contains API module configuration.
+// API_FEATURES overrides individual feature flags with a comma-separated list
+// of names, each optionally followed by =true or =false.
 type APIConfig struct {
-	BasePath string                `toml:"base_path"`
-	CORS     middleware.CORSConfig `toml:"cors"`
-	OpenAPI  openapi.Config        `toml:"openapi"`
+	BasePath    string                       `toml:"base_path"`
+	CORS        middleware.CORSConfig        `toml:"cors"`
+	RateLimit   middleware.RateLimitConfig   `toml:"rate_limit"`
+	Concurrency middleware.ConcurrencyConfig `toml:"concurrency"`
+	Auth        middleware.AuthConfig        `toml:"auth"`
+	APIKeys     middleware.APIKeyConfig      `toml:"api_keys"`
+	OpenAPI     openapi.Config               `toml:"openapi"`
+	Pagination  pagination.Config            `toml:"pagination"`
+	Features    Features                     `toml:"features"`
 }
 
-// Finalize applies defaults, loads environment overrides, and validates nested configurations.
+// Finalize applies defaults, loads environment overrides, and validates
+// nested configurations, reporting the problems of every section in a
+// *ValidationError.
 func (c *APIConfig) Finalize() error {
 	c.loadDefaults()
-	c.loadEnv()
 
-	if err := c.CORS.Finalize(corsEnv); err != nil {
-		return fmt.Errorf("cors: %w", err)
-	}
-	if err := c.OpenAPI.Finalize(openAPIEnv); err != nil {
-		return fmt.Errorf("openapi: %w", err)
-	}
-	return nil
+	var errs fieldErrors
+	errs.add("", c.loadEnv())
+	errs.addSection("cors", c.CORS.Finalize(corsEnv))
+	errs.addSection("rate_limit", c.RateLimit.Finalize(rateLimitEnv))
+	errs.addSection("concurrency", c.Concurrency.Finalize(concurrencyEnv))
+	errs.addSection("auth", c.Auth.Finalize(authEnv))
+	errs.addSection("api_keys", c.APIKeys.Finalize(apiKeyEnv))
+	errs.addSection("openapi", c.OpenAPI.Finalize(openAPIEnv))
+	errs.addSection("pagination", c.Pagination.Finalize(paginationEnv))
+	return errs.err()
 }
 
 // Merge applies non-zero values from the overlay configuration.
@@ -49,7 +90,18 @@ func (c *APIConfig) Merge(overlay *APIConfig) {
 		c.BasePath = overlay.BasePath
 	}
 	c.CORS.Merge(&overlay.CORS)
+	c.RateLimit.Merge(&overlay.RateLimit)
+	c.Concurrency.Merge(&overlay.Concurrency)
+	c.Auth.Merge(&overlay.Auth)
+	c.APIKeys.Merge(&overlay.APIKeys)
 	c.OpenAPI.Merge(&overlay.OpenAPI)
+	c.Pagination.Merge(&overlay.Pagination)
+	if len(overlay.Features) > 0 {
+		if c.Features == nil {
+			c.Features = make(Features)
+		}
+		maps.Copy(c.Features, overlay.Features)
+	}
 }
 
 func (c *APIConfig) loadDefaults() {
@@ -58,8 +110,18 @@ func (c *APIConfig) loadDefaults() {
 	}
 }
 
-func (c *APIConfig) loadEnv() {
-	if v := os.Getenv("API_BASE_PATH"); v != "" {
+func (c *APIConfig) loadEnv() error {
+	var r envvar.Reader
+
+	if v := r.Get("API_BASE_PATH"); v != "" {
 		c.BasePath = v
 	}
+	if v := r.Get("API_FEATURES"); v != "" {
+		if c.Features == nil {
+			c.Features = make(Features)
+		}
+		c.Features.parse(v)
+	}
+
+	return r.Err()
 }
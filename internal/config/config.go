@@ -1,12 +1,30 @@
 // Package config provides application configuration management with support for
 // TOML files, environment variable overrides, and configuration overlays.
+//
+// Every override variable, such as API_AUTH_TOKENS, may instead be given as
+// the path of a file holding its value in the same name suffixed with _FILE,
+// such as API_AUTH_TOKENS_FILE, for secrets mounted by Docker or Kubernetes.
+// SERVICE_ENV, SERVICE_CONFIG, and CONFIG_STRICT, which select and govern
+// the files to load, are read directly.
+//
+// Configuration files are parsed strictly: a key that matches no setting,
+// such as a misspelled shutdown_timout or a [sever] table, fails the load
+// with its file and line rather than being silently ignored. Set
+// CONFIG_STRICT=false to accept unknown keys while migrating old files.
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/JaimeStill/go-lit/pkg/envvar"
 	"github.com/pelletier/go-toml/v2"
 )
 
@@ -14,14 +32,27 @@ const (
 	// BaseConfigFile is the primary configuration file name.
 	BaseConfigFile = "config.toml"
 
-	// OverlayConfigPattern is the file name pattern for environment-specific overlays.
+	// OverlayConfigPattern is the file name pattern for environment-specific
+	// overlays, resolved in the directory of the base file.
 	OverlayConfigPattern = "config.%s.toml"
 
+	// EnvConfigStrict disables strict parsing of configuration files when
+	// set to false.
+	EnvConfigStrict = "CONFIG_STRICT"
+
+	// EnvServiceConfig overrides the path of the base configuration file.
+	EnvServiceConfig = "SERVICE_CONFIG"
+
 	EnvServiceDomain = "SERVICE_DOMAIN"
 
-	// EnvServiceEnv specifies the environment name for configuration overlays.
+	// EnvServiceEnv specifies the environment name for configuration
+	// overlays, or a comma-separated list of names whose overlays are applied
+	// in order, such as "eu,eu-prod".
 	EnvServiceEnv = "SERVICE_ENV"
 
+	// EnvServiceProfile overrides the built-in defaults profile.
+	EnvServiceProfile = "SERVICE_PROFILE"
+
 	// EnvServiceShutdownTimeout overrides the service shutdown timeout.
 	EnvServiceShutdownTimeout = "SERVICE_SHUTDOWN_TIMEOUT"
 
@@ -29,40 +60,73 @@ const (
 )
 
 // Config represents the root service configuration.
+// Profile selects the built-in defaults, "local" or "production", filled in
+// for settings the files leave unset; it defaults to local when SERVICE_ENV
+// is unset or "local" and to production otherwise.
 type Config struct {
-	Server          ServerConfig  `toml:"server"`
-	Logging         LoggingConfig `toml:"logging"`
-	API             APIConfig     `toml:"api"`
-	Domain          string        `toml:"domain"`
-	ShutdownTimeout string        `toml:"shutdown_timeout"`
-	Version         string        `toml:"version"`
+	Server          ServerConfig    `toml:"server"`
+	Logging         LoggingConfig   `toml:"logging"`
+	API             APIConfig       `toml:"api"`
+	Telemetry       TelemetryConfig `toml:"telemetry"`
+	Admin           AdminConfig     `toml:"admin"`
+	Agents          AgentsConfig    `toml:"agents"`
+	Domain          string          `toml:"domain"`
+	Profile         string          `toml:"profile"`
+	ShutdownTimeout Duration        `toml:"shutdown_timeout"`
+	Version         string          `toml:"version"`
+
+	source   string
+	overlays []string
+	defined  map[string]bool
+}
+
+// Source returns the path of the base file the configuration was loaded from.
+func (c *Config) Source() string {
+	return c.source
 }
 
-// Env returns the current environment name from the SERVICE_ENV variable or "local".
+// Overlays returns the paths of the overlay files applied over the base
+// file, in the order they were applied.
+func (c *Config) Overlays() []string {
+	return c.overlays
+}
+
+// Env returns the current environment name from the SERVICE_ENV variable or
+// "local". When SERVICE_ENV lists several names, the last and most specific
+// is returned.
 func (c *Config) Env() string {
-	if env := os.Getenv(EnvServiceEnv); env != "" {
-		return env
+	if envs := envNames(); len(envs) > 0 {
+		return envs[len(envs)-1]
 	}
 	return "local"
 }
 
-// ShutdownTimeoutDuration parses and returns the shutdown timeout as a time.Duration.
-func (c *Config) ShutdownTimeoutDuration() time.Duration {
-	d, _ := time.ParseDuration(c.ShutdownTimeout)
-	return d
+// Load reads the base configuration file named by SERVICE_CONFIG, or
+// config.toml in the working directory, and applies any environment-specific
+// overlay.
+func Load() (*Config, error) {
+	path := BaseConfigFile
+	if v := os.Getenv(EnvServiceConfig); v != "" {
+		path = v
+	}
+	return LoadFrom(path)
 }
 
-// Load reads and parses the base configuration file and applies any environment-specific overlay.
-func Load() (*Config, error) {
-	cfg, err := load(BaseConfigFile)
+// LoadFrom reads and parses the base configuration file at path and merges
+// the overlay of each environment named by SERVICE_ENV over it in order, from
+// the same directory. Every named overlay must exist, so a misspelled
+// environment fails to load rather than silently running on the base file.
+func LoadFrom(path string) (*Config, error) {
+	cfg, err := load(path)
 	if err != nil {
 		return nil, err
 	}
 
-	if path := overlayPath(); path != "" {
-		overlay, err := load(path)
+	overlays := overlayPaths(filepath.Dir(path))
+	for _, overlayPath := range overlays {
+		overlay, err := load(overlayPath)
 		if err != nil {
-			return nil, fmt.Errorf("load overlay %s: %w", path, err)
+			return nil, fmt.Errorf("load overlay %s: %w", overlayPath, err)
 		}
 		cfg.Merge(overlay)
 	}
@@ -71,27 +135,29 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("finalize config: %w", err)
 	}
 
+	cfg.source = path
+	cfg.overlays = overlays
 	return cfg, nil
 }
 
-// Finalize applies defaults, loads environment overrides, and validates the configuration.
+// finalize applies defaults, loads environment overrides, and validates the
+// configuration, filling in the defaults of the selected profile before the
+// sections are finalized. Every section is finalized even after one fails,
+// and all of their problems are returned together in a *ValidationError.
 func (c *Config) finalize() error {
 	c.loadDefaults()
-	c.loadEnv()
 
-	if err := c.validate(); err != nil {
-		return err
-	}
-	if err := c.Server.Finalize(); err != nil {
-		return fmt.Errorf("server: %w", err)
-	}
-	if err := c.Logging.Finalize(); err != nil {
-		return fmt.Errorf("logging: %w", err)
-	}
-	if err := c.API.Finalize(); err != nil {
-		return fmt.Errorf("api: %w", err)
-	}
-	return nil
+	var errs fieldErrors
+	errs.addSection("", c.loadEnv())
+	errs.addSection("", c.validate())
+	c.applyProfile()
+	errs.addSection("server", c.Server.Finalize())
+	errs.addSection("logging", c.Logging.Finalize())
+	errs.addSection("api", c.API.Finalize())
+	errs.addSection("telemetry", c.Telemetry.Finalize())
+	errs.addSection("admin", c.Admin.Finalize())
+	errs.addSection("agents", c.Agents.Finalize())
+	return errs.err()
 }
 
 // Merge applies values from overlay configuration that differ from zero values.
@@ -99,46 +165,88 @@ func (c *Config) Merge(overlay *Config) {
 	if overlay.Domain != "" {
 		c.Domain = overlay.Domain
 	}
-	if overlay.ShutdownTimeout != "" {
-		c.ShutdownTimeout = overlay.ShutdownTimeout
+	if overlay.Profile != "" {
+		c.Profile = overlay.Profile
 	}
+	if len(overlay.defined) > 0 {
+		if c.defined == nil {
+			c.defined = make(map[string]bool)
+		}
+		maps.Copy(c.defined, overlay.defined)
+	}
+	c.ShutdownTimeout.Merge(overlay.ShutdownTimeout)
 	if overlay.Version != "" {
 		c.Version = overlay.Version
 	}
 	c.Server.Merge(&overlay.Server)
 	c.Logging.Merge(&overlay.Logging)
 	c.API.Merge(&overlay.API)
+	c.Telemetry.Merge(&overlay.Telemetry)
+	c.Admin.Merge(&overlay.Admin)
+	c.Agents.Merge(&overlay.Agents)
+
+	// Enabled flags are only merged when the overlay sets them, since an
+	// overlay that omits a section decodes it as disabled.
+	overlay.mergeFlag(&c.API.CORS.Enabled, "api.cors.enabled", overlay.API.CORS.Enabled)
+	overlay.mergeFlag(&c.API.CORS.AllowCredentials, "api.cors.allow_credentials", overlay.API.CORS.AllowCredentials)
+	overlay.mergeFlag(&c.API.Auth.Enabled, "api.auth.enabled", overlay.API.Auth.Enabled)
+	overlay.mergeFlag(&c.Admin.Auth.Enabled, "admin.auth.enabled", overlay.Admin.Auth.Enabled)
+	overlay.mergeFlag(&c.API.APIKeys.Enabled, "api.api_keys.enabled", overlay.API.APIKeys.Enabled)
+	overlay.mergeFlag(&c.API.RateLimit.Enabled, "api.rate_limit.enabled", overlay.API.RateLimit.Enabled)
+	overlay.mergeFlag(&c.API.Concurrency.Enabled, "api.concurrency.enabled", overlay.API.Concurrency.Enabled)
+	overlay.mergeFlag(&c.Telemetry.Enabled, "telemetry.enabled", overlay.Telemetry.Enabled)
+}
+
+// mergeFlag sets *dst to value when c, an overlay, defines key.
+func (c *Config) mergeFlag(dst *bool, key string, value bool) {
+	if c.defined[key] {
+		*dst = value
+	}
 }
 
 func (c *Config) loadDefaults() {
 	if c.Domain == "" {
 		c.Domain = "http://localhost:8080"
 	}
-	if c.ShutdownTimeout == "" {
-		c.ShutdownTimeout = "30s"
+	if c.Profile == "" {
+		c.Profile = c.defaultProfile()
 	}
+	c.ShutdownTimeout.SetDefault(30 * time.Second)
 	if c.Version == "" {
 		c.Version = "0.1.0"
 	}
 }
 
-func (c *Config) loadEnv() {
-	if v := os.Getenv(EnvServiceDomain); v != "" {
+func (c *Config) loadEnv() error {
+	var r envvar.Reader
+	var errs fieldErrors
+
+	if v := r.Get(EnvServiceDomain); v != "" {
 		c.Domain = v
 	}
-	if v := os.Getenv(EnvServiceShutdownTimeout); v != "" {
-		c.ShutdownTimeout = v
+	if v := r.Get(EnvServiceProfile); v != "" {
+		c.Profile = v
+	}
+	if v := r.Get(EnvServiceShutdownTimeout); v != "" {
+		errs.add("shutdown_timeout", parseEnvDuration(&c.ShutdownTimeout, EnvServiceShutdownTimeout, v))
 	}
-	if v := os.Getenv(EnvServiceVersion); v != "" {
+	if v := r.Get(EnvServiceVersion); v != "" {
 		c.Version = v
 	}
+
+	errs.add("", r.Err())
+	return errs.err()
 }
 
 func (c *Config) validate() error {
-	if _, err := time.ParseDuration(c.ShutdownTimeout); err != nil {
-		return fmt.Errorf("invalid shutdown_timeout: %w", err)
+	var errs fieldErrors
+	if _, ok := profiles[c.Profile]; !ok {
+		errs.add("profile", fmt.Errorf("%q must be local or production", c.Profile))
+	}
+	if c.ShutdownTimeout.Duration < 0 {
+		errs.add("shutdown_timeout", fmt.Errorf("%s must not be negative", c.ShutdownTimeout))
 	}
-	return nil
+	return errs.err()
 }
 
 func load(path string) (*Config, error) {
@@ -147,20 +255,82 @@ func load(path string) (*Config, error) {
 		return nil, fmt.Errorf("read config: %w", err)
 	}
 
+	dec := toml.NewDecoder(bytes.NewReader(data))
+	if strict() {
+		dec.DisallowUnknownFields()
+	}
+
 	var cfg Config
-	if err := toml.Unmarshal(data, &cfg); err != nil {
+	if err := dec.Decode(&cfg); err != nil {
+		var missing *toml.StrictMissingError
+		if errors.As(err, &missing) {
+			return nil, unknownKeys(path, missing)
+		}
 		return nil, fmt.Errorf("parse config: %w", err)
 	}
 
+	var raw map[string]any
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	cfg.defined = make(map[string]bool)
+	definedKeys(cfg.defined, "", raw)
+
 	return &cfg, nil
 }
 
-func overlayPath() string {
-	if env := os.Getenv(EnvServiceEnv); env != "" {
-		overlayPath := fmt.Sprintf(OverlayConfigPattern, env)
-		if _, err := os.Stat(overlayPath); err == nil {
-			return overlayPath
+// definedKeys records the dotted path of every key set in table, such as
+// "api.cors.enabled", so settings whose zero value is meaningful can be told
+// apart from settings left out of the file.
+func definedKeys(keys map[string]bool, prefix string, table map[string]any) {
+	for key, value := range table {
+		path := prefix + key
+		keys[path] = true
+		if nested, ok := value.(map[string]any); ok {
+			definedKeys(keys, path+".", nested)
+		}
+	}
+}
+
+// strict reports whether configuration files are parsed strictly, which
+// they are unless CONFIG_STRICT is set to false.
+func strict() bool {
+	if v := os.Getenv(EnvConfigStrict); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			return enabled
 		}
 	}
-	return ""
+	return true
+}
+
+// unknownKeys describes each key in path that matches no setting, with the
+// line it appears on.
+func unknownKeys(path string, err *toml.StrictMissingError) error {
+	keys := make([]string, 0, len(err.Errors))
+	for _, e := range err.Errors {
+		row, _ := e.Position()
+		keys = append(keys, fmt.Sprintf("%s (%s:%d)", strings.Join(e.Key(), "."), path, row))
+	}
+	return fmt.Errorf("unknown config keys: %s", strings.Join(keys, ", "))
+}
+
+// envNames returns the environment names listed in SERVICE_ENV.
+func envNames() []string {
+	var names []string
+	for name := range strings.SplitSeq(os.Getenv(EnvServiceEnv), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// overlayPaths returns the overlay file of each environment in SERVICE_ENV,
+// in dir.
+func overlayPaths(dir string) []string {
+	var paths []string
+	for _, env := range envNames() {
+		paths = append(paths, filepath.Join(dir, fmt.Sprintf(OverlayConfigPattern, env)))
+	}
+	return paths
 }
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStrictParsing(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		overlay string
+		strict  string
+		wantErr []string
+	}{
+		{
+			name: "known keys",
+			body: "shutdown_timeout = \"5s\"\n\n[server]\nport = 9000\n",
+		},
+		{
+			name:    "unknown top-level key",
+			body:    "shutdown_timout = \"5s\"\n",
+			wantErr: []string{"unknown config keys", "shutdown_timout (", "config.toml:1)"},
+		},
+		{
+			name:    "unknown nested key",
+			body:    "[server]\nport = 9000\nread_timout = \"5s\"\n",
+			wantErr: []string{"server.read_timout (", "config.toml:3)"},
+		},
+		{
+			name:    "unknown key in a nested table",
+			body:    "[api.cors]\norigin = [\"https://app.example.com\"]\n",
+			wantErr: []string{"api.cors.origin ("},
+		},
+		{
+			name:    "misnamed table",
+			body:    "[sever]\nport = 9000\n",
+			wantErr: []string{"sever (", "config.toml:1)"},
+		},
+		{
+			name:    "every unknown key is reported",
+			body:    "shutdown_timout = \"5s\"\n\n[server]\nportt = 9000\n",
+			wantErr: []string{"shutdown_timout (", "server.portt ("},
+		},
+		{
+			name:    "unknown key in an overlay",
+			body:    "[server]\nport = 9000\n",
+			overlay: "[server]\nprot = 9001\n",
+			wantErr: []string{"load overlay", "config.staging.toml", "server.prot ("},
+		},
+		{
+			name:   "opt out",
+			body:   "shutdown_timout = \"5s\"\n\n[sever]\nport = 9000\n",
+			strict: "false",
+		},
+		{
+			name:    "opt out of an overlay",
+			body:    "[server]\nport = 9000\n",
+			overlay: "[server]\nprot = 9001\n",
+			strict:  "false",
+		},
+		{
+			name:    "explicitly strict",
+			body:    "shutdown_timout = \"5s\"\n",
+			strict:  "true",
+			wantErr: []string{"shutdown_timout ("},
+		},
+		{
+			name:    "unparsable setting stays strict",
+			body:    "shutdown_timout = \"5s\"\n",
+			strict:  "sometimes",
+			wantErr: []string{"shutdown_timout ("},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv(EnvConfigStrict, tt.strict)
+
+			path := writeConfig(t, tt.body)
+			if tt.overlay != "" {
+				t.Setenv(EnvServiceEnv, "staging")
+				overlay := filepath.Join(filepath.Dir(path), fmt.Sprintf(OverlayConfigPattern, "staging"))
+				if err := os.WriteFile(overlay, []byte(tt.overlay), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err := LoadFrom(path)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("LoadFrom() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("LoadFrom() error = nil, want unknown keys")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("LoadFrom() error = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOverlayKeepsEnabledFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		body    string
+		overlay string
+		enabled func(*Config) bool
+		want    bool
+	}{
+		{
+			name:    "overlay without auth keeps it on",
+			env:     "API_AUTH_ENABLED",
+			body:    "[api.auth]\nenabled = true\n\n[[api.auth.tokens]]\nsubject = \"svc\"\ntoken = \"secret\"\n",
+			overlay: "domain = \"https://eu.example.com\"\n",
+			enabled: func(c *Config) bool { return c.API.Auth.Enabled },
+			want:    true,
+		},
+		{
+			name:    "overlay disables auth",
+			env:     "API_AUTH_ENABLED",
+			body:    "[api.auth]\nenabled = true\n\n[[api.auth.tokens]]\nsubject = \"svc\"\ntoken = \"secret\"\n",
+			overlay: "[api.auth]\nenabled = false\n",
+			enabled: func(c *Config) bool { return c.API.Auth.Enabled },
+			want:    false,
+		},
+		{
+			name:    "overlay without api keys keeps them on",
+			env:     "API_KEYS_ENABLED",
+			body:    "[api.api_keys]\nenabled = true\n\n[[api.api_keys.keys]]\nname = \"svc\"\nhash = \"0000000000000000000000000000000000000000000000000000000000000000\"\n",
+			overlay: "domain = \"https://eu.example.com\"\n",
+			enabled: func(c *Config) bool { return c.API.APIKeys.Enabled },
+			want:    true,
+		},
+		{
+			name:    "overlay without rate limit keeps it on",
+			env:     "API_RATE_LIMIT_ENABLED",
+			body:    "[api.rate_limit]\nenabled = true\n",
+			overlay: "domain = \"https://eu.example.com\"\n",
+			enabled: func(c *Config) bool { return c.API.RateLimit.Enabled },
+			want:    true,
+		},
+		{
+			name:    "overlay without concurrency keeps it on",
+			env:     "API_CONCURRENCY_ENABLED",
+			body:    "[api.concurrency]\nenabled = true\n",
+			overlay: "domain = \"https://eu.example.com\"\n",
+			enabled: func(c *Config) bool { return c.API.Concurrency.Enabled },
+			want:    true,
+		},
+		{
+			name:    "overlay without telemetry keeps it on",
+			env:     "TELEMETRY_ENABLED",
+			body:    "[telemetry]\nenabled = true\n",
+			overlay: "domain = \"https://eu.example.com\"\n",
+			enabled: func(c *Config) bool { return c.Telemetry.Enabled },
+			want:    true,
+		},
+		{
+			name:    "overlay without cors keeps it on",
+			env:     "API_CORS_ENABLED",
+			body:    "profile = \"local\"\n\n[api.cors]\nenabled = true\n",
+			overlay: "[server]\nport = 9000\n",
+			enabled: func(c *Config) bool { return c.API.CORS.Enabled },
+			want:    true,
+		},
+		{
+			name:    "overlay without cors keeps credentials allowed",
+			env:     "API_CORS_ALLOW_CREDENTIALS",
+			body:    "profile = \"production\"\n\n[api.cors]\nenabled = true\norigins = [\"https://app.example.com\"]\nallow_credentials = true\n",
+			overlay: "[server]\nport = 9000\n",
+			enabled: func(c *Config) bool { return c.API.CORS.AllowCredentials },
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t, tt.env)
+			t.Setenv(EnvServiceEnv, "eu")
+
+			path := writeConfig(t, tt.body)
+			overlay := filepath.Join(filepath.Dir(path), fmt.Sprintf(OverlayConfigPattern, "eu"))
+			if err := os.WriteFile(overlay, []byte(tt.overlay), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := LoadFrom(path)
+			if err != nil {
+				t.Fatalf("LoadFrom() error = %v", err)
+			}
+			if got := tt.enabled(cfg); got != tt.want {
+				t.Errorf("enabled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FieldError is a problem with one setting, identified by its TOML key
+// path, such as "server.read_timeout". A problem with a whole section, such
+// as an unreadable *_FILE override, has the section's path.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Err.Error()
+	}
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError lists every problem found while finalizing a
+// configuration, so a broken deployment can be fixed in one pass.
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	parts := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		parts[i] = fe.Error()
+	}
+	return fmt.Sprintf("%d invalid settings: %s", len(e.Errors), strings.Join(parts, "; "))
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, fe := range e.Errors {
+		errs[i] = fe
+	}
+	return errs
+}
+
+// fieldErrors collects the problems of a section as it is finalized.
+type fieldErrors []*FieldError
+
+// add records err against field, ignoring a nil err.
+func (f *fieldErrors) add(field string, err error) {
+	if err != nil {
+		*f = append(*f, &FieldError{Field: field, Err: err})
+	}
+}
+
+// addSection records the problems of a nested section, prefixing their
+// fields with section. An error that is not a *ValidationError is recorded
+// against the section as a whole.
+func (f *fieldErrors) addSection(section string, err error) {
+	if err == nil {
+		return
+	}
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		f.add(section, err)
+		return
+	}
+	for _, fe := range ve.Errors {
+		field := fe.Field
+		switch {
+		case section == "":
+		case field == "":
+			field = section
+		default:
+			field = section + "." + field
+		}
+		f.add(field, fe.Err)
+	}
+}
+
+// err returns the collected problems as a *ValidationError, or nil.
+func (f fieldErrors) err() error {
+	if len(f) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: f}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidationErrorAggregates(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		env        map[string]string
+		wantFields []string
+		wantPrefix string
+	}{
+		{
+			name: "three sections",
+			body: "[server]\nport = 70000\n\n[logging]\nlevel = \"loud\"\n\n" +
+				"[api.concurrency]\nqueue_timeout = \"-1s\"\n",
+			wantFields: []string{"server.port", "logging.level", "api.concurrency"},
+			wantPrefix: "3 invalid settings: ",
+		},
+		{
+			name:       "root, environment, and nested fields",
+			body:       "shutdown_timeout = \"-1s\"\n\n[agents]\nmax_images = -1\n",
+			env:        map[string]string{EnvServerReadTimeout: "soon"},
+			wantFields: []string{"shutdown_timeout", "server.read_timeout", "agents.max_images"},
+			wantPrefix: "3 invalid settings: ",
+		},
+		{
+			name:       "single field",
+			body:       "[server]\nport = -1\n",
+			wantFields: []string{"server.port"},
+			wantPrefix: "finalize config: server.port: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t, EnvServerReadTimeout)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			_, err := LoadFrom(writeConfig(t, tt.body))
+			var ve *ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("LoadFrom() error = %v, want a *ValidationError", err)
+			}
+
+			fields := make([]string, len(ve.Errors))
+			for i, fe := range ve.Errors {
+				fields[i] = fe.Field
+			}
+			if !slices.Equal(fields, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", fields, tt.wantFields)
+			}
+			if !strings.Contains(err.Error(), tt.wantPrefix) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestFieldErrorsAddSection(t *testing.T) {
+	nested := &ValidationError{Errors: []*FieldError{
+		{Field: "port", Err: errors.New("bad port")},
+		{Field: "", Err: errors.New("bad section")},
+	}}
+
+	tests := []struct {
+		name    string
+		section string
+		err     error
+		want    []string
+	}{
+		{name: "nil", section: "server", err: nil, want: []string{}},
+		{name: "plain error", section: "api.cors", err: errors.New("bad"), want: []string{"api.cors: bad"}},
+		{name: "prefixed", section: "server", err: nested, want: []string{"server.port: bad port", "server: bad section"}},
+		{name: "root section", section: "", err: nested, want: []string{"port: bad port", "bad section"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs fieldErrors
+			errs.addSection(tt.section, tt.err)
+
+			got := make([]string, len(errs))
+			for i, fe := range errs {
+				got[i] = fe.Error()
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("errors = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
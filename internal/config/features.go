@@ -0,0 +1,43 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Features holds named feature flags from the [api.features] table.
+// Flags that are not set are disabled.
+type Features map[string]bool
+
+// Enabled reports whether the named flag is set to true.
+func (f Features) Enabled(name string) bool {
+	return f[name]
+}
+
+// Flag returns a function reporting whether the named flag is enabled,
+// suitable for the Enabled field of routes.Route and routes.Group.
+func (f Features) Flag(name string) func() bool {
+	return func() bool { return f.Enabled(name) }
+}
+
+// parse applies a comma-separated list of flags, each a name optionally
+// followed by =true or =false, such as "experimental_agents,legacy_vision=false".
+// A bare name enables the flag. Entries with invalid values are ignored.
+func (f Features) parse(v string) {
+	for entry := range strings.SplitSeq(v, ",") {
+		name, value, hasValue := strings.Cut(strings.TrimSpace(entry), "=")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		enabled := true
+		if hasValue {
+			b, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				continue
+			}
+			enabled = b
+		}
+		f[name] = enabled
+	}
+}
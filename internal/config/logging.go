@@ -1,6 +1,6 @@
 package config
 
-import "os"
+import "github.com/JaimeStill/go-lit/pkg/envvar"
 
 const (
 	// EnvLoggingLevel overrides the logging level.
@@ -16,11 +16,15 @@ type LoggingConfig struct {
 	Format LogFormat `toml:"format"`
 }
 
-// Finalize applies defaults, loads environment overrides, and validates the logging configuration.
+// Finalize applies defaults, loads environment overrides, and validates the
+// logging configuration, reporting every invalid field in a *ValidationError.
 func (c *LoggingConfig) Finalize() error {
 	c.loadDefaults()
-	c.loadEnv()
-	return c.validate()
+
+	var errs fieldErrors
+	errs.add("", c.loadEnv())
+	errs.addSection("", c.validate())
+	return errs.err()
 }
 
 // Merge applies values from overlay configuration that differ from zero values.
@@ -33,13 +37,17 @@ func (c *LoggingConfig) Merge(overlay *LoggingConfig) {
 	}
 }
 
-func (c *LoggingConfig) loadEnv() {
-	if v := os.Getenv(EnvLoggingLevel); v != "" {
+func (c *LoggingConfig) loadEnv() error {
+	var r envvar.Reader
+
+	if v := r.Get(EnvLoggingLevel); v != "" {
 		c.Level = LogLevel(v)
 	}
-	if v := os.Getenv(EnvLoggingFormat); v != "" {
+	if v := r.Get(EnvLoggingFormat); v != "" {
 		c.Format = LogFormat(v)
 	}
+
+	return r.Err()
 }
 
 func (c *LoggingConfig) loadDefaults() {
@@ -52,11 +60,8 @@ func (c *LoggingConfig) loadDefaults() {
 }
 
 func (c *LoggingConfig) validate() error {
-	if err := c.Level.Validate(); err != nil {
-		return err
-	}
-	if err := c.Format.Validate(); err != nil {
-		return err
-	}
-	return nil
+	var errs fieldErrors
+	errs.add("level", c.Level.Validate())
+	errs.add("format", c.Format.Validate())
+	return errs.err()
 }
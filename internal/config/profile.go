@@ -0,0 +1,70 @@
+package config
+
+import "slices"
+
+// Built-in configuration profiles.
+const (
+	// ProfileLocal defaults to text logs at debug level and CORS enabled for
+	// localhost origins.
+	ProfileLocal = "local"
+
+	// ProfileProduction defaults to JSON logs at info level and CORS
+	// disabled.
+	ProfileProduction = "production"
+)
+
+// localOrigins are the CORS origins allowed by the local profile.
+var localOrigins = []string{
+	"http://localhost:8080",
+	"http://127.0.0.1:8080",
+	"http://localhost:3000",
+	"http://localhost:5173",
+}
+
+// profiles apply the defaults of each built-in profile. They only fill
+// settings the configuration files left unset, and run before each section
+// loads its environment overrides, so explicit file and environment values
+// always win.
+var profiles = map[string]func(*Config){
+	ProfileLocal: func(c *Config) {
+		if c.Logging.Level == "" {
+			c.Logging.Level = LogLevelDebug
+		}
+		if c.Logging.Format == "" {
+			c.Logging.Format = LogFormatText
+		}
+		if !c.defined["api.cors.enabled"] {
+			c.API.CORS.Enabled = true
+		}
+		if !c.defined["api.cors.origins"] {
+			c.API.CORS.Origins = slices.Clone(localOrigins)
+		}
+	},
+	ProfileProduction: func(c *Config) {
+		if c.Logging.Level == "" {
+			c.Logging.Level = LogLevelInfo
+		}
+		if c.Logging.Format == "" {
+			c.Logging.Format = LogFormatJSON
+		}
+		if !c.defined["api.cors.enabled"] {
+			c.API.CORS.Enabled = false
+		}
+	},
+}
+
+// defaultProfile returns the profile used when none is configured: local
+// for the local environment and production for every other.
+func (c *Config) defaultProfile() string {
+	if c.Env() == "local" {
+		return ProfileLocal
+	}
+	return ProfileProduction
+}
+
+// applyProfile applies the defaults of the selected profile.
+func (c *Config) applyProfile() {
+	if apply, ok := profiles[c.Profile]; ok {
+		apply(c)
+	}
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeConfig writes body as config.toml in a temporary directory and
+// returns its path.
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), BaseConfigFile)
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// clearEnv unsets the variables that select and override configuration so
+// the environment running the tests does not leak into them.
+func clearEnv(t *testing.T, names ...string) {
+	t.Helper()
+	for _, name := range append([]string{EnvServiceEnv, EnvServiceProfile, EnvConfigStrict}, names...) {
+		t.Setenv(name, "")
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		body    string
+		env     map[string]string
+		level   LogLevel
+		format  LogFormat
+		cors    bool
+		origins []string
+	}{
+		{
+			name:    "local defaults",
+			level:   LogLevelDebug,
+			format:  LogFormatText,
+			cors:    true,
+			origins: localOrigins,
+		},
+		{
+			name:    "production defaults",
+			profile: ProfileProduction,
+			level:   LogLevelInfo,
+			format:  LogFormatJSON,
+		},
+		{
+			name:    "file disables cors under local",
+			body:    "[api.cors]\nenabled = false\n",
+			level:   LogLevelDebug,
+			format:  LogFormatText,
+			origins: localOrigins,
+		},
+		{
+			name:    "file values win under local",
+			body:    "[logging]\nlevel = \"warn\"\nformat = \"json\"\n\n[api.cors]\norigins = [\"https://app.example.com\"]\n",
+			level:   LogLevelWarn,
+			format:  LogFormatJSON,
+			cors:    true,
+			origins: []string{"https://app.example.com"},
+		},
+		{
+			name:    "file enables cors under production",
+			profile: ProfileProduction,
+			body:    "[api.cors]\nenabled = true\norigins = [\"https://app.example.com\"]\n",
+			level:   LogLevelInfo,
+			format:  LogFormatJSON,
+			cors:    true,
+			origins: []string{"https://app.example.com"},
+		},
+		{
+			name:    "env values win",
+			profile: ProfileProduction,
+			env:     map[string]string{"LOGGING_LEVEL": "debug", "API_CORS_ENABLED": "true"},
+			level:   LogLevelDebug,
+			format:  LogFormatJSON,
+			cors:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t, "LOGGING_LEVEL", "LOGGING_FORMAT", "API_CORS_ENABLED", "API_CORS_ORIGINS")
+			t.Setenv(EnvServiceProfile, tt.profile)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := LoadFrom(writeConfig(t, tt.body))
+			if err != nil {
+				t.Fatalf("LoadFrom() error = %v", err)
+			}
+
+			if cfg.Logging.Level != tt.level {
+				t.Errorf("Logging.Level = %q, want %q", cfg.Logging.Level, tt.level)
+			}
+			if cfg.Logging.Format != tt.format {
+				t.Errorf("Logging.Format = %q, want %q", cfg.Logging.Format, tt.format)
+			}
+			if cfg.API.CORS.Enabled != tt.cors {
+				t.Errorf("API.CORS.Enabled = %v, want %v", cfg.API.CORS.Enabled, tt.cors)
+			}
+			if !slices.Equal(cfg.API.CORS.Origins, tt.origins) {
+				t.Errorf("API.CORS.Origins = %v, want %v", cfg.API.CORS.Origins, tt.origins)
+			}
+		})
+	}
+}
+
+func TestDefaultProfile(t *testing.T) {
+	tests := []struct {
+		env  string
+		want string
+	}{
+		{"", ProfileLocal},
+		{"local", ProfileLocal},
+		{"production", ProfileProduction},
+		{"staging", ProfileProduction},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv(EnvServiceEnv, tt.env)
+			if got := new(Config).defaultProfile(); got != tt.want {
+				t.Errorf("defaultProfile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
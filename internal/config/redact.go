@@ -0,0 +1,61 @@
+package config
+
+import "reflect"
+
+// RedactedValue replaces the value of each sensitive setting in Redacted.
+const RedactedValue = "[redacted]"
+
+// Redacted returns a deep copy of c in which every non-empty string field
+// tagged `sensitive:"true"`, and every element of such a string slice or
+// map, is replaced with RedactedValue. Tag new secret settings, such as
+// tokens and keys, so they never appear in logs or debug output.
+func (c *Config) Redacted() *Config {
+	out := new(Config)
+	redact(reflect.ValueOf(out).Elem(), reflect.ValueOf(c).Elem(), false)
+	return out
+}
+
+// redact deep copies src into dst, masking strings when sensitive is set.
+func redact(dst, src reflect.Value, sensitive bool) {
+	switch src.Kind() {
+	case reflect.Struct:
+		dst.Set(src)
+		for i := range src.NumField() {
+			field := src.Type().Field(i)
+			if field.IsExported() {
+				redact(dst.Field(i), src.Field(i), field.Tag.Get("sensitive") == "true")
+			}
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			dst.SetZero()
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := range src.Len() {
+			redact(s.Index(i), src.Index(i), sensitive)
+		}
+		dst.Set(s)
+	case reflect.Map:
+		if src.IsNil() {
+			dst.SetZero()
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			v := reflect.New(src.Type().Elem()).Elem()
+			redact(v, iter.Value(), sensitive)
+			m.SetMapIndex(iter.Key(), v)
+		}
+		dst.Set(m)
+	case reflect.String:
+		if sensitive && src.Len() > 0 {
+			dst.SetString(RedactedValue)
+			return
+		}
+		dst.Set(src)
+	default:
+		dst.Set(src)
+	}
+}
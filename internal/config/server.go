@@ -2,9 +2,13 @@ package config
 
 import (
 	"fmt"
-	"os"
+	"math"
+	"net/netip"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/JaimeStill/go-lit/pkg/envvar"
 )
 
 const (
@@ -22,15 +26,60 @@ const (
 
 	// EnvServerShutdownTimeout overrides the server shutdown timeout.
 	EnvServerShutdownTimeout = "SERVER_SHUTDOWN_TIMEOUT"
+
+	// EnvServerStartupTimeout overrides how long startup hooks may run.
+	EnvServerStartupTimeout = "SERVER_STARTUP_TIMEOUT"
+
+	// EnvServerStartupTimeoutAction overrides what happens when startup times out.
+	EnvServerStartupTimeoutAction = "SERVER_STARTUP_TIMEOUT_ACTION"
+
+	// EnvServerRequestTimeout overrides the per-request handler timeout.
+	EnvServerRequestTimeout = "SERVER_REQUEST_TIMEOUT"
+
+	// EnvServerMaxBodySize overrides the maximum request body size.
+	EnvServerMaxBodySize = "SERVER_MAX_BODY_SIZE"
+
+	// EnvServerTrustedProxies overrides the trusted proxy addresses and CIDR ranges (comma-separated).
+	EnvServerTrustedProxies = "SERVER_TRUSTED_PROXIES"
+
+	// EnvServerForwardedPrefixes overrides the accepted X-Forwarded-Prefix values (comma-separated).
+	EnvServerForwardedPrefixes = "SERVER_FORWARDED_PREFIXES"
+)
+
+// Startup timeout actions.
+const (
+	// StartupAbort shuts the service down when startup times out.
+	StartupAbort = "abort"
+
+	// StartupDegrade keeps the service running, not ready, when startup
+	// times out, becoming ready if the remaining hooks finish.
+	StartupDegrade = "degrade"
 )
 
 // ServerConfig contains HTTP server configuration.
+// StartupTimeout bounds how long startup hooks may run before
+// StartupTimeoutAction, "abort" or "degrade", is taken; "0" waits
+// indefinitely.
+// RequestTimeout bounds how long API handlers may run before the request is
+// canceled with a 504; event streams are exempt and "0" disables it.
+// MaxBodySize limits API request bodies, written as a byte count with an
+// optional B, KB, MB, or GB suffix such as "10MB"; "0" disables it.
+// ForwardedPrefixes lists the X-Forwarded-Prefix values accepted from a
+// reverse proxy; the header is ignored when the list is empty.
+// TrustedProxies lists the addresses or CIDR ranges of proxies whose
+// X-Forwarded-For and X-Real-IP headers identify the client.
 type ServerConfig struct {
-	Host            string `toml:"host"`
-	Port            int    `toml:"port"`
-	ReadTimeout     string `toml:"read_timeout"`
-	WriteTimeout    string `toml:"write_timeout"`
-	ShutdownTimeout string `toml:"shutdown_timeout"`
+	Host                 string   `toml:"host"`
+	Port                 int      `toml:"port"`
+	ReadTimeout          Duration `toml:"read_timeout"`
+	WriteTimeout         Duration `toml:"write_timeout"`
+	ShutdownTimeout      Duration `toml:"shutdown_timeout"`
+	StartupTimeout       Duration `toml:"startup_timeout"`
+	StartupTimeoutAction string   `toml:"startup_timeout_action"`
+	RequestTimeout       Duration `toml:"request_timeout"`
+	MaxBodySize          string   `toml:"max_body_size"`
+	ForwardedPrefixes    []string `toml:"forwarded_prefixes"`
+	TrustedProxies       []string `toml:"trusted_proxies"`
 }
 
 // Addr returns the server address in host:port format.
@@ -38,29 +87,33 @@ func (c *ServerConfig) Addr() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
-// ReadTimeoutDuration parses and returns the read timeout as a time.Duration.
-func (c *ServerConfig) ReadTimeoutDuration() time.Duration {
-	d, _ := time.ParseDuration(c.ReadTimeout)
-	return d
-}
-
-// WriteTimeoutDuration parses and returns the write timeout as a time.Duration.
-func (c *ServerConfig) WriteTimeoutDuration() time.Duration {
-	d, _ := time.ParseDuration(c.WriteTimeout)
-	return d
+// MaxBodySizeBytes parses and returns the maximum request body size in bytes.
+func (c *ServerConfig) MaxBodySizeBytes() int64 {
+	n, _ := parseByteSize(c.MaxBodySize)
+	return n
 }
 
-// ShutdownTimeoutDuration parses and returns the shutdown timeout as a time.Duration.
-func (c *ServerConfig) ShutdownTimeoutDuration() time.Duration {
-	d, _ := time.ParseDuration(c.ShutdownTimeout)
-	return d
+// TrustedProxyPrefixes parses and returns the trusted proxies as prefixes.
+// A bare address is a single-address prefix.
+func (c *ServerConfig) TrustedProxyPrefixes() []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(c.TrustedProxies))
+	for _, proxy := range c.TrustedProxies {
+		if prefix, err := parsePrefix(proxy); err == nil {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
 }
 
-// Finalize applies defaults, loads environment overrides, and validates the server configuration.
+// Finalize applies defaults, loads environment overrides, and validates the
+// server configuration, reporting every invalid field in a *ValidationError.
 func (c *ServerConfig) Finalize() error {
 	c.loadDefaults()
-	c.loadEnv()
-	return c.validate()
+
+	var errs fieldErrors
+	errs.addSection("", c.loadEnv())
+	errs.addSection("", c.validate())
+	return errs.err()
 }
 
 // Merge applies values from overlay configuration that differ from zero values.
@@ -71,35 +124,79 @@ func (c *ServerConfig) Merge(overlay *ServerConfig) {
 	if overlay.Port != 0 {
 		c.Port = overlay.Port
 	}
-	if overlay.ReadTimeout != "" {
-		c.ReadTimeout = overlay.ReadTimeout
+	c.ReadTimeout.Merge(overlay.ReadTimeout)
+	c.WriteTimeout.Merge(overlay.WriteTimeout)
+	c.ShutdownTimeout.Merge(overlay.ShutdownTimeout)
+	c.StartupTimeout.Merge(overlay.StartupTimeout)
+	if overlay.StartupTimeoutAction != "" {
+		c.StartupTimeoutAction = overlay.StartupTimeoutAction
+	}
+	c.RequestTimeout.Merge(overlay.RequestTimeout)
+	if overlay.MaxBodySize != "" {
+		c.MaxBodySize = overlay.MaxBodySize
 	}
-	if overlay.WriteTimeout != "" {
-		c.WriteTimeout = overlay.WriteTimeout
+	if len(overlay.ForwardedPrefixes) > 0 {
+		c.ForwardedPrefixes = overlay.ForwardedPrefixes
 	}
-	if overlay.ShutdownTimeout != "" {
-		c.ShutdownTimeout = overlay.ShutdownTimeout
+	if len(overlay.TrustedProxies) > 0 {
+		c.TrustedProxies = overlay.TrustedProxies
 	}
 }
 
-func (c *ServerConfig) loadEnv() {
-	if v := os.Getenv(EnvServerHost); v != "" {
+func (c *ServerConfig) loadEnv() error {
+	var r envvar.Reader
+	var errs fieldErrors
+
+	if v := r.Get(EnvServerHost); v != "" {
 		c.Host = v
 	}
-	if v := os.Getenv(EnvServerPort); v != "" {
+	if v := r.Get(EnvServerPort); v != "" {
 		if port, err := strconv.Atoi(v); err == nil {
 			c.Port = port
 		}
 	}
-	if v := os.Getenv(EnvServerReadTimeout); v != "" {
-		c.ReadTimeout = v
+	if v := r.Get(EnvServerReadTimeout); v != "" {
+		errs.add("read_timeout", parseEnvDuration(&c.ReadTimeout, EnvServerReadTimeout, v))
+	}
+	if v := r.Get(EnvServerWriteTimeout); v != "" {
+		errs.add("write_timeout", parseEnvDuration(&c.WriteTimeout, EnvServerWriteTimeout, v))
+	}
+	if v := r.Get(EnvServerShutdownTimeout); v != "" {
+		errs.add("shutdown_timeout", parseEnvDuration(&c.ShutdownTimeout, EnvServerShutdownTimeout, v))
+	}
+	if v := r.Get(EnvServerStartupTimeout); v != "" {
+		errs.add("startup_timeout", parseEnvDuration(&c.StartupTimeout, EnvServerStartupTimeout, v))
+	}
+	if v := r.Get(EnvServerStartupTimeoutAction); v != "" {
+		c.StartupTimeoutAction = v
+	}
+	if v := r.Get(EnvServerRequestTimeout); v != "" {
+		errs.add("request_timeout", parseEnvDuration(&c.RequestTimeout, EnvServerRequestTimeout, v))
+	}
+	if v := r.Get(EnvServerMaxBodySize); v != "" {
+		c.MaxBodySize = v
 	}
-	if v := os.Getenv(EnvServerWriteTimeout); v != "" {
-		c.WriteTimeout = v
+	if v := r.Get(EnvServerForwardedPrefixes); v != "" {
+		prefixes := strings.Split(v, ",")
+		c.ForwardedPrefixes = make([]string, 0, len(prefixes))
+		for _, prefix := range prefixes {
+			if trimmed := strings.TrimSpace(prefix); trimmed != "" {
+				c.ForwardedPrefixes = append(c.ForwardedPrefixes, trimmed)
+			}
+		}
 	}
-	if v := os.Getenv(EnvServerShutdownTimeout); v != "" {
-		c.ShutdownTimeout = v
+	if v := r.Get(EnvServerTrustedProxies); v != "" {
+		proxies := strings.Split(v, ",")
+		c.TrustedProxies = make([]string, 0, len(proxies))
+		for _, proxy := range proxies {
+			if trimmed := strings.TrimSpace(proxy); trimmed != "" {
+				c.TrustedProxies = append(c.TrustedProxies, trimmed)
+			}
+		}
 	}
+
+	errs.add("", r.Err())
+	return errs.err()
 }
 
 func (c *ServerConfig) loadDefaults() {
@@ -109,29 +206,92 @@ func (c *ServerConfig) loadDefaults() {
 	if c.Port == 0 {
 		c.Port = 8080
 	}
-	if c.ReadTimeout == "" {
-		c.ReadTimeout = "1m"
+	c.ReadTimeout.SetDefault(time.Minute)
+	c.WriteTimeout.SetDefault(15 * time.Minute)
+	c.ShutdownTimeout.SetDefault(30 * time.Second)
+	c.StartupTimeout.SetDefault(2 * time.Minute)
+	if c.StartupTimeoutAction == "" {
+		c.StartupTimeoutAction = StartupAbort
 	}
-	if c.WriteTimeout == "" {
-		c.WriteTimeout = "15m"
-	}
-	if c.ShutdownTimeout == "" {
-		c.ShutdownTimeout = "30s"
+	c.RequestTimeout.SetDefault(2 * time.Minute)
+	if c.MaxBodySize == "" {
+		c.MaxBodySize = "32MB"
 	}
 }
 
 func (c *ServerConfig) validate() error {
+	var errs fieldErrors
+
 	if c.Port < 1 || c.Port > 65535 {
-		return fmt.Errorf("invalid port: %d", c.Port)
+		errs.add("port", fmt.Errorf("%d is out of range (must be 1-65535)", c.Port))
+	}
+	if c.StartupTimeout.Duration < 0 {
+		errs.add("startup_timeout", fmt.Errorf("%s must not be negative", c.StartupTimeout))
+	}
+	if c.StartupTimeoutAction != StartupAbort && c.StartupTimeoutAction != StartupDegrade {
+		errs.add("startup_timeout_action", fmt.Errorf("%q must be abort or degrade", c.StartupTimeoutAction))
+	}
+	if c.RequestTimeout.Duration < 0 {
+		errs.add("request_timeout", fmt.Errorf("%s must not be negative", c.RequestTimeout))
+	}
+	if _, err := parseByteSize(c.MaxBodySize); err != nil {
+		errs.add("max_body_size", err)
+	}
+	for _, prefix := range c.ForwardedPrefixes {
+		if !strings.HasPrefix(prefix, "/") || strings.HasSuffix(prefix, "/") {
+			errs.add("forwarded_prefixes", fmt.Errorf("%q must start with / and not end with /", prefix))
+		}
+	}
+	for _, proxy := range c.TrustedProxies {
+		if _, err := parsePrefix(proxy); err != nil {
+			errs.add("trusted_proxies", fmt.Errorf("%q must be an IP address or CIDR range", proxy))
+		}
+	}
+
+	return errs.err()
+}
+
+// parsePrefix parses a CIDR range or a bare IP address.
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
 	}
-	if _, err := time.ParseDuration(c.ReadTimeout); err != nil {
-		return fmt.Errorf("invalid read_timeout: %w", err)
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+var byteUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// parseByteSize parses a size such as "512", "64KB", or "10MB". Units are
+// case-insensitive powers of 1024.
+func parseByteSize(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range byteUnits {
+		if n, ok := strings.CutSuffix(value, unit.suffix); ok {
+			value, multiplier = strings.TrimSpace(n), unit.size
+			break
+		}
 	}
-	if _, err := time.ParseDuration(c.WriteTimeout); err != nil {
-		return fmt.Errorf("invalid write_timeout: %w", err)
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q is not a byte size (e.g. 512, 64KB, 10MB)", s)
 	}
-	if _, err := time.ParseDuration(c.ShutdownTimeout); err != nil {
-		return fmt.Errorf("invalid shutdown_timeout: %w", err)
+	if n > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("%q is too large", s)
 	}
-	return nil
+	return n * multiplier, nil
 }
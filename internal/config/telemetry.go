@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/JaimeStill/go-lit/pkg/envvar"
+)
+
+const (
+	// EnvTelemetryEnabled overrides whether traces are exported.
+	EnvTelemetryEnabled = "TELEMETRY_ENABLED"
+
+	// EnvTelemetryEndpoint overrides the OTLP/HTTP collector endpoint.
+	EnvTelemetryEndpoint = "TELEMETRY_ENDPOINT"
+
+	// EnvTelemetrySampleRatio overrides the fraction of new traces sampled.
+	EnvTelemetrySampleRatio = "TELEMETRY_SAMPLE_RATIO"
+
+	// EnvTelemetryServiceName overrides the service name reported with traces.
+	EnvTelemetryServiceName = "TELEMETRY_SERVICE_NAME"
+)
+
+// TelemetryConfig contains OpenTelemetry tracing configuration.
+// Endpoint is the base URL of an OTLP/HTTP collector. SampleRatio is the
+// fraction, between 0 and 1, of traces started by this service that are
+// sampled; requests continuing a caller's trace follow the caller's decision.
+// A zero ratio is treated as unset, so disable tracing with Enabled instead.
+type TelemetryConfig struct {
+	Enabled     bool    `toml:"enabled"`
+	Endpoint    string  `toml:"endpoint"`
+	SampleRatio float64 `toml:"sample_ratio"`
+	ServiceName string  `toml:"service_name"`
+}
+
+// Finalize applies defaults, loads environment overrides, and validates the telemetry configuration.
+func (c *TelemetryConfig) Finalize() error {
+	c.loadDefaults()
+	if err := c.loadEnv(); err != nil {
+		return err
+	}
+	return c.validate()
+}
+
+// Merge applies values from overlay configuration that differ from zero
+// values. Enabled is left to Config.Merge, which only applies it when the
+// overlay sets it.
+func (c *TelemetryConfig) Merge(overlay *TelemetryConfig) {
+	if overlay.Endpoint != "" {
+		c.Endpoint = overlay.Endpoint
+	}
+	if overlay.SampleRatio > 0 {
+		c.SampleRatio = overlay.SampleRatio
+	}
+	if overlay.ServiceName != "" {
+		c.ServiceName = overlay.ServiceName
+	}
+}
+
+func (c *TelemetryConfig) loadEnv() error {
+	var r envvar.Reader
+
+	if v := r.Get(EnvTelemetryEnabled); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			c.Enabled = enabled
+		}
+	}
+	if v := r.Get(EnvTelemetryEndpoint); v != "" {
+		c.Endpoint = v
+	}
+	if v := r.Get(EnvTelemetrySampleRatio); v != "" {
+		if ratio, err := strconv.ParseFloat(v, 64); err == nil {
+			c.SampleRatio = ratio
+		}
+	}
+	if v := r.Get(EnvTelemetryServiceName); v != "" {
+		c.ServiceName = v
+	}
+
+	return r.Err()
+}
+
+func (c *TelemetryConfig) loadDefaults() {
+	if c.Endpoint == "" {
+		c.Endpoint = "http://localhost:4318"
+	}
+	if c.SampleRatio == 0 {
+		c.SampleRatio = 1
+	}
+	if c.ServiceName == "" {
+		c.ServiceName = "go-lit"
+	}
+}
+
+func (c *TelemetryConfig) validate() error {
+	if u, err := url.Parse(c.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid endpoint: %q (must be an http or https URL)", c.Endpoint)
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("invalid sample_ratio: %v (must be between 0 and 1)", c.SampleRatio)
+	}
+	return nil
+}
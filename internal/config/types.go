@@ -3,8 +3,29 @@ package config
 import (
 	"fmt"
 	"log/slog"
+	"time"
+
+	"github.com/JaimeStill/go-lit/pkg/middleware"
 )
 
+// Duration is a time.Duration written in configuration files and environment
+// variables as a string such as "30s" or "5m". It is the middleware package's
+// Duration, so sections shared with middleware convert without copying.
+type Duration = middleware.Duration
+
+// NewDuration returns d as a set Duration.
+func NewDuration(d time.Duration) Duration {
+	return middleware.NewDuration(d)
+}
+
+// parseEnvDuration sets d from the value v of environment variable name.
+func parseEnvDuration(d *Duration, name, v string) error {
+	if err := d.UnmarshalText([]byte(v)); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
 // LogLevel represents the minimum severity level for log output.
 type LogLevel string
 
@@ -66,4 +87,3 @@ func (f LogFormat) Validate() error {
 		return fmt.Errorf("invalid log format: %s (must be text or json)", f)
 	}
 }
-
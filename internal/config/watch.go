@@ -0,0 +1,96 @@
+package config
+
+import (
+	"context"
+	"encoding"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// WatchInterval is how often Watch checks the configuration files for changes.
+const WatchInterval = 2 * time.Second
+
+// Watch reloads the configuration with LoadFrom whenever the base file at
+// path or one of its environment overlays is created, modified, or removed, until
+// ctx is done. A configuration that loads is passed to onChange; one that
+// fails to load or validate is passed to onError as a whole and never
+// partially applied. Files are polled every WatchInterval.
+func Watch(ctx context.Context, path string, onChange func(*Config), onError func(error)) {
+	files := append([]string{path}, overlayPaths(filepath.Dir(path))...)
+
+	last := fileStates(files)
+	ticker := time.NewTicker(WatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := fileStates(files)
+			if reflect.DeepEqual(current, last) {
+				continue
+			}
+			last = current
+
+			cfg, err := LoadFrom(path)
+			if err != nil {
+				onError(err)
+				continue
+			}
+			onChange(cfg)
+		}
+	}
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+func fileStates(files []string) []fileState {
+	states := make([]fileState, len(files))
+	for i, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			states[i] = fileState{modTime: info.ModTime(), size: info.Size(), exists: true}
+		}
+	}
+	return states
+}
+
+// Diff returns the TOML keys of the settings that differ between a and b,
+// such as "server.port" or "api.cors.origins", in declaration order.
+func Diff(a, b *Config) []string {
+	return diffValues(reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem(), "")
+}
+
+var textMarshaler = reflect.TypeFor[encoding.TextMarshaler]()
+
+func diffValues(a, b reflect.Value, prefix string) []string {
+	var keys []string
+	for i := range a.NumField() {
+		field := a.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("toml"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		key := prefix + name
+
+		fa, fb := a.Field(i), b.Field(i)
+		if field.Type.Kind() == reflect.Struct && !field.Type.Implements(textMarshaler) {
+			keys = append(keys, diffValues(fa, fb, key+".")...)
+			continue
+		}
+		if !reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,56 @@
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/JaimeStill/go-lit/pkg/handlers"
+	"github.com/JaimeStill/go-lit/pkg/pagination"
+	"github.com/JaimeStill/go-lit/pkg/routes"
+)
+
+var (
+	ErrNotFound         = errors.New("template not found")
+	ErrConflict         = errors.New("template name already exists")
+	ErrInvalidTemplate  = errors.New("invalid template")
+	ErrInvalidVariables = errors.New("invalid variables")
+	ErrRender           = errors.New("render error")
+)
+
+// VariableError reports the variables that failed validation at render time.
+// Fields are request field paths, such as "variables.topic".
+type VariableError struct {
+	Reason string
+	Fields []string
+}
+
+func (e *VariableError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrInvalidVariables, e.Reason, strings.Join(e.Fields, ", "))
+}
+
+func (e *VariableError) Unwrap() error {
+	return ErrInvalidVariables
+}
+
+func MapHTTPStatus(err error) int {
+	switch {
+	case errors.Is(err, handlers.ErrBodyTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidTemplate),
+		errors.Is(err, pagination.ErrInvalidSort),
+		errors.Is(err, pagination.ErrInvalidFilter),
+		errors.Is(err, routes.ErrInvalidParam),
+		errors.Is(err, routes.ErrMissingParam):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrInvalidVariables):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
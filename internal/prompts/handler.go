@@ -0,0 +1,133 @@
+package prompts
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/JaimeStill/go-lit/pkg/handlers"
+	"github.com/JaimeStill/go-lit/pkg/module"
+	"github.com/JaimeStill/go-lit/pkg/pagination"
+	"github.com/JaimeStill/go-lit/pkg/routes"
+)
+
+type Handler struct {
+	store      *Store
+	logger     *slog.Logger
+	pagination pagination.Config
+}
+
+func NewHandler(store *Store, logger *slog.Logger, pagination pagination.Config) *Handler {
+	return &Handler{
+		store:      store,
+		logger:     logger,
+		pagination: pagination,
+	}
+}
+
+func (h *Handler) Routes() routes.Group {
+	return routes.Group{
+		Prefix:      "/prompts",
+		Tags:        []string{"Prompts"},
+		Description: "Named prompt templates with variable substitution",
+		Schemas:     Schemas,
+		Routes: []routes.Route{
+			{Method: "GET", Pattern: "", Handler: h.List, OpenAPI: listOperation(h.pagination)},
+			{Method: "POST", Pattern: "", Handler: h.Create, OpenAPI: Spec.Create},
+			{Method: "GET", Pattern: "/{id}", Handler: h.Find, OpenAPI: Spec.Find},
+			{Method: "PUT", Pattern: "/{id}", Handler: h.Update, OpenAPI: Spec.Update},
+			{Method: "DELETE", Pattern: "/{id}", Handler: h.Delete, OpenAPI: Spec.Delete},
+		},
+	}
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	page := pagination.PageRequestFromQuery(r.URL.Query(), h.pagination)
+	filters, err := pagination.ParseFilters(r.URL.Query(), FilterSpecs...)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	result, err := h.store.List(page, filters)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	base := &url.URL{
+		Path:     strings.TrimSuffix(module.ExternalPrefixFromContext(r.Context()), "/") + r.URL.Path,
+		RawQuery: r.URL.RawQuery,
+	}
+	result.WriteLinkHeader(w, base)
+	handlers.RespondJSON(w, http.StatusOK, result)
+}
+
+func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
+	id, err := routes.PathUUID(r, "id")
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	t, err := h.store.Find(id.String())
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+	handlers.RespondJSON(w, http.StatusOK, t)
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var cmd TemplateCommand
+	if err := handlers.DecodeJSON(r, &cmd); err != nil {
+		err = fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	t, err := h.store.Create(cmd)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+	handlers.RespondJSON(w, http.StatusCreated, t)
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := routes.PathUUID(r, "id")
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	var cmd TemplateCommand
+	if err := handlers.DecodeJSON(r, &cmd); err != nil {
+		err = fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	t, err := h.store.Update(id.String(), cmd)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+	handlers.RespondJSON(w, http.StatusOK, t)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := routes.PathUUID(r, "id")
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	if err := h.store.Delete(id.String()); err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
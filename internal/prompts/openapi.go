@@ -0,0 +1,143 @@
+package prompts
+
+import (
+	"maps"
+
+	"github.com/JaimeStill/go-lit/pkg/openapi"
+	"github.com/JaimeStill/go-lit/pkg/pagination"
+)
+
+var Spec = struct {
+	List   *openapi.Operation
+	Find   *openapi.Operation
+	Create *openapi.Operation
+	Update *openapi.Operation
+	Delete *openapi.Operation
+}{
+	List: &openapi.Operation{
+		OperationID: "listPrompts",
+		Summary:     "List prompt templates",
+		Description: "Return a page of prompt templates, optionally filtered by name or description",
+		Responses: openapi.Responses{
+			200: openapi.ResponseJSON("Page of prompt templates", "TemplateDefinitionPage"),
+			400: openapi.ErrBadRequest(),
+		},
+	},
+	Find: &openapi.Operation{
+		OperationID: "getPrompt",
+		Summary:     "Get prompt template",
+		Parameters:  []*openapi.Parameter{openapi.PathParam("id", "Template ID")},
+		Responses: openapi.Responses{
+			200: openapi.ResponseJSON("Prompt template", "TemplateDefinition"),
+			404: openapi.ErrNotFound(),
+		},
+	},
+	Create: &openapi.Operation{
+		OperationID: "createPrompt",
+		Summary:     "Create prompt template",
+		Description: "Validate and store a prompt template. The body must parse as a Go text/template and reference only declared variables.",
+		RequestBody: openapi.RequestBodyJSON("TemplateCommand", true),
+		Responses: openapi.Responses{
+			201: withLinks(openapi.ResponseJSON("Created prompt template", "TemplateDefinition"), templateLinks),
+			400: openapi.ErrBadRequest(),
+			409: openapi.ErrConflict(),
+		},
+	},
+	Update: &openapi.Operation{
+		OperationID: "updatePrompt",
+		Summary:     "Update prompt template",
+		Parameters:  []*openapi.Parameter{openapi.PathParam("id", "Template ID")},
+		RequestBody: openapi.RequestBodyJSON("TemplateCommand", true),
+		Responses: openapi.Responses{
+			200: openapi.ResponseJSON("Updated prompt template", "TemplateDefinition"),
+			400: openapi.ErrBadRequest(),
+			404: openapi.ErrNotFound(),
+			409: openapi.ErrConflict(),
+		},
+	},
+	Delete: &openapi.Operation{
+		OperationID: "deletePrompt",
+		Summary:     "Delete prompt template",
+		Parameters:  []*openapi.Parameter{openapi.PathParam("id", "Template ID")},
+		Responses: openapi.Responses{
+			204: {Description: "Template deleted"},
+			404: openapi.ErrNotFound(),
+		},
+	},
+}
+
+// listOperation documents List with the page parameters accepted under cfg,
+// its filters, and the page headers it writes.
+func listOperation(cfg pagination.Config) *openapi.Operation {
+	op := *Spec.List
+	op.Parameters = append(openapi.PageQueryParams(cfg), openapi.FilterQueryParams(FilterSpecs...)...)
+	page := *op.Responses[200]
+	page.Headers = openapi.PageHeaders()
+	op.Responses = maps.Clone(op.Responses)
+	op.Responses[200] = &page
+	return &op
+}
+
+// templateLinks connect a returned template to the operations addressed by its id.
+var templateLinks = map[string]*openapi.Link{
+	"GetPrompt": {
+		OperationID: "getPrompt",
+		Parameters:  map[string]any{"id": "$response.body#/id"},
+		Description: "Retrieve the created template",
+	},
+	"UpdatePrompt": {
+		OperationID: "updatePrompt",
+		Parameters:  map[string]any{"id": "$response.body#/id"},
+		Description: "Update the created template",
+	},
+	"DeletePrompt": {
+		OperationID: "deletePrompt",
+		Parameters:  map[string]any{"id": "$response.body#/id"},
+		Description: "Delete the created template",
+	},
+}
+
+func withLinks(r *openapi.Response, links map[string]*openapi.Link) *openapi.Response {
+	r.Links = links
+	return r
+}
+
+var variableSchema = &openapi.Schema{
+	Type:     "object",
+	Required: []string{"name", "type"},
+	Properties: map[string]*openapi.Schema{
+		"name":        {Type: "string", Description: "Variable name referenced in the body as {{ .name }}"},
+		"type":        openapi.EnumOf(TypeString, TypeNumber, TypeInteger, TypeBoolean),
+		"required":    {Type: "boolean", Description: "Whether the variable must be provided at render time"},
+		"default":     {Description: "Value used when the variable is not provided"},
+		"description": {Type: "string"},
+	},
+}
+
+var Schemas = map[string]*openapi.Schema{
+	"TemplateVariable": variableSchema,
+	"TemplateCommand": {
+		Type:     "object",
+		Required: []string{"name", "body"},
+		Properties: map[string]*openapi.Schema{
+			"name":        {Type: "string", Description: "Unique template name"},
+			"description": {Type: "string"},
+			"body":        {Type: "string", Description: "Go text/template body"},
+			"variables":   {Type: "array", Items: openapi.SchemaRef("TemplateVariable")},
+		},
+	},
+	"TemplateDefinition": {
+		Type:     "object",
+		Required: []string{"id", "name", "body", "variables", "created_at", "updated_at"},
+		Properties: map[string]*openapi.Schema{
+			"id":          {Type: "string", Format: "uuid", ReadOnly: true},
+			"name":        {Type: "string"},
+			"description": {Type: "string"},
+			"body":        {Type: "string", Description: "Go text/template body"},
+			"variables":   {Type: "array", Items: openapi.SchemaRef("TemplateVariable")},
+			"created_at":  {Type: "string", Format: "date-time", ReadOnly: true},
+			"updated_at":  {Type: "string", Format: "date-time", ReadOnly: true},
+		},
+	},
+	"TemplateDefinitionPage": openapi.PageResultSchema("TemplateDefinition"),
+}
@@ -0,0 +1,42 @@
+// Package prompts manages named prompt templates with declared variables.
+// Template bodies use Go text/template syntax and are validated at save time
+// against their variable declarations.
+package prompts
+
+import "time"
+
+// Variable types supported by template variable declarations.
+const (
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+)
+
+// Variable declares a named value that a template body may reference.
+type Variable struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required,omitempty"`
+	Default     any    `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// TemplateDefinition is a stored prompt template.
+type TemplateDefinition struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Body        string     `json:"body"`
+	Variables   []Variable `json:"variables"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TemplateCommand contains the fields for creating or updating a template.
+type TemplateCommand struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Body        string     `json:"body"`
+	Variables   []Variable `json:"variables"`
+}
@@ -0,0 +1,215 @@
+package prompts
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JaimeStill/go-lit/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+// SortFields are the fields List accepts in a sort expression.
+var SortFields = []string{"name", "created_at", "updated_at"}
+
+// FilterSpecs declare the filters List accepts.
+var FilterSpecs = []pagination.FilterSpec{
+	{Field: "name", Type: pagination.FilterString, Operators: []pagination.Operator{pagination.OpEq, pagination.OpContains}},
+	{Field: "created_at", Type: pagination.FilterTime, Operators: []pagination.Operator{pagination.OpGte, pagination.OpLt}},
+	{Field: "updated_at", Type: pagination.FilterTime, Operators: []pagination.Operator{pagination.OpGte, pagination.OpLt}},
+}
+
+var filterFields = map[string]func(TemplateDefinition) any{
+	"name":       func(t TemplateDefinition) any { return t.Name },
+	"created_at": func(t TemplateDefinition) any { return t.CreatedAt },
+	"updated_at": func(t TemplateDefinition) any { return t.UpdatedAt },
+}
+
+// Store holds prompt templates in memory, keyed by ID with unique names.
+// It is safe for concurrent use.
+type Store struct {
+	mu        sync.RWMutex
+	templates map[string]*TemplateDefinition
+}
+
+// NewStore creates an empty Store.
+func NewStore() *Store {
+	return &Store{templates: make(map[string]*TemplateDefinition)}
+}
+
+// List returns a page of templates whose name or description matches the
+// search term and that satisfy filters parsed with FilterSpecs. Sort accepts
+// any of SortFields, with a - prefix for descending order, and defaults to
+// name; other fields are rejected with pagination.ErrInvalidSort.
+func (s *Store) List(page pagination.PageRequest, filters pagination.Filters) (pagination.PageResult[TemplateDefinition], error) {
+	sorts, err := page.Sorts(SortFields...)
+	if err != nil {
+		return pagination.PageResult[TemplateDefinition]{}, err
+	}
+
+	s.mu.RLock()
+	matches := make([]TemplateDefinition, 0, len(s.templates))
+	search := strings.ToLower(page.Search)
+	for _, t := range s.templates {
+		if search == "" ||
+			strings.Contains(strings.ToLower(t.Name), search) ||
+			strings.Contains(strings.ToLower(t.Description), search) {
+			matches = append(matches, t.clone())
+		}
+	}
+	s.mu.RUnlock()
+
+	matches = pagination.ApplyFilters(matches, filters, filterFields)
+	slices.SortFunc(matches, compareBy(sorts))
+
+	return pagination.ApplyPage(matches, page), nil
+}
+
+// Find returns the template with the given ID.
+func (s *Store) Find(id string) (*TemplateDefinition, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.templates[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	result := t.clone()
+	return &result, nil
+}
+
+// FindByName returns the template with the given name.
+func (s *Store) FindByName(name string) (*TemplateDefinition, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, t := range s.templates {
+		if t.Name == name {
+			result := t.clone()
+			return &result, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+}
+
+// Create validates and stores a new template.
+func (s *Store) Create(cmd TemplateCommand) (*TemplateDefinition, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.nameTaken(cmd.Name, "") {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, cmd.Name)
+	}
+
+	now := time.Now().UTC()
+	t := &TemplateDefinition{
+		ID:          uuid.Must(uuid.NewV7()).String(),
+		Name:        cmd.Name,
+		Description: cmd.Description,
+		Body:        cmd.Body,
+		Variables:   slices.Clone(cmd.Variables),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	s.templates[t.ID] = t
+
+	result := t.clone()
+	return &result, nil
+}
+
+// Update validates and replaces the template with the given ID.
+func (s *Store) Update(id string, cmd TemplateCommand) (*TemplateDefinition, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.templates[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if s.nameTaken(cmd.Name, id) {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, cmd.Name)
+	}
+
+	t.Name = cmd.Name
+	t.Description = cmd.Description
+	t.Body = cmd.Body
+	t.Variables = slices.Clone(cmd.Variables)
+	t.UpdatedAt = time.Now().UTC()
+
+	result := t.clone()
+	return &result, nil
+}
+
+// Delete removes the template with the given ID.
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.templates[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	delete(s.templates, id)
+	return nil
+}
+
+// Render renders the named template with the provided variables.
+func (s *Store) Render(name string, variables map[string]any) (string, error) {
+	t, err := s.FindByName(name)
+	if err != nil {
+		return "", err
+	}
+	return t.Render(variables)
+}
+
+// clone copies t with its own Variables, so callers cannot change a stored
+// template through the returned slice.
+func (t *TemplateDefinition) clone() TemplateDefinition {
+	c := *t
+	c.Variables = slices.Clone(t.Variables)
+	return c
+}
+
+func (s *Store) nameTaken(name, exceptID string) bool {
+	for id, t := range s.templates {
+		if id != exceptID && t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// compareBy orders templates by each sort field in turn, breaking remaining
+// ties by name.
+func compareBy(sorts []pagination.SortField) func(a, b TemplateDefinition) int {
+	return func(a, b TemplateDefinition) int {
+		for _, sort := range sorts {
+			var c int
+			switch sort.Field {
+			case "created_at":
+				c = a.CreatedAt.Compare(b.CreatedAt)
+			case "updated_at":
+				c = a.UpdatedAt.Compare(b.UpdatedAt)
+			case "name":
+				c = cmp.Compare(a.Name, b.Name)
+			}
+			if sort.Descending {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(a.Name, b.Name)
+	}
+}
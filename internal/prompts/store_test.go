@@ -0,0 +1,126 @@
+package prompts
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStoreRejectsInvalidTemplatesOnSave(t *testing.T) {
+	store := NewStore()
+	valid := TemplateCommand{
+		Name:      "summary",
+		Body:      "Summarize {{.Text}}",
+		Variables: []Variable{{Name: "Text", Type: TypeString, Required: true}},
+	}
+	created, err := store.Create(valid)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"undeclared field", "Summarize {{.Missing}}"},
+		{"undeclared root variable", "{{range .Text}}{{$.Missing}}{{end}}"},
+		{"parse error", "Summarize {{.Text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := valid
+			cmd.Name = "other"
+			cmd.Body = tt.body
+			if _, err := store.Create(cmd); !errors.Is(err, ErrInvalidTemplate) {
+				t.Errorf("Create() error = %v, want ErrInvalidTemplate", err)
+			}
+
+			cmd.Name = valid.Name
+			if _, err := store.Update(created.ID, cmd); !errors.Is(err, ErrInvalidTemplate) {
+				t.Errorf("Update() error = %v, want ErrInvalidTemplate", err)
+			}
+		})
+	}
+
+	if _, err := store.FindByName("other"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FindByName(other) error = %v, want ErrNotFound after rejected saves", err)
+	}
+	if got, _ := store.Find(created.ID); got.Body != valid.Body {
+		t.Errorf("Body = %q, want unchanged %q after rejected updates", got.Body, valid.Body)
+	}
+}
+
+func TestStoreCopiesVariables(t *testing.T) {
+	store := NewStore()
+	cmd := TemplateCommand{
+		Name:      "summary",
+		Body:      "Summarize {{.Text}}",
+		Variables: []Variable{{Name: "Text", Type: TypeString, Required: true}},
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(t *testing.T, id string)
+	}{
+		{
+			name: "command after Create",
+			mutate: func(t *testing.T, id string) {
+				cmd.Variables[0].Name = "Changed"
+			},
+		},
+		{
+			name: "result of Find",
+			mutate: func(t *testing.T, id string) {
+				got, err := store.Find(id)
+				if err != nil {
+					t.Fatalf("Find() error = %v", err)
+				}
+				got.Variables[0].Name = "Changed"
+			},
+		},
+		{
+			name: "result of FindByName",
+			mutate: func(t *testing.T, id string) {
+				got, err := store.FindByName("summary")
+				if err != nil {
+					t.Fatalf("FindByName() error = %v", err)
+				}
+				got.Variables[0].Name = "Changed"
+			},
+		},
+		{
+			name: "result of Update",
+			mutate: func(t *testing.T, id string) {
+				update := cmd
+				update.Variables = []Variable{{Name: "Text", Type: TypeString, Required: true}}
+				got, err := store.Update(id, update)
+				if err != nil {
+					t.Fatalf("Update() error = %v", err)
+				}
+				got.Variables[0].Name = "Changed"
+				update.Variables[0].Name = "Changed"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.Variables = []Variable{{Name: "Text", Type: TypeString, Required: true}}
+			created, err := store.Create(cmd)
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			defer store.Delete(created.ID)
+
+			tt.mutate(t, created.ID)
+
+			got, err := store.Find(created.ID)
+			if err != nil {
+				t.Fatalf("Find() error = %v", err)
+			}
+			if name := got.Variables[0].Name; name != "Text" {
+				t.Errorf("stored variable name = %q, want %q", name, "Text")
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// MaxRenderedSize caps the size in bytes of a rendered template.
+const MaxRenderedSize = 64 << 10
+
+// funcs is the restricted set of functions available to template bodies.
+// It deliberately excludes anything with file, environment, or network access.
+var funcs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"join":  strings.Join,
+}
+
+var errRenderLimit = fmt.Errorf("rendered output exceeds %d bytes", MaxRenderedSize)
+
+// Validate checks that a command declares well-formed variables and that its
+// body parses and references only declared variables.
+func (cmd *TemplateCommand) Validate() error {
+	if strings.TrimSpace(cmd.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidTemplate)
+	}
+
+	declared := make(map[string]bool, len(cmd.Variables))
+	for i, v := range cmd.Variables {
+		if v.Name == "" {
+			return fmt.Errorf("%w: variables[%d]: name is required", ErrInvalidTemplate, i)
+		}
+		if declared[v.Name] {
+			return fmt.Errorf("%w: variables[%d]: duplicate variable %s", ErrInvalidTemplate, i, v.Name)
+		}
+		declared[v.Name] = true
+
+		if err := checkType(v.Type, v.Default); err != nil {
+			return fmt.Errorf("%w: variables[%d]: %v", ErrInvalidTemplate, i, err)
+		}
+	}
+
+	t, err := parseBody(cmd.Name, cmd.Body)
+	if err != nil {
+		return fmt.Errorf("%w: body: %v", ErrInvalidTemplate, err)
+	}
+
+	if err := checkRanges(t); err != nil {
+		return fmt.Errorf("%w: body: %v", ErrInvalidTemplate, err)
+	}
+
+	var undeclared []string
+	for _, name := range referencedFields(t.Tree.Root) {
+		if !declared[name] && !slices.Contains(undeclared, name) {
+			undeclared = append(undeclared, name)
+		}
+	}
+	if len(undeclared) > 0 {
+		return fmt.Errorf("%w: body references undeclared variables: %s", ErrInvalidTemplate, strings.Join(undeclared, ", "))
+	}
+
+	return nil
+}
+
+// Render executes the template body with the provided variables.
+// Declared defaults fill in absent values; missing required or mistyped
+// variables return a *VariableError naming each offending field.
+func (d *TemplateDefinition) Render(variables map[string]any) (string, error) {
+	data := make(map[string]any, len(d.Variables))
+	var missing, mistyped []string
+
+	for _, v := range d.Variables {
+		value, ok := variables[v.Name]
+		if !ok || value == nil {
+			switch {
+			case v.Default != nil:
+				value = v.Default
+			case v.Required:
+				missing = append(missing, "variables."+v.Name)
+				continue
+			default:
+				value = zeroValue(v.Type)
+			}
+		}
+
+		if err := checkType(v.Type, value); err != nil {
+			mistyped = append(mistyped, "variables."+v.Name)
+			continue
+		}
+		data[v.Name] = value
+	}
+
+	if len(missing) > 0 {
+		return "", &VariableError{Reason: "missing required variables", Fields: missing}
+	}
+	if len(mistyped) > 0 {
+		return "", &VariableError{Reason: "variables do not match declared types", Fields: mistyped}
+	}
+
+	t, err := parseBody(d.Name, d.Body)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrRender, err)
+	}
+
+	var out limitedBuilder
+	if err := t.Execute(&out, data); err != nil {
+		if errors.Is(err, errRenderLimit) {
+			return "", fmt.Errorf("%w: %v", ErrInvalidVariables, errRenderLimit)
+		}
+		return "", fmt.Errorf("%w: %v", ErrRender, err)
+	}
+
+	return out.String(), nil
+}
+
+func parseBody(name, body string) (*template.Template, error) {
+	return template.New(name).
+		Funcs(funcs).
+		Option("missingkey=error").
+		Parse(body)
+}
+
+// referencedFields collects the top-level variable names a template reads,
+// either from dot or from the root variable $, as in {{.Name}} or
+// {{$.Name}}. Range and with bodies rebind dot, so only their $-rooted
+// references are collected.
+func referencedFields(node parse.Node) []string {
+	var names []string
+
+	var walk func(n parse.Node, rooted bool)
+	walk = func(n parse.Node, rooted bool) {
+		switch n := n.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child, rooted)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe, rooted)
+		case *parse.IfNode:
+			walk(n.Pipe, rooted)
+			walk(n.List, rooted)
+			walk(n.ElseList, rooted)
+		case *parse.RangeNode:
+			walk(n.Pipe, rooted)
+			walk(n.List, false)
+			walk(n.ElseList, rooted)
+		case *parse.WithNode:
+			walk(n.Pipe, rooted)
+			walk(n.List, false)
+			walk(n.ElseList, rooted)
+		case *parse.TemplateNode:
+			walk(n.Pipe, rooted)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd, rooted)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg, rooted)
+			}
+		case *parse.FieldNode:
+			if rooted {
+				names = append(names, n.Ident[0])
+			}
+		case *parse.VariableNode:
+			if n.Ident[0] == "$" && len(n.Ident) > 1 {
+				names = append(names, n.Ident[1])
+			}
+		case *parse.ChainNode:
+			if isRoot(n.Node, rooted) {
+				names = append(names, n.Field[0])
+			}
+			walk(n.Node, rooted)
+		}
+	}
+
+	walk(node, true)
+	return names
+}
+
+// checkRanges rejects range actions over anything but the template data or
+// its fields. Variables are scalars, so only an integer from a literal, a
+// local variable, or a function could drive a loop, and a loop that writes
+// nothing never reaches MaxRenderedSize to stop it.
+func checkRanges(t *template.Template) error {
+	for _, tmpl := range t.Templates() {
+		if tmpl.Tree == nil {
+			continue
+		}
+		if err := checkRangeNodes(tmpl.Tree.Root, tmpl.Name() == t.Name()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkRangeNodes(node parse.Node, rooted bool) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkRangeNodes(child, rooted); err != nil {
+				return err
+			}
+		}
+	case *parse.IfNode:
+		if err := checkRangeNodes(n.List, rooted); err != nil {
+			return err
+		}
+		return checkRangeNodes(n.ElseList, rooted)
+	case *parse.RangeNode:
+		if !rangesOverData(n.Pipe, rooted) {
+			return fmt.Errorf("range over %s: only the template data may be ranged over", n.Pipe)
+		}
+		if err := checkRangeNodes(n.List, false); err != nil {
+			return err
+		}
+		return checkRangeNodes(n.ElseList, rooted)
+	case *parse.WithNode:
+		if err := checkRangeNodes(n.List, false); err != nil {
+			return err
+		}
+		return checkRangeNodes(n.ElseList, rooted)
+	}
+	return nil
+}
+
+// rangesOverData reports whether pipe reads the template data directly: a
+// field, the root variable $ or a field of it, or dot where it has not been
+// rebound.
+func rangesOverData(pipe *parse.PipeNode, rooted bool) bool {
+	if len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+	switch n := pipe.Cmds[0].Args[0].(type) {
+	case *parse.FieldNode:
+		return true
+	case *parse.VariableNode:
+		return n.Ident[0] == "$"
+	case *parse.DotNode:
+		return rooted
+	}
+	return false
+}
+
+// isRoot reports whether node evaluates to the template data: the root
+// variable $, or dot where it has not been rebound, possibly parenthesized.
+func isRoot(node parse.Node, rooted bool) bool {
+	switch n := node.(type) {
+	case *parse.VariableNode:
+		return len(n.Ident) == 1 && n.Ident[0] == "$"
+	case *parse.DotNode:
+		return rooted
+	case *parse.PipeNode:
+		return len(n.Decl) == 0 && len(n.Cmds) == 1 && len(n.Cmds[0].Args) == 1 && isRoot(n.Cmds[0].Args[0], rooted)
+	}
+	return false
+}
+
+func checkType(typ string, value any) error {
+	switch typ {
+	case TypeString, TypeNumber, TypeInteger, TypeBoolean:
+	default:
+		return fmt.Errorf("unsupported type %q (must be string, number, integer, or boolean)", typ)
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	var ok bool
+	switch typ {
+	case TypeString:
+		_, ok = value.(string)
+	case TypeNumber:
+		_, ok = value.(float64)
+	case TypeInteger:
+		f, isNumber := value.(float64)
+		ok = isNumber && f == math.Trunc(f)
+	case TypeBoolean:
+		_, ok = value.(bool)
+	}
+
+	if !ok {
+		return fmt.Errorf("value %v is not of type %s", value, typ)
+	}
+	return nil
+}
+
+func zeroValue(typ string) any {
+	switch typ {
+	case TypeNumber, TypeInteger:
+		return float64(0)
+	case TypeBoolean:
+		return false
+	default:
+		return ""
+	}
+}
+
+// limitedBuilder accumulates output and fails once MaxRenderedSize is exceeded.
+type limitedBuilder struct {
+	strings.Builder
+}
+
+func (b *limitedBuilder) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > MaxRenderedSize {
+		return 0, errRenderLimit
+	}
+	return b.Builder.Write(p)
+}
@@ -0,0 +1,157 @@
+package prompts
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTemplateCommandValidate(t *testing.T) {
+	vars := []Variable{
+		{Name: "Topic", Type: TypeString, Required: true},
+		{Name: "Items", Type: TypeString},
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		undeclared string
+	}{
+		{name: "declared field", body: "Explain {{.Topic}}"},
+		{name: "declared root variable", body: "Explain {{$.Topic}}"},
+		{name: "range body rebinds dot", body: "{{range .Items}}{{.Anything}}{{end}}"},
+		{name: "declared root variable in range", body: "{{range .Items}}{{$.Topic}}{{end}}"},
+		{name: "local variable", body: "{{$t := .Topic}}{{$t}}"},
+		{name: "undeclared field", body: "Explain {{.Missing}}", undeclared: "Missing"},
+		{name: "undeclared root variable", body: "Explain {{$.Missing}}", undeclared: "Missing"},
+		{name: "undeclared root variable in range", body: "{{range .Items}}{{$.Undeclared}}{{end}}", undeclared: "Undeclared"},
+		{name: "undeclared root variable in with", body: "{{with .Topic}}{{$.Other | upper}}{{end}}", undeclared: "Other"},
+		{name: "undeclared field in if", body: "{{if .Topic}}{{.Missing}}{{end}}", undeclared: "Missing"},
+		{name: "undeclared root chain", body: "{{($).Missing}}", undeclared: "Missing"},
+		{name: "undeclared dot chain", body: "{{(.).Missing}}", undeclared: "Missing"},
+		{name: "dot chain in range", body: "{{range .Items}}{{(.).Anything}}{{end}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := TemplateCommand{Name: "t", Body: tt.body, Variables: vars}
+			err := cmd.Validate()
+
+			if tt.undeclared == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidTemplate) {
+				t.Fatalf("Validate() error = %v, want ErrInvalidTemplate", err)
+			}
+			if !strings.Contains(err.Error(), "undeclared variables: "+tt.undeclared) {
+				t.Errorf("Validate() error = %v, want naming %s", err, tt.undeclared)
+			}
+		})
+	}
+}
+
+func TestTemplateCommandValidateRanges(t *testing.T) {
+	vars := []Variable{{Name: "Items", Type: TypeString}}
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "field", body: "{{range .Items}}{{end}}"},
+		{name: "root field", body: "{{range $.Items}}{{end}}"},
+		{name: "root data", body: "{{range $k, $v := .}}{{$k}}{{end}}"},
+		{name: "integer literal", body: "{{range 1000000000}}{{end}}", wantErr: true},
+		{name: "declared integer literal", body: "{{range $i := 1000000000}}{{end}}", wantErr: true},
+		{name: "local constant", body: "{{$n := 1000000000}}{{range $n}}{{end}}", wantErr: true},
+		{name: "function result", body: "{{range len .Items}}{{end}}", wantErr: true},
+		{name: "parenthesized literal", body: "{{range (1000000000)}}{{end}}", wantErr: true},
+		{name: "rebound dot", body: "{{with 1000000000}}{{range .}}{{end}}{{end}}", wantErr: true},
+		{name: "nested literal", body: "{{range .Items}}{{range 1000}}{{end}}{{end}}", wantErr: true},
+		{name: "in else branch", body: "{{if .Items}}{{else}}{{range 1000}}{{end}}{{end}}", wantErr: true},
+		{name: "in defined template", body: `{{define "loop"}}{{range 1000}}{{end}}{{end}}{{template "loop"}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := TemplateCommand{Name: "t", Body: tt.body, Variables: vars}
+			err := cmd.Validate()
+
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidTemplate) {
+				t.Fatalf("Validate() error = %v, want ErrInvalidTemplate", err)
+			}
+			if !strings.Contains(err.Error(), "range over") {
+				t.Errorf("Validate() error = %v, want naming the range", err)
+			}
+		})
+	}
+}
+
+func TestTemplateDefinitionRender(t *testing.T) {
+	def := TemplateDefinition{
+		Name: "t",
+		Body: "{{.Greeting}}, {{.Name | upper}}{{if .Loud}}!{{end}}",
+		Variables: []Variable{
+			{Name: "Greeting", Type: TypeString, Default: "Hello"},
+			{Name: "Name", Type: TypeString, Required: true},
+			{Name: "Loud", Type: TypeBoolean},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		variables map[string]any
+		want      string
+		fields    []string
+	}{
+		{name: "defaults", variables: map[string]any{"Name": "ada"}, want: "Hello, ADA"},
+		{name: "all set", variables: map[string]any{"Greeting": "Hi", "Name": "ada", "Loud": true}, want: "Hi, ADA!"},
+		{name: "missing required", variables: map[string]any{}, fields: []string{"variables.Name"}},
+		{name: "mistyped", variables: map[string]any{"Name": "ada", "Loud": "yes"}, fields: []string{"variables.Loud"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := def.Render(tt.variables)
+
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("Render() error = %v", err)
+				}
+				if got != tt.want {
+					t.Errorf("Render() = %q, want %q", got, tt.want)
+				}
+				return
+			}
+			var verr *VariableError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Render() error = %v, want *VariableError", err)
+			}
+			if strings.Join(verr.Fields, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("Fields = %v, want %v", verr.Fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestTemplateDefinitionRenderLimit(t *testing.T) {
+	def := TemplateDefinition{
+		Name:      "t",
+		Body:      "{{.Text}}{{.Text}}",
+		Variables: []Variable{{Name: "Text", Type: TypeString}},
+	}
+
+	_, err := def.Render(map[string]any{"Text": strings.Repeat("x", MaxRenderedSize)})
+	if !errors.Is(err, ErrInvalidVariables) {
+		t.Errorf("Render() error = %v, want ErrInvalidVariables", err)
+	}
+}
@@ -0,0 +1,56 @@
+// Package envvar reads configuration overrides from environment variables,
+// following the convention of container secrets mounted as files: for a
+// variable FOO, a path in FOO_FILE supplies the value instead.
+package envvar
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FileSuffix is appended to a variable's name to name the variable holding
+// the path of a file with its value.
+const FileSuffix = "_FILE"
+
+// Lookup returns the value of the environment variable name. When
+// name_FILE is set, the value is read from the file at that path instead,
+// with surrounding whitespace trimmed, and an unreadable file is an error.
+// An empty name or an unset variable returns an empty value.
+func Lookup(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	if path := os.Getenv(name + FileSuffix); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("%s%s: %w", name, FileSuffix, err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return os.Getenv(name), nil
+}
+
+// Reader reads a sequence of variables with Lookup, collecting errors so a
+// configuration section can read all of its overrides and check once.
+// The zero value is ready to use.
+type Reader struct {
+	errs []error
+}
+
+// Get returns the value of name as Lookup does, recording any error and
+// returning an empty value in its place.
+func (r *Reader) Get(name string) string {
+	v, err := Lookup(name)
+	if err != nil {
+		r.errs = append(r.errs, err)
+		return ""
+	}
+	return v
+}
+
+// Err returns the errors recorded by Get, joined, or nil.
+func (r *Reader) Err() error {
+	return errors.Join(r.errs...)
+}
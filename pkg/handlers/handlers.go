@@ -2,19 +2,104 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
+// ErrBodyTooLarge reports a request body that exceeded the limit set by
+// http.MaxBytesReader, as applied by middleware.MaxBytes.
+var ErrBodyTooLarge = errors.New("request body too large")
+
 func RespondJSON(w http.ResponseWriter, status int, data any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
 }
 
+// RespondError logs err and writes it as a JSON error body. When the response
+// carries an X-Request-Id header, as set by middleware.RequestID, the ID is
+// logged and included in the body as request_id so clients can quote it.
 func RespondError(w http.ResponseWriter, logger *slog.Logger, status int, err error) {
-	logger.Error("handler error", "error", err, "status", status)
+	body := map[string]string{"error": err.Error()}
+	attrs := []any{"error", err, "status", status}
+	if id := w.Header().Get("X-Request-Id"); id != "" {
+		body["request_id"] = id
+		attrs = append(attrs, "request_id", id)
+	}
+
+	logger.Error("handler error", attrs...)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+	json.NewEncoder(w).Encode(body)
+}
+
+// DecodeJSON decodes the request body as JSON into v. Errors are passed
+// through BodyError, so an oversized body is reported as ErrBodyTooLarge.
+func DecodeJSON(r *http.Request, v any) error {
+	return BodyError(json.NewDecoder(r.Body).Decode(v))
+}
+
+// BodyError translates an error from reading a request body, replacing an
+// *http.MaxBytesError with an error wrapping ErrBodyTooLarge. Other errors,
+// including nil, are returned unchanged.
+func BodyError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return fmt.Errorf("%w (limit %d bytes)", ErrBodyTooLarge, tooLarge.Limit)
+	}
+	return err
+}
+
+// MatchesIfNoneMatch reports whether an If-None-Match header matches etag,
+// using the weak comparison RFC 9110 prescribes for it.
+func MatchesIfNoneMatch(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsGzip reports whether an Accept-Encoding header permits gzip.
+// Codings are compared case-insensitively, an explicit gzip entry takes
+// precedence over *, and an entry with q=0 refuses the coding.
+func AcceptsGzip(header string) bool {
+	var gzip, wildcard, gzipSeen, wildcardSeen bool
+	for part := range strings.SplitSeq(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		switch strings.ToLower(strings.TrimSpace(coding)) {
+		case "gzip":
+			gzip, gzipSeen = acceptable(params), true
+		case "*":
+			wildcard, wildcardSeen = acceptable(params), true
+		}
+	}
+	if gzipSeen {
+		return gzip
+	}
+	return wildcardSeen && wildcard
+}
+
+// acceptable reports whether the parameters of an Accept-Encoding entry
+// leave it acceptable, which they do unless they set q=0.
+func acceptable(params string) bool {
+	for param := range strings.SplitSeq(params, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if strings.EqualFold(strings.TrimSpace(name), "q") {
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q == 0 {
+				return false
+			}
+		}
+	}
+	return true
 }
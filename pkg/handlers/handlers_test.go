@@ -0,0 +1,59 @@
+package handlers
+
+import "testing"
+
+func TestMatchesIfNoneMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		etag   string
+		want   bool
+	}{
+		{name: "empty header", header: "", etag: `"abc"`, want: false},
+		{name: "exact", header: `"abc"`, etag: `"abc"`, want: true},
+		{name: "different", header: `"abd"`, etag: `"abc"`, want: false},
+		{name: "weak candidate", header: `W/"abc"`, etag: `"abc"`, want: true},
+		{name: "weak etag", header: `"abc"`, etag: `W/"abc"`, want: true},
+		{name: "list", header: `"x", "abc" , "y"`, etag: `"abc"`, want: true},
+		{name: "wildcard", header: "*", etag: `"abc"`, want: true},
+		{name: "unquoted", header: "abc", etag: `"abc"`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesIfNoneMatch(tt.header, tt.etag); got != tt.want {
+				t.Errorf("MatchesIfNoneMatch(%q, %q) = %v, want %v", tt.header, tt.etag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{name: "empty", header: "", want: false},
+		{name: "gzip", header: "gzip", want: true},
+		{name: "list", header: "br, gzip, deflate", want: true},
+		{name: "other codings only", header: "br, deflate", want: false},
+		{name: "uppercase", header: "GZIP", want: true},
+		{name: "padded", header: " gzip ;q=0.5", want: true},
+		{name: "refused", header: "gzip;q=0", want: false},
+		{name: "refused with decimals", header: "gzip; q=0.000", want: false},
+		{name: "wildcard", header: "*", want: true},
+		{name: "wildcard refused", header: "*;q=0", want: false},
+		{name: "explicit refusal beats wildcard", header: "*;q=1, gzip;q=0", want: false},
+		{name: "explicit acceptance beats wildcard refusal", header: "*;q=0, gzip", want: true},
+		{name: "uppercase q", header: "gzip;Q=0", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AcceptsGzip(tt.header); got != tt.want {
+				t.Errorf("AcceptsGzip(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,25 +3,97 @@ package lifecycle
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 )
 
+// ErrShutdownTimeout is reported for shutdown hooks still running when the
+// shutdown timeout expires.
+var ErrShutdownTimeout = errors.New("shutdown timeout")
+
 // ReadinessChecker provides a simple interface for checking if a system is ready.
 type ReadinessChecker interface {
 	Ready() bool
 }
 
+// ReadinessFunc adapts a function to a ReadinessChecker.
+type ReadinessFunc func() bool
+
+// Ready calls f.
+func (f ReadinessFunc) Ready() bool {
+	return f()
+}
+
 // Coordinator manages application lifecycle including startup hooks, shutdown hooks,
 // and readiness state. It provides a shared context that is cancelled during shutdown.
 type Coordinator struct {
 	ctx        context.Context
 	cancel     context.CancelFunc
+	ctxMu      sync.RWMutex
 	startupWg  sync.WaitGroup
-	shutdownWg sync.WaitGroup
+	startup    []*startupHook
+	signals    map[string]chan struct{}
+	startupMu  sync.Mutex
+	hooks      []shutdownHook
+	hooksMu    sync.Mutex
 	ready      bool
 	readyMu    sync.RWMutex
+	checkers   map[string]ReadinessChecker
+	liveness   map[string]livenessCheck
+	livenessMu sync.RWMutex
+}
+
+// shutdownHook is a registered shutdown function. Hooks registered with
+// OnShutdown keep their function in watch, running from registration until
+// it returns, and done is closed when it does.
+type shutdownHook struct {
+	name  string
+	fn    func(ctx context.Context) error
+	watch func()
+	done  chan struct{}
+}
+
+func (h shutdownHook) run(ctx context.Context) error {
+	if h.watch != nil {
+		<-h.done
+		return nil
+	}
+	return h.fn(ctx)
+}
+
+// HookResult is the outcome of a startup or shutdown hook. Err is nil when
+// the hook succeeded; for a shutdown hook it wraps ErrShutdownTimeout when
+// the hook was still running as the shutdown timeout expired.
+type HookResult struct {
+	Name     string
+	Err      error
+	Duration time.Duration
+}
+
+// ShutdownError reports the shutdown hooks that failed or timed out.
+type ShutdownError struct {
+	Failed []HookResult
+}
+
+func (e *ShutdownError) Error() string {
+	parts := make([]string, len(e.Failed))
+	for i, r := range e.Failed {
+		parts[i] = fmt.Sprintf("%s: %v", r.Name, r.Err)
+	}
+	return "shutdown hooks failed: " + strings.Join(parts, "; ")
+}
+
+// Unwrap returns the errors of the failed hooks, so errors.Is can test for
+// ErrShutdownTimeout.
+func (e *ShutdownError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, r := range e.Failed {
+		errs[i] = r.Err
+	}
+	return errs
 }
 
 // New creates a new Coordinator with an active context.
@@ -33,21 +105,46 @@ func New() *Coordinator {
 	}
 }
 
-// Context returns the coordinator's context, which is cancelled during shutdown.
+// Context returns the coordinator's context, which is cancelled during shutdown
+// and replaced by Restart.
 func (c *Coordinator) Context() context.Context {
+	c.ctxMu.RLock()
+	defer c.ctxMu.RUnlock()
 	return c.ctx
 }
 
-// OnStartup registers a function to run concurrently during startup.
-// All registered functions must complete before WaitForStartup returns.
-func (c *Coordinator) OnStartup(fn func()) {
-	c.startupWg.Go(fn)
-}
-
 // OnShutdown registers a function to run concurrently during shutdown.
 // Functions should wait for Context().Done() before performing cleanup.
+// The function starts immediately and is invoked again after each Restart,
+// so it must call Context() after registration rather than capture the
+// context at registration. Such hooks cannot report errors; prefer
+// OnShutdownE.
 func (c *Coordinator) OnShutdown(fn func()) {
-	c.shutdownWg.Go(fn)
+	c.hooksMu.Lock()
+	defer c.hooksMu.Unlock()
+	hook := shutdownHook{name: fmt.Sprintf("hook-%d", len(c.hooks)+1), watch: fn}
+	launchWatch(&hook)
+	c.hooks = append(c.hooks, hook)
+}
+
+// launchWatch starts an OnShutdown function with a fresh done channel.
+func launchWatch(hook *shutdownHook) {
+	done := make(chan struct{})
+	hook.done = done
+	go func() {
+		defer close(done)
+		hook.watch()
+	}()
+}
+
+// OnShutdownE registers a named function to run concurrently once Shutdown
+// is called. The context passed to fn expires with the shutdown timeout, and
+// the error fn returns is reported under name. The hook is kept across
+// Restart and runs again at each shutdown.
+func (c *Coordinator) OnShutdownE(name string, fn func(ctx context.Context) error) {
+	c.hooksMu.Lock()
+	defer c.hooksMu.Unlock()
+	c.hooks = append(c.hooks, shutdownHook{name: name, fn: fn})
 }
 
 // Ready returns true after WaitForStartup has completed.
@@ -57,29 +154,161 @@ func (c *Coordinator) Ready() bool {
 	return c.ready
 }
 
-// WaitForStartup blocks until all startup hooks complete, then marks the coordinator as ready.
-func (c *Coordinator) WaitForStartup() {
-	c.startupWg.Wait()
+// RegisterChecker registers a named readiness checker for a subsystem, such as
+// a connection to an external provider, reported by Readiness.
+// Registering a name again replaces its checker.
+func (c *Coordinator) RegisterChecker(name string, checker ReadinessChecker) {
 	c.readyMu.Lock()
-	c.ready = true
-	c.readyMu.Unlock()
+	defer c.readyMu.Unlock()
+	if c.checkers == nil {
+		c.checkers = make(map[string]ReadinessChecker)
+	}
+	c.checkers[name] = checker
 }
 
-// Shutdown cancels the context and waits for all shutdown hooks to complete.
-// Returns an error if shutdown does not complete within the timeout.
+// Readiness returns the current state of each registered checker by name.
+func (c *Coordinator) Readiness() map[string]bool {
+	c.readyMu.RLock()
+	defer c.readyMu.RUnlock()
+	status := make(map[string]bool, len(c.checkers))
+	for name, checker := range c.checkers {
+		status[name] = checker.Ready()
+	}
+	return status
+}
+
+// Report returns the current state of each registered checker together with
+// the startup state under "startup". Checkers are evaluated on every call, so
+// a subsystem that stops being ready after startup is reported as such.
+func (c *Coordinator) Report() map[string]bool {
+	status := c.Readiness()
+	status["startup"] = c.Ready()
+	return status
+}
+
+// Shutdown cancels the context and runs all shutdown hooks, waiting up to
+// timeout for them to complete. It returns a *ShutdownError naming the hooks
+// that failed or were still running when the timeout expired.
 func (c *Coordinator) Shutdown(timeout time.Duration) error {
+	_, err := c.ShutdownResults(timeout)
+	return err
+}
+
+// ShutdownResults is Shutdown that also returns the outcome of every hook in
+// registration order, so callers can report on each.
+func (c *Coordinator) ShutdownResults(timeout time.Duration) ([]HookResult, error) {
+	c.ctxMu.RLock()
 	c.cancel()
+	c.ctxMu.RUnlock()
 
-	done := make(chan struct{})
-	go func() {
-		c.shutdownWg.Wait()
-		close(done)
-	}()
+	c.hooksMu.Lock()
+	hooks := append([]shutdownHook(nil), c.hooks...)
+	c.hooksMu.Unlock()
 
-	select {
-	case <-done:
-		return nil
-	case <-time.After(timeout):
-		return fmt.Errorf("shutdown timeout after %v", timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	type outcome struct {
+		index    int
+		err      error
+		duration time.Duration
+	}
+	start := time.Now()
+	outcomes := make(chan outcome, len(hooks))
+	for i, hook := range hooks {
+		go func() {
+			err := hook.run(ctx)
+			outcomes <- outcome{index: i, err: err, duration: time.Since(start)}
+		}()
+	}
+
+	timedOut := fmt.Errorf("%w after %v", ErrShutdownTimeout, timeout)
+	results := make([]HookResult, len(hooks))
+	finished := make([]bool, len(hooks))
+collect:
+	for range hooks {
+		select {
+		case o := <-outcomes:
+			if errors.Is(o.err, context.DeadlineExceeded) && ctx.Err() != nil {
+				o.err = timedOut
+			}
+			results[o.index] = HookResult{Name: hooks[o.index].name, Err: o.err, Duration: o.duration}
+			finished[o.index] = true
+		case <-ctx.Done():
+			break collect
+		}
+	}
+
+	var failed []HookResult
+	for i, hook := range hooks {
+		if !finished[i] {
+			results[i] = HookResult{Name: hook.name, Err: timedOut, Duration: time.Since(start)}
+		}
+		if results[i].Err != nil {
+			failed = append(failed, results[i])
+		}
+	}
+
+	if len(failed) > 0 {
+		return results, &ShutdownError{Failed: failed}
+	}
+	return results, nil
+}
+
+// Restart shuts the coordinator down and brings it back up in the same
+// process, for subsystems rebuilt after a configuration reload. It runs the
+// shutdown hooks as Shutdown does and waits for startup hooks still running
+// to return. It then replaces the context, invokes every OnShutdown function
+// again so it waits on the new context, and replays the startup hooks in
+// dependency order. OnShutdownE hooks are kept and run again at the next
+// shutdown.
+//
+// The coordinator is brought back up even if a shutdown hook fails or times
+// out, so its context is never left cancelled; the *ShutdownError is then
+// returned wrapped once the startup hooks have been relaunched.
+//
+// Restart only cycles hooks. A service started outside a startup hook, such
+// as a server that begins listening before WaitForStartup and is stopped by
+// an OnShutdownE hook, stays stopped; register its start with OnStartupAfter
+// for Restart to bring it back.
+//
+// As at boot, the coordinator is not ready until WaitForStartup or
+// WaitForStartupContext observes the replayed hooks complete.
+func (c *Coordinator) Restart(timeout time.Duration) error {
+	_, shutdownErr := c.ShutdownResults(timeout)
+	c.startupWg.Wait()
+
+	c.readyMu.Lock()
+	c.ready = false
+	c.readyMu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.ctxMu.Lock()
+	c.ctx, c.cancel = ctx, cancel
+	c.ctxMu.Unlock()
+
+	c.hooksMu.Lock()
+	for i := range c.hooks {
+		if c.hooks[i].watch != nil {
+			launchWatch(&c.hooks[i])
+		}
+	}
+	c.hooksMu.Unlock()
+
+	c.startupMu.Lock()
+	c.signals = nil
+	hooks := append([]*startupHook(nil), c.startup...)
+	for _, hook := range hooks {
+		hook.finished, hook.err, hook.duration = false, nil, 0
+	}
+	c.startupMu.Unlock()
+
+	for _, hook := range hooks {
+		c.launchStartup(hook)
+	}
+
+	if shutdownErr != nil {
+		return fmt.Errorf("restart: %w", shutdownErr)
 	}
+	return nil
 }
@@ -0,0 +1,76 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRestart(t *testing.T) {
+	tests := []struct {
+		name     string
+		shutdown func(ctx context.Context) error
+		wantErr  bool
+	}{
+		{
+			name:     "hooks succeed",
+			shutdown: func(context.Context) error { return nil },
+		},
+		{
+			name:     "hook fails",
+			shutdown: func(context.Context) error { return errors.New("close failed") },
+			wantErr:  true,
+		},
+		{
+			name: "hook times out",
+			shutdown: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+
+			var starts atomic.Int32
+			watching := make(chan struct{}, 2)
+			c.OnStartupNamed("service", func() { starts.Add(1) })
+			c.OnShutdown(func() {
+				watching <- struct{}{}
+				<-c.Context().Done()
+			})
+			c.OnShutdownE("close", tt.shutdown)
+			c.WaitForStartup()
+
+			err := c.Restart(50 * time.Millisecond)
+			var shutdownErr *ShutdownError
+			if got := errors.As(err, &shutdownErr); got != tt.wantErr {
+				t.Fatalf("Restart() error = %v, want ShutdownError %v", err, tt.wantErr)
+			}
+
+			if err := c.Context().Err(); err != nil {
+				t.Errorf("context after Restart = %v, want active", err)
+			}
+
+			c.WaitForStartup()
+			if !c.Ready() {
+				t.Error("coordinator not ready after replayed startup")
+			}
+			if n := starts.Load(); n != 2 {
+				t.Errorf("startup hook ran %d times, want 2", n)
+			}
+			for i := range 2 {
+				select {
+				case <-watching:
+				case <-time.After(time.Second):
+					t.Fatalf("OnShutdown function invoked %d times, want 2", i)
+				}
+			}
+		})
+	}
+}
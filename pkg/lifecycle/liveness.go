@@ -0,0 +1,101 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultLivenessTimeout bounds a liveness check registered without
+// WithLivenessTimeout.
+const DefaultLivenessTimeout = time.Second
+
+type livenessCheck struct {
+	check   func(ctx context.Context) error
+	timeout time.Duration
+}
+
+// LivenessOption configures a liveness check.
+type LivenessOption func(*livenessCheck)
+
+// WithLivenessTimeout sets how long a liveness check may run before it is
+// considered failed.
+func WithLivenessTimeout(timeout time.Duration) LivenessOption {
+	return func(l *livenessCheck) { l.timeout = timeout }
+}
+
+// RegisterLiveness registers a named liveness check, which reports whether a
+// subsystem is still functioning, such as a worker loop that has not
+// deadlocked. Unlike readiness, a failing liveness check means the process
+// should be restarted. Registering a name again replaces its check.
+func (c *Coordinator) RegisterLiveness(name string, check func(ctx context.Context) error, opts ...LivenessOption) {
+	l := livenessCheck{check: check, timeout: DefaultLivenessTimeout}
+	for _, opt := range opts {
+		opt(&l)
+	}
+
+	c.livenessMu.Lock()
+	defer c.livenessMu.Unlock()
+	if c.liveness == nil {
+		c.liveness = make(map[string]livenessCheck)
+	}
+	c.liveness[name] = l
+}
+
+// Liveness runs every liveness check concurrently and returns the errors of
+// those that failed by name. Each check runs under its own timeout and is
+// abandoned when the timeout expires, so a hung check cannot hang the caller.
+func (c *Coordinator) Liveness(ctx context.Context) map[string]error {
+	c.livenessMu.RLock()
+	checks := make(map[string]livenessCheck, len(c.liveness))
+	for name, l := range c.liveness {
+		checks[name] = l
+	}
+	c.livenessMu.RUnlock()
+
+	type outcome struct {
+		name string
+		err  error
+	}
+	outcomes := make(chan outcome, len(checks))
+	for name, l := range checks {
+		go func() {
+			outcomes <- outcome{name: name, err: l.run(ctx)}
+		}()
+	}
+
+	failed := make(map[string]error)
+	for range checks {
+		if o := <-outcomes; o.err != nil {
+			failed[o.name] = o.err
+		}
+	}
+	return failed
+}
+
+// Healthy reports whether every liveness check passes.
+func (c *Coordinator) Healthy(ctx context.Context) bool {
+	return len(c.Liveness(ctx)) == 0
+}
+
+// run calls the check, returning once it does or its timeout expires.
+func (l livenessCheck) run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- l.check(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("no response within %v", l.timeout)
+		}
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,57 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Service is a process that can be started and gracefully shut down.
+type Service interface {
+	Start() error
+	Shutdown(timeout time.Duration) error
+}
+
+// Run starts s and blocks until ctx is done or the process receives SIGINT
+// or SIGTERM, then shuts s down within shutdownTimeout and returns the
+// shutdown error. A signal received while s is starting begins shutdown
+// without waiting for Start to return, and a second signal during shutdown
+// exits the process immediately with status 1. If Start fails, s is shut
+// down and the start error is returned along with any shutdown error.
+func Run(ctx context.Context, s Service, shutdownTimeout time.Duration) error {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	started := make(chan error, 1)
+	go func() { started <- s.Start() }()
+
+	select {
+	case err := <-started:
+		if err != nil {
+			return errors.Join(fmt.Errorf("start: %w", err), s.Shutdown(shutdownTimeout))
+		}
+		select {
+		case <-ctx.Done():
+		case <-signals:
+		}
+	case <-ctx.Done():
+	case <-signals:
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-signals:
+			os.Exit(1)
+		case <-done:
+		}
+	}()
+
+	return s.Shutdown(shutdownTimeout)
+}
@@ -0,0 +1,277 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type startupHook struct {
+	name     string
+	deps     []string
+	fn       func(ctx context.Context) error
+	finished bool
+	err      error
+	duration time.Duration
+}
+
+// StartupError reports the startup hooks that failed or, when
+// WaitForStartupContext gave up waiting, were still running.
+type StartupError struct {
+	Failed  []HookResult
+	Pending []string
+	Err     error
+}
+
+func (e *StartupError) Error() string {
+	if len(e.Pending) > 0 {
+		return fmt.Sprintf("startup incomplete: %v: waiting on [%s]", e.Err, strings.Join(e.Pending, ", "))
+	}
+	parts := make([]string, len(e.Failed))
+	for i, r := range e.Failed {
+		parts[i] = fmt.Sprintf("%s: %v", r.Name, r.Err)
+	}
+	return "startup hooks failed: " + strings.Join(parts, "; ")
+}
+
+// Unwrap returns the context error that ended the wait, if any.
+func (e *StartupError) Unwrap() error {
+	return e.Err
+}
+
+// OnStartup registers a function to run concurrently during startup.
+// All registered functions must complete before WaitForStartup returns.
+// The hook is named startup-N, skipping names already registered, and the
+// name is chosen and claimed under one lock so concurrent calls never collide.
+func (c *Coordinator) OnStartup(fn func()) {
+	c.startupMu.Lock()
+	var name string
+	for n := len(c.startup) + 1; ; n++ {
+		name = fmt.Sprintf("startup-%d", n)
+		if c.hook(name) == nil {
+			break
+		}
+	}
+	hook := &startupHook{name: name, fn: func(context.Context) error {
+		fn()
+		return nil
+	}}
+	c.startup = append(c.startup, hook)
+	c.startupMu.Unlock()
+
+	c.launchStartup(hook)
+}
+
+// OnStartupNamed registers a function to run concurrently during startup
+// under a name that StartupProgress and StartupError report while it runs
+// and that OnStartupAfter hooks can depend on. It panics if the name is
+// already registered.
+func (c *Coordinator) OnStartupNamed(name string, fn func()) {
+	err := c.OnStartupAfter(name, nil, func(context.Context) error {
+		fn()
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// OnStartupAfter registers a named startup function that runs once every
+// hook named in deps has completed, so independent hooks still run in
+// parallel. Dependencies may be registered later; a hook waits until they
+// are, and is reported pending meanwhile. If a dependency fails, the hook is
+// not run and fails in turn. fn receives the coordinator's context. The hook
+// is kept and replayed by Restart.
+//
+// Registration fails if the name is already registered or if the hook would
+// complete a dependency cycle.
+func (c *Coordinator) OnStartupAfter(name string, deps []string, fn func(ctx context.Context) error) error {
+	c.startupMu.Lock()
+	if slices.ContainsFunc(c.startup, func(h *startupHook) bool { return h.name == name }) {
+		c.startupMu.Unlock()
+		return fmt.Errorf("startup hook %q already registered", name)
+	}
+	if cycle := c.findCycle(name, deps); cycle != nil {
+		c.startupMu.Unlock()
+		return fmt.Errorf("startup hook %q: dependency cycle: %s", name, strings.Join(cycle, " -> "))
+	}
+	hook := &startupHook{name: name, deps: slices.Clone(deps), fn: fn}
+	c.startup = append(c.startup, hook)
+	c.startupMu.Unlock()
+
+	c.launchStartup(hook)
+	return nil
+}
+
+// launchStartup runs hook once its dependencies finish.
+func (c *Coordinator) launchStartup(hook *startupHook) {
+	ctx := c.Context()
+
+	c.startupMu.Lock()
+	done := c.signal(hook.name)
+	waits := make([]chan struct{}, len(hook.deps))
+	for i, dep := range hook.deps {
+		waits[i] = c.signal(dep)
+	}
+	c.startupMu.Unlock()
+
+	c.startupWg.Go(func() {
+		start := time.Now()
+		err := c.awaitDeps(ctx, hook.deps, waits)
+		if err == nil {
+			start = time.Now()
+			err = hook.fn(ctx)
+		}
+
+		c.startupMu.Lock()
+		hook.finished = true
+		hook.err = err
+		hook.duration = time.Since(start)
+		c.startupMu.Unlock()
+		close(done)
+	})
+}
+
+// awaitDeps waits for each dependency to finish, returning an error if one
+// failed or ctx ended first.
+func (c *Coordinator) awaitDeps(ctx context.Context, deps []string, waits []chan struct{}) error {
+	for i, dep := range deps {
+		select {
+		case <-waits[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		c.startupMu.Lock()
+		err := c.hook(dep).err
+		c.startupMu.Unlock()
+		if err != nil {
+			return fmt.Errorf("dependency %q failed", dep)
+		}
+	}
+	return nil
+}
+
+// signal returns the channel closed when the named hook finishes, creating
+// it for hooks not yet registered. The caller must hold startupMu.
+func (c *Coordinator) signal(name string) chan struct{} {
+	if c.signals == nil {
+		c.signals = make(map[string]chan struct{})
+	}
+	ch, ok := c.signals[name]
+	if !ok {
+		ch = make(chan struct{})
+		c.signals[name] = ch
+	}
+	return ch
+}
+
+// hook returns the registered hook with the given name. The caller must hold
+// startupMu.
+func (c *Coordinator) hook(name string) *startupHook {
+	for _, h := range c.startup {
+		if h.name == name {
+			return h
+		}
+	}
+	return nil
+}
+
+// findCycle returns the dependency path from name back to itself that
+// registering name with deps would create, or nil if there is none. The
+// caller must hold startupMu.
+func (c *Coordinator) findCycle(name string, deps []string) []string {
+	visited := make(map[string]bool)
+	var walk func(current string, path []string) []string
+	walk = func(current string, path []string) []string {
+		path = append(path, current)
+		if current == name {
+			return path
+		}
+		if visited[current] {
+			return nil
+		}
+		visited[current] = true
+		if h := c.hook(current); h != nil {
+			for _, dep := range h.deps {
+				if cycle := walk(dep, path); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		return nil
+	}
+
+	for _, dep := range deps {
+		if cycle := walk(dep, []string{name}); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// StartupProgress returns the number of startup hooks registered, and the
+// names of those still running or waiting on dependencies in registration
+// order.
+func (c *Coordinator) StartupProgress() (total int, pending []string) {
+	c.startupMu.Lock()
+	defer c.startupMu.Unlock()
+	for _, hook := range c.startup {
+		if !hook.finished {
+			pending = append(pending, hook.name)
+		}
+	}
+	return len(c.startup), pending
+}
+
+// WaitForStartup blocks until all startup hooks complete, then marks the
+// coordinator as ready unless a hook failed.
+func (c *Coordinator) WaitForStartup() {
+	c.startupWg.Wait()
+	if c.startupFailures() != nil {
+		return
+	}
+	c.readyMu.Lock()
+	c.ready = true
+	c.readyMu.Unlock()
+}
+
+// WaitForStartupContext is WaitForStartup that gives up when ctx is done,
+// returning a *StartupError naming the hooks still running. The hooks are
+// not interrupted, and the coordinator remains not ready until they finish
+// and WaitForStartup is called again. If every hook finishes but some
+// failed, it returns a *StartupError naming them.
+func (c *Coordinator) WaitForStartupContext(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		c.startupWg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		c.WaitForStartup()
+		if failed := c.startupFailures(); failed != nil {
+			return &StartupError{Failed: failed}
+		}
+		return nil
+	case <-ctx.Done():
+		_, pending := c.StartupProgress()
+		return &StartupError{Pending: pending, Err: ctx.Err()}
+	}
+}
+
+// startupFailures returns the results of the finished hooks that failed.
+func (c *Coordinator) startupFailures() []HookResult {
+	c.startupMu.Lock()
+	defer c.startupMu.Unlock()
+	var failed []HookResult
+	for _, hook := range c.startup {
+		if hook.finished && hook.err != nil {
+			failed = append(failed, HookResult{Name: hook.name, Err: hook.err, Duration: hook.duration})
+		}
+	}
+	return failed
+}
@@ -0,0 +1,91 @@
+package lifecycle
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// startupNames returns the names of the registered startup hooks.
+func startupNames(c *Coordinator) []string {
+	c.startupMu.Lock()
+	defer c.startupMu.Unlock()
+	names := make([]string, len(c.startup))
+	for i, hook := range c.startup {
+		names[i] = hook.name
+	}
+	return names
+}
+
+func TestOnStartupNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		named []string
+		count int
+		want  []string
+	}{
+		{
+			name:  "sequential",
+			count: 3,
+			want:  []string{"startup-1", "startup-2", "startup-3"},
+		},
+		{
+			name:  "skips a taken name",
+			named: []string{"startup-2"},
+			count: 2,
+			want:  []string{"startup-2", "startup-3", "startup-4"},
+		},
+		{
+			name:  "skips consecutive taken names",
+			named: []string{"startup-3", "startup-2"},
+			count: 1,
+			want:  []string{"startup-3", "startup-2", "startup-4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			for _, name := range tt.named {
+				c.OnStartupNamed(name, func() {})
+			}
+			for range tt.count {
+				c.OnStartup(func() {})
+			}
+			c.WaitForStartup()
+
+			if got := startupNames(c); !slices.Equal(got, tt.want) {
+				t.Errorf("names = %v, want %v", got, tt.want)
+			}
+			if !c.Ready() {
+				t.Error("coordinator not ready after startup")
+			}
+		})
+	}
+}
+
+func TestOnStartupConcurrent(t *testing.T) {
+	const n = 50
+	c := New()
+	c.OnStartupNamed(fmt.Sprintf("startup-%d", n/2), func() {})
+
+	var wg sync.WaitGroup
+	for range n {
+		wg.Go(func() { c.OnStartup(func() {}) })
+	}
+	wg.Wait()
+	c.WaitForStartup()
+
+	names := startupNames(c)
+	if len(names) != n+1 {
+		t.Fatalf("registered %d hooks, want %d", len(names), n+1)
+	}
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			t.Errorf("name %q registered twice", name)
+		}
+		seen[name] = true
+	}
+}
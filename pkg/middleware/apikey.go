@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIKeyHeader is the request header carrying an API key.
+const APIKeyHeader = "X-API-Key"
+
+// KeyInfo describes the holder of an API key. Tier optionally names the
+// rate limit tier the holder belongs to.
+type KeyInfo struct {
+	Name    string
+	Tier    string
+	Revoked bool
+}
+
+// KeyStore resolves API keys to the metadata of their holders.
+type KeyStore interface {
+	Lookup(key string) (*KeyInfo, bool)
+}
+
+type keyInfoKey struct{}
+
+// APIKey returns middleware that authenticates requests by the X-API-Key
+// header. Requests without a key, or with one the store does not know,
+// receive 401 and requests with a revoked key 403, each with a JSON error.
+// The KeyInfo of an accepted request is available to handlers through
+// KeyInfoFromContext, and its name is added to the Logger entry.
+func APIKey(store KeyStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(APIKeyHeader)
+			if key == "" {
+				respondError(w, http.StatusUnauthorized, "missing API key")
+				return
+			}
+
+			info, ok := store.Lookup(key)
+			if !ok {
+				respondError(w, http.StatusUnauthorized, "invalid API key")
+				return
+			}
+			AddLogAttrs(r.Context(), "api_key", info.Name)
+			if info.Revoked {
+				respondError(w, http.StatusForbidden, "API key revoked")
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), keyInfoKey{}, info)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// KeyInfoFromContext returns the KeyInfo stored by APIKey.
+func KeyInfoFromContext(ctx context.Context) (*KeyInfo, bool) {
+	info, ok := ctx.Value(keyInfoKey{}).(*KeyInfo)
+	return info, ok
+}
+
+// HashKey returns the hex-encoded SHA-256 digest of an API key, the form in
+// which HashedKeys and KeyConfig hold keys.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// HashedKeys is a KeyStore holding keys by SHA-256 digest, so the keys
+// themselves never need to be stored.
+type HashedKeys map[[sha256.Size]byte]*KeyInfo
+
+// NewHashedKeys builds a HashedKeys store from key configurations.
+func NewHashedKeys(keys []KeyConfig) (HashedKeys, error) {
+	store := make(HashedKeys, len(keys))
+	for i, k := range keys {
+		if k.Name == "" {
+			return nil, fmt.Errorf("keys[%d]: name is required", i)
+		}
+		digest, err := k.digest()
+		if err != nil {
+			return nil, fmt.Errorf("keys[%d] (%s): %w", i, k.Name, err)
+		}
+		if _, ok := store[digest]; ok {
+			return nil, fmt.Errorf("keys[%d] (%s): duplicate hash", i, k.Name)
+		}
+		store[digest] = &KeyInfo{Name: k.Name, Tier: k.Tier, Revoked: k.Revoked}
+	}
+	return store, nil
+}
+
+// Lookup returns the holder of key, if the store has it.
+func (s HashedKeys) Lookup(key string) (*KeyInfo, bool) {
+	info, ok := s[sha256.Sum256([]byte(key))]
+	return info, ok
+}
+
+func (k KeyConfig) digest() ([sha256.Size]byte, error) {
+	var digest [sha256.Size]byte
+	b, err := hex.DecodeString(strings.TrimSpace(k.Hash))
+	if err != nil || len(b) != sha256.Size {
+		return digest, fmt.Errorf("hash must be a hex-encoded SHA-256 digest")
+	}
+	copy(digest[:], b)
+	return digest, nil
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompress(t *testing.T) {
+	body := strings.Repeat(`{"message":"hello"}`, 64)
+
+	tests := []struct {
+		name           string
+		method         string
+		acceptEncoding string
+		header         map[string]string
+		status         int
+		wantGzip       bool
+		wantETag       string
+	}{
+		{name: "json", acceptEncoding: "gzip", header: map[string]string{"Content-Type": "application/json"}, wantGzip: true},
+		{name: "not accepted", acceptEncoding: "br", header: map[string]string{"Content-Type": "application/json"}},
+		{name: "refused", acceptEncoding: "*, gzip;q=0", header: map[string]string{"Content-Type": "application/json"}},
+		{name: "head", method: "HEAD", acceptEncoding: "gzip", header: map[string]string{"Content-Type": "application/json"}},
+		{name: "event stream", acceptEncoding: "gzip", header: map[string]string{"Content-Type": "text/event-stream"}},
+		{name: "already encoded", acceptEncoding: "gzip", header: map[string]string{"Content-Type": "application/json", "Content-Encoding": "br"}},
+		{name: "incompressible type", acceptEncoding: "gzip", header: map[string]string{"Content-Type": "image/png"}},
+		{name: "no content", acceptEncoding: "gzip", header: map[string]string{"Content-Type": "application/json"}, status: http.StatusNoContent},
+		{
+			name:           "strong etag weakened",
+			acceptEncoding: "gzip",
+			header:         map[string]string{"Content-Type": "application/json", "ETag": `"v1"`},
+			wantGzip:       true,
+			wantETag:       `W/"v1"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Compress()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for name, value := range tt.header {
+					w.Header().Set(name, value)
+				}
+				status := tt.status
+				if status == 0 {
+					status = http.StatusOK
+				}
+				w.WriteHeader(status)
+				if status != http.StatusNoContent {
+					io.WriteString(w, body)
+				}
+			}))
+
+			method := tt.method
+			if method == "" {
+				method = "GET"
+			}
+			req := httptest.NewRequest(method, "/", nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if got := rec.Header().Values("Vary"); len(got) != 1 || got[0] != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want [Accept-Encoding]", got)
+			}
+
+			gzipped := rec.Header().Get("Content-Encoding") == "gzip"
+			if gzipped != tt.wantGzip {
+				t.Fatalf("gzipped = %v, want %v", gzipped, tt.wantGzip)
+			}
+			if tt.wantETag != "" && rec.Header().Get("ETag") != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", rec.Header().Get("ETag"), tt.wantETag)
+			}
+
+			got := rec.Body.String()
+			if gzipped {
+				zr, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatalf("gzip.NewReader() error = %v", err)
+				}
+				data, err := io.ReadAll(zr)
+				if err != nil {
+					t.Fatalf("read gzip body: %v", err)
+				}
+				got = string(data)
+			}
+			if method == "HEAD" || tt.status == http.StatusNoContent {
+				return
+			}
+			if got != body {
+				t.Errorf("body = %.40q..., want the handler's body", got)
+			}
+		})
+	}
+}
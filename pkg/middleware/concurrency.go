@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ConcurrencyLimit returns middleware that admits at most max requests at
+// once. Further requests wait up to queueTimeout for a slot and are rejected
+// with 503 and a Retry-After header once the wait expires; requests whose
+// client disconnects while waiting are dropped without a response. A
+// queueTimeout of zero rejects requests as soon as every slot is taken, and a
+// max below one disables the limit.
+//
+// A slot is released when the handler returns, which for an event stream is
+// when the stream ends, or as soon as the request context is canceled by a
+// client disconnect or Timeout, whichever comes first, so abandoned streams
+// do not hold capacity while the handler winds down.
+func ConcurrencyLimit(max int, queueTimeout time.Duration) func(http.Handler) http.Handler {
+	if max < 1 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	slots := make(chan struct{}, max)
+	retryAfter := strconv.Itoa(int(math.Max(1, math.Ceil(queueTimeout.Seconds()))))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			if err := acquireSlot(ctx, slots, queueTimeout); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				w.Header().Set("Retry-After", retryAfter)
+				respondError(w, http.StatusServiceUnavailable, err.Error())
+				return
+			}
+
+			release := sync.OnceFunc(func() { <-slots })
+			stop := context.AfterFunc(ctx, release)
+			defer func() {
+				stop()
+				release()
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// acquireSlot claims a slot, waiting up to timeout for one to free up.
+func acquireSlot(ctx context.Context, slots chan struct{}, timeout time.Duration) error {
+	select {
+	case slots <- struct{}{}:
+		return nil
+	default:
+	}
+	if timeout <= 0 {
+		return fmt.Errorf("server at capacity")
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return fmt.Errorf("server at capacity, waited %s", timeout)
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Predicate reports whether a request satisfies a condition.
+type Predicate func(*http.Request) bool
+
+// Only applies mw to requests matching pred; other requests go directly to
+// the next handler. Both paths are composed once when the chain is built,
+// which a module does on its first request, so a request costs only the
+// predicate call.
+func Only(mw func(http.Handler) http.Handler, pred Predicate) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if pred(r) {
+				wrapped.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Unless applies mw to requests not matching pred, such as skipping
+// authentication for the OpenAPI document.
+func Unless(mw func(http.Handler) http.Handler, pred Predicate) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if pred(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			wrapped.ServeHTTP(w, r)
+		})
+	}
+}
+
+// PathPrefix matches requests whose path starts with any of the prefixes.
+// Within a module, paths are relative to the module prefix.
+func PathPrefix(prefixes ...string) Predicate {
+	return func(r *http.Request) bool {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Path matches requests whose path equals any of the paths.
+func Path(paths ...string) Predicate {
+	return func(r *http.Request) bool {
+		return slices.Contains(paths, r.URL.Path)
+	}
+}
+
+// Methods matches requests using any of the HTTP methods.
+func Methods(methods ...string) Predicate {
+	return func(r *http.Request) bool {
+		return slices.Contains(methods, r.Method)
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// marker returns middleware that sets the X-Applied header, recording
+// whether it ran.
+func marker(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Applied", "true")
+		next.ServeHTTP(w, r)
+	})
+}
+
+func TestOnlyUnless(t *testing.T) {
+	tests := []struct {
+		name   string
+		pred   Predicate
+		method string
+		path   string
+		match  bool
+	}{
+		{name: "prefix match", pred: PathPrefix("/dist/", "/assets/"), method: "GET", path: "/assets/app.js", match: true},
+		{name: "prefix miss", pred: PathPrefix("/dist/"), method: "GET", path: "/api/dist", match: false},
+		{name: "path match", pred: Path("/openapi.json"), method: "GET", path: "/openapi.json", match: true},
+		{name: "path requires equality", pred: Path("/openapi.json"), method: "GET", path: "/openapi.json/x", match: false},
+		{name: "method match", pred: Methods("POST", "PUT"), method: "PUT", path: "/", match: true},
+		{name: "method miss", pred: Methods("POST"), method: "GET", path: "/", match: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { reached = true })
+
+			combinators := []struct {
+				name string
+				mw   func(http.Handler) http.Handler
+				want bool
+			}{
+				{name: "Only", mw: Only(marker, tt.pred), want: tt.match},
+				{name: "Unless", mw: Unless(marker, tt.pred), want: !tt.match},
+			}
+			for _, c := range combinators {
+				reached = false
+				rec := httptest.NewRecorder()
+				c.mw(next).ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+				if applied := rec.Header().Get("X-Applied") == "true"; applied != c.want {
+					t.Errorf("%s: middleware applied = %v, want %v", c.name, applied, c.want)
+				}
+				if !reached {
+					t.Errorf("%s: next handler not reached", c.name)
+				}
+			}
+		})
+	}
+}
+
+// TestSkippedRequestAllocations verifies that a request skipped by Only or
+// Unless allocates nothing beyond the handler it passes through to.
+func TestSkippedRequestAllocations(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	allocating := func(http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Applied", "true")
+		})
+	}
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+	}{
+		{name: "Only with PathPrefix", handler: Only(allocating, PathPrefix("/dist/"))(next)},
+		{name: "Only with Methods", handler: Only(allocating, Methods("POST"))(next)},
+		{name: "Unless with Path", handler: Unless(allocating, Path("/api/openapi.json"))(next)},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	base := testing.AllocsPerRun(100, func() { next.ServeHTTP(rec, req) })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := testing.AllocsPerRun(100, func() { tt.handler.ServeHTTP(rec, req) })
+			if got != base {
+				t.Errorf("skipped request allocations = %v, want %v", got, base)
+			}
+			if rec.Header().Get("X-Applied") != "" {
+				t.Error("middleware applied to a skipped request")
+			}
+		})
+	}
+}
@@ -1,9 +1,15 @@
 package middleware
 
 import (
+	"fmt"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/JaimeStill/go-lit/pkg/envvar"
+	"github.com/pelletier/go-toml/v2"
 )
 
 // CORSConfig holds Cross-Origin Resource Sharing policy settings.
@@ -30,16 +36,17 @@ type CORSEnv struct {
 func (c *CORSConfig) Finalize(env *CORSEnv) error {
 	c.loadDefaults()
 	if env != nil {
-		c.loadEnv(env)
+		if err := c.loadEnv(env); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-// Merge applies non-zero values from the overlay configuration.
+// Merge applies non-zero values from the overlay configuration. Enabled and
+// AllowCredentials are left to the caller, since an overlay that omits them
+// cannot be told apart from one that sets them to false.
 func (c *CORSConfig) Merge(overlay *CORSConfig) {
-	c.Enabled = overlay.Enabled
-	c.AllowCredentials = overlay.AllowCredentials
-
 	if overlay.Origins != nil {
 		c.Origins = overlay.Origins
 	}
@@ -56,7 +63,7 @@ func (c *CORSConfig) Merge(overlay *CORSConfig) {
 
 func (c *CORSConfig) loadDefaults() {
 	if len(c.AllowedMethods) == 0 {
-		c.AllowedMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+		c.AllowedMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
 	}
 	if len(c.AllowedHeaders) == 0 {
 		c.AllowedHeaders = []string{"Content-Type", "Authorization"}
@@ -66,9 +73,11 @@ func (c *CORSConfig) loadDefaults() {
 	}
 }
 
-func (c *CORSConfig) loadEnv(env *CORSEnv) {
+func (c *CORSConfig) loadEnv(env *CORSEnv) error {
+	var r envvar.Reader
+
 	if env.Enabled != "" {
-		if v := os.Getenv(env.Enabled); v != "" {
+		if v := r.Get(env.Enabled); v != "" {
 			if enabled, err := strconv.ParseBool(v); err == nil {
 				c.Enabled = enabled
 			}
@@ -76,7 +85,7 @@ func (c *CORSConfig) loadEnv(env *CORSEnv) {
 	}
 
 	if env.Origins != "" {
-		if v := os.Getenv(env.Origins); v != "" {
+		if v := r.Get(env.Origins); v != "" {
 			origins := strings.Split(v, ",")
 			c.Origins = make([]string, 0, len(origins))
 			for _, origin := range origins {
@@ -88,7 +97,7 @@ func (c *CORSConfig) loadEnv(env *CORSEnv) {
 	}
 
 	if env.AllowedMethods != "" {
-		if v := os.Getenv(env.AllowedMethods); v != "" {
+		if v := r.Get(env.AllowedMethods); v != "" {
 			methods := strings.Split(v, ",")
 			c.AllowedMethods = make([]string, 0, len(methods))
 			for _, method := range methods {
@@ -100,7 +109,7 @@ func (c *CORSConfig) loadEnv(env *CORSEnv) {
 	}
 
 	if env.AllowedHeaders != "" {
-		if v := os.Getenv(env.AllowedHeaders); v != "" {
+		if v := r.Get(env.AllowedHeaders); v != "" {
 			headers := strings.Split(v, ",")
 			c.AllowedHeaders = make([]string, 0, len(headers))
 			for _, header := range headers {
@@ -112,7 +121,7 @@ func (c *CORSConfig) loadEnv(env *CORSEnv) {
 	}
 
 	if env.AllowCredentials != "" {
-		if v := os.Getenv(env.AllowCredentials); v != "" {
+		if v := r.Get(env.AllowCredentials); v != "" {
 			if creds, err := strconv.ParseBool(v); err == nil {
 				c.AllowCredentials = creds
 			}
@@ -120,11 +129,415 @@ func (c *CORSConfig) loadEnv(env *CORSEnv) {
 	}
 
 	if env.MaxAge != "" {
-		if v := os.Getenv(env.MaxAge); v != "" {
+		if v := r.Get(env.MaxAge); v != "" {
 			if maxAge, err := strconv.Atoi(v); err == nil {
 				c.MaxAge = maxAge
 			}
 		}
 	}
+
+	return r.Err()
+}
+
+// RateLimitConfig holds token-bucket rate limiting settings. Rate is the
+// sustained number of requests per second each client may make and Burst the
+// number it may make at once. Clients are identified by the principal that
+// authentication middleware running before RateLimit accepted, and by client
+// IP otherwise, never by a request header a client could vary freely.
+// KeyFunc, when set in code, replaces both. At most MaxKeys buckets are kept,
+// evicting the least recently used.
+type RateLimitConfig struct {
+	Enabled bool                       `toml:"enabled"`
+	Rate    float64                    `toml:"rate"`
+	Burst   int                        `toml:"burst"`
+	MaxKeys int                        `toml:"max_keys"`
+	KeyFunc func(*http.Request) string `toml:"-"`
+}
+
+// RateLimitEnv maps environment variable names for rate limit configuration.
+type RateLimitEnv struct {
+	Enabled string
+	Rate    string
+	Burst   string
+	MaxKeys string
+}
+
+// Finalize applies defaults, loads environment variable overrides, and validates the configuration.
+func (c *RateLimitConfig) Finalize(env *RateLimitEnv) error {
+	c.loadDefaults()
+	if env != nil {
+		if err := c.loadEnv(env); err != nil {
+			return err
+		}
+	}
+	return c.validate()
+}
+
+// Merge applies non-zero values from the overlay configuration. Enabled is
+// left to the caller, since an overlay that omits it cannot be told apart
+// from one that sets it to false.
+func (c *RateLimitConfig) Merge(overlay *RateLimitConfig) {
+	if overlay.Rate > 0 {
+		c.Rate = overlay.Rate
+	}
+	if overlay.Burst > 0 {
+		c.Burst = overlay.Burst
+	}
+	if overlay.MaxKeys > 0 {
+		c.MaxKeys = overlay.MaxKeys
+	}
+}
+
+func (c *RateLimitConfig) loadDefaults() {
+	if c.Rate <= 0 {
+		c.Rate = 1
+	}
+	if c.Burst <= 0 {
+		c.Burst = 5
+	}
+	if c.MaxKeys <= 0 {
+		c.MaxKeys = defaultRateLimitMaxKeys
+	}
+}
+
+func (c *RateLimitConfig) loadEnv(env *RateLimitEnv) error {
+	var r envvar.Reader
+
+	if env.Enabled != "" {
+		if v := r.Get(env.Enabled); v != "" {
+			if enabled, err := strconv.ParseBool(v); err == nil {
+				c.Enabled = enabled
+			}
+		}
+	}
+
+	if env.Rate != "" {
+		if v := r.Get(env.Rate); v != "" {
+			if rate, err := strconv.ParseFloat(v, 64); err == nil {
+				c.Rate = rate
+			}
+		}
+	}
+
+	if env.Burst != "" {
+		if v := r.Get(env.Burst); v != "" {
+			if burst, err := strconv.Atoi(v); err == nil {
+				c.Burst = burst
+			}
+		}
+	}
+
+	if env.MaxKeys != "" {
+		if v := r.Get(env.MaxKeys); v != "" {
+			if maxKeys, err := strconv.Atoi(v); err == nil {
+				c.MaxKeys = maxKeys
+			}
+		}
+	}
+
+	return r.Err()
+}
+
+func (c *RateLimitConfig) validate() error {
+	if c.Rate <= 0 {
+		return fmt.Errorf("invalid rate: %v (must be positive)", c.Rate)
+	}
+	if c.Burst < 1 {
+		return fmt.Errorf("invalid burst: %d (must be at least 1)", c.Burst)
+	}
+	if c.MaxKeys < 1 {
+		return fmt.Errorf("invalid max_keys: %d (must be at least 1)", c.MaxKeys)
+	}
+	return nil
+}
+
+// ConcurrencyConfig holds concurrency limit settings. MaxInFlight is the
+// number of requests served at once and QueueTimeout, a duration such as
+// "10s", how long further requests wait for a slot before being rejected;
+// "0" rejects them immediately.
+type ConcurrencyConfig struct {
+	Enabled      bool     `toml:"enabled"`
+	MaxInFlight  int      `toml:"max_in_flight"`
+	QueueTimeout Duration `toml:"queue_timeout"`
+}
+
+// ConcurrencyEnv maps environment variable names for concurrency limit configuration.
+type ConcurrencyEnv struct {
+	Enabled      string
+	MaxInFlight  string
+	QueueTimeout string
+}
+
+// Finalize applies defaults, loads environment variable overrides, and validates the configuration.
+func (c *ConcurrencyConfig) Finalize(env *ConcurrencyEnv) error {
+	c.loadDefaults()
+	if env != nil {
+		if err := c.loadEnv(env); err != nil {
+			return err
+		}
+	}
+	return c.validate()
+}
+
+// Merge applies non-zero values from the overlay configuration. Enabled is
+// left to the caller, since an overlay that omits it cannot be told apart
+// from one that sets it to false.
+func (c *ConcurrencyConfig) Merge(overlay *ConcurrencyConfig) {
+	if overlay.MaxInFlight > 0 {
+		c.MaxInFlight = overlay.MaxInFlight
+	}
+	c.QueueTimeout.Merge(overlay.QueueTimeout)
+}
+
+func (c *ConcurrencyConfig) loadDefaults() {
+	if c.MaxInFlight <= 0 {
+		c.MaxInFlight = 8
+	}
+	c.QueueTimeout.SetDefault(10 * time.Second)
+}
+
+func (c *ConcurrencyConfig) loadEnv(env *ConcurrencyEnv) error {
+	var r envvar.Reader
+
+	if env.Enabled != "" {
+		if v := r.Get(env.Enabled); v != "" {
+			if enabled, err := strconv.ParseBool(v); err == nil {
+				c.Enabled = enabled
+			}
+		}
+	}
+
+	if env.MaxInFlight != "" {
+		if v := r.Get(env.MaxInFlight); v != "" {
+			if maxInFlight, err := strconv.Atoi(v); err == nil {
+				c.MaxInFlight = maxInFlight
+			}
+		}
+	}
+
+	if env.QueueTimeout != "" {
+		if v := r.Get(env.QueueTimeout); v != "" {
+			if err := c.QueueTimeout.UnmarshalText([]byte(v)); err != nil {
+				return fmt.Errorf("invalid queue_timeout: %s: %w", env.QueueTimeout, err)
+			}
+		}
+	}
+
+	return r.Err()
 }
 
+func (c *ConcurrencyConfig) validate() error {
+	if c.MaxInFlight < 1 {
+		return fmt.Errorf("invalid max_in_flight: %d (must be at least 1)", c.MaxInFlight)
+	}
+	if c.QueueTimeout.Duration < 0 {
+		return fmt.Errorf("invalid queue_timeout: %s (must not be negative)", c.QueueTimeout)
+	}
+	return nil
+}
+
+// AuthConfig holds bearer token authentication settings. Tokens lists the
+// static tokens accepted while Enabled, each with the subject it
+// authenticates as.
+type AuthConfig struct {
+	Enabled bool          `toml:"enabled"`
+	Tokens  []TokenConfig `toml:"tokens"`
+}
+
+// TokenConfig is a static bearer token and the subject it authenticates as.
+type TokenConfig struct {
+	Subject string `toml:"subject"`
+	Token   string `toml:"token" sensitive:"true"`
+}
+
+// AuthEnv maps environment variable names for authentication configuration.
+// Tokens is read as a comma-separated list of subject=token pairs.
+type AuthEnv struct {
+	Enabled string
+	Tokens  string
+}
+
+// Finalize loads environment variable overrides and validates the configuration.
+func (c *AuthConfig) Finalize(env *AuthEnv) error {
+	if env != nil {
+		if err := c.loadEnv(env); err != nil {
+			return err
+		}
+	}
+	return c.validate()
+}
+
+// Merge applies non-zero values from the overlay configuration. Enabled is
+// left to the caller, since an overlay that omits it cannot be told apart
+// from one that sets it to false.
+func (c *AuthConfig) Merge(overlay *AuthConfig) {
+	if overlay.Tokens != nil {
+		c.Tokens = overlay.Tokens
+	}
+}
+
+// Validator returns a StaticTokens validator for the configured tokens.
+func (c *AuthConfig) Validator() TokenValidator {
+	tokens := make(map[string]string, len(c.Tokens))
+	for _, t := range c.Tokens {
+		tokens[t.Token] = t.Subject
+	}
+	return StaticTokens(tokens)
+}
+
+func (c *AuthConfig) loadEnv(env *AuthEnv) error {
+	var r envvar.Reader
+
+	if env.Enabled != "" {
+		if v := r.Get(env.Enabled); v != "" {
+			if enabled, err := strconv.ParseBool(v); err == nil {
+				c.Enabled = enabled
+			}
+		}
+	}
+
+	if env.Tokens != "" {
+		if v := r.Get(env.Tokens); v != "" {
+			pairs := strings.Split(v, ",")
+			c.Tokens = make([]TokenConfig, 0, len(pairs))
+			for _, pair := range pairs {
+				subject, token, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if ok {
+					c.Tokens = append(c.Tokens, TokenConfig{Subject: subject, Token: token})
+				}
+			}
+		}
+	}
+
+	return r.Err()
+}
+
+func (c *AuthConfig) validate() error {
+	if c.Enabled && len(c.Tokens) == 0 {
+		return fmt.Errorf("no tokens configured")
+	}
+
+	seen := make(map[string]bool, len(c.Tokens))
+	for i, t := range c.Tokens {
+		if t.Subject == "" {
+			return fmt.Errorf("tokens[%d]: subject is required", i)
+		}
+		if t.Token == "" {
+			return fmt.Errorf("tokens[%d] (%s): token is required", i, t.Subject)
+		}
+		if seen[t.Token] {
+			return fmt.Errorf("tokens[%d] (%s): duplicate token", i, t.Subject)
+		}
+		seen[t.Token] = true
+	}
+	return nil
+}
+
+// APIKeyConfig holds API key authentication settings. Keys are listed by the
+// hex-encoded SHA-256 digest of the key, as produced by HashKey, so the
+// configuration never holds the keys themselves. KeysFile optionally names a
+// TOML file of further [[keys]] entries in the same form, letting keys be
+// managed apart from the main configuration.
+type APIKeyConfig struct {
+	Enabled  bool        `toml:"enabled"`
+	KeysFile string      `toml:"keys_file"`
+	Keys     []KeyConfig `toml:"keys"`
+}
+
+// KeyConfig is an API key entry: the holder's name, the key's digest, an
+// optional rate limit tier, and whether the key has been revoked.
+type KeyConfig struct {
+	Name    string `toml:"name"`
+	Hash    string `toml:"hash" sensitive:"true"`
+	Tier    string `toml:"tier"`
+	Revoked bool   `toml:"revoked"`
+}
+
+// APIKeyEnv maps environment variable names for API key configuration.
+type APIKeyEnv struct {
+	Enabled  string
+	KeysFile string
+}
+
+// Finalize loads environment variable overrides and validates the configuration.
+func (c *APIKeyConfig) Finalize(env *APIKeyEnv) error {
+	if env != nil {
+		if err := c.loadEnv(env); err != nil {
+			return err
+		}
+	}
+	return c.validate()
+}
+
+// Merge applies non-zero values from the overlay configuration. Enabled is
+// left to the caller, since an overlay that omits it cannot be told apart
+// from one that sets it to false.
+func (c *APIKeyConfig) Merge(overlay *APIKeyConfig) {
+	if overlay.KeysFile != "" {
+		c.KeysFile = overlay.KeysFile
+	}
+	if overlay.Keys != nil {
+		c.Keys = overlay.Keys
+	}
+}
+
+// Store returns a HashedKeys store of the configured keys together with
+// those read from KeysFile.
+func (c *APIKeyConfig) Store() (HashedKeys, error) {
+	keys := c.Keys
+	if c.KeysFile != "" {
+		fileKeys, err := readKeysFile(c.KeysFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(append([]KeyConfig{}, keys...), fileKeys...)
+	}
+	return NewHashedKeys(keys)
+}
+
+func readKeysFile(path string) ([]KeyConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read keys file: %w", err)
+	}
+
+	var file struct {
+		Keys []KeyConfig `toml:"keys"`
+	}
+	if err := toml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse keys file %s: %w", path, err)
+	}
+	return file.Keys, nil
+}
+
+func (c *APIKeyConfig) loadEnv(env *APIKeyEnv) error {
+	var r envvar.Reader
+
+	if env.Enabled != "" {
+		if v := r.Get(env.Enabled); v != "" {
+			if enabled, err := strconv.ParseBool(v); err == nil {
+				c.Enabled = enabled
+			}
+		}
+	}
+
+	if env.KeysFile != "" {
+		if v := r.Get(env.KeysFile); v != "" {
+			c.KeysFile = v
+		}
+	}
+
+	return r.Err()
+}
+
+func (c *APIKeyConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if len(c.Keys) == 0 && c.KeysFile == "" {
+		return fmt.Errorf("no keys or keys_file configured")
+	}
+
+	_, err := c.Store()
+	return err
+}
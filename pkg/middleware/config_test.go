@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+func TestConcurrencyConfigQueueTimeout(t *testing.T) {
+	const envName = "TEST_CONCURRENCY_QUEUE_TIMEOUT"
+
+	tests := []struct {
+		name    string
+		file    string
+		env     string
+		overlay string
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			name: "default",
+			want: 10 * time.Second,
+		},
+		{
+			name: "from file",
+			file: `queue_timeout = "250ms"`,
+			want: 250 * time.Millisecond,
+		},
+		{
+			name: "explicit zero is kept",
+			file: `queue_timeout = "0s"`,
+			want: 0,
+		},
+		{
+			name: "environment overrides file",
+			file: `queue_timeout = "250ms"`,
+			env:  "2s",
+			want: 2 * time.Second,
+		},
+		{
+			name:    "overlay zero overrides file",
+			file:    `queue_timeout = "250ms"`,
+			overlay: `queue_timeout = "0s"`,
+			want:    0,
+		},
+		{
+			name:    "invalid file value",
+			file:    `queue_timeout = "soon"`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid environment value",
+			env:     "soon",
+			wantErr: true,
+		},
+		{
+			name:    "negative",
+			file:    `queue_timeout = "-1s"`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envName, tt.env)
+
+			var cfg ConcurrencyConfig
+			err := toml.Unmarshal([]byte(tt.file), &cfg)
+			if err == nil && tt.overlay != "" {
+				var overlay ConcurrencyConfig
+				if err := toml.Unmarshal([]byte(tt.overlay), &overlay); err != nil {
+					t.Fatalf("overlay: %v", err)
+				}
+				cfg.Merge(&overlay)
+			}
+			if err == nil {
+				err = cfg.Finalize(&ConcurrencyEnv{QueueTimeout: envName})
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg.QueueTimeout.Duration != tt.want {
+				t.Errorf("QueueTimeout = %v, want %v", cfg.QueueTimeout, tt.want)
+			}
+		})
+	}
+}
@@ -3,12 +3,50 @@ package middleware
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"slices"
 	"strings"
 )
 
 // CORS returns middleware that handles Cross-Origin Resource Sharing based on configuration.
+//
+// An origin is allowed when it exactly matches a configured origin, or when
+// it matches a configured origin whose host begins with a wildcard label,
+// such as https://*.preview.example.com. A wildcard stands for exactly one
+// label, so it matches https://pr-12.preview.example.com but neither
+// https://preview.example.com nor https://a.b.preview.example.com, and the
+// scheme and port must match exactly. Wildcards never match the "null"
+// origin, and they are ignored when credentials are allowed, so credentialed
+// requests are only ever granted to exact origins.
+//
+// Responses vary by Origin, and preflight responses also by the requested
+// method and headers, so shared caches do not serve one origin's CORS
+// headers to another. Only a preflight from an allowed origin is answered
+// here; other OPTIONS requests, including preflights from disallowed
+// origins, continue down the chain to the route's handler.
 func CORS(cfg *CORSConfig) func(http.Handler) http.Handler {
+	var exact []string
+	var wildcards []originPattern
+	for _, origin := range cfg.Origins {
+		if p, ok := parseOriginPattern(origin); ok {
+			wildcards = append(wildcards, p)
+		} else {
+			exact = append(exact, origin)
+		}
+	}
+
+	allowed := func(origin string) bool {
+		if slices.Contains(exact, origin) {
+			return true
+		}
+		if cfg.AllowCredentials {
+			return false
+		}
+		return slices.ContainsFunc(wildcards, func(p originPattern) bool {
+			return p.matches(origin)
+		})
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if !cfg.Enabled || len(cfg.Origins) == 0 {
@@ -16,10 +54,16 @@ func CORS(cfg *CORSConfig) func(http.Handler) http.Handler {
 				return
 			}
 
-			origin := r.Header.Get("Origin")
-			allowed := slices.Contains(cfg.Origins, origin)
+			w.Header().Add("Vary", "Origin")
+			preflight := r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != ""
+			if preflight {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+			}
 
-			if allowed {
+			origin := r.Header.Get("Origin")
+			granted := origin != "" && allowed(origin)
+			if granted {
 				w.Header().Set("Access-Control-Allow-Origin", origin)
 				w.Header().Set("Access-Control-Allow-Methods", strings.Join(cfg.AllowedMethods, ", "))
 				w.Header().Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
@@ -33,7 +77,7 @@ func CORS(cfg *CORSConfig) func(http.Handler) http.Handler {
 				}
 			}
 
-			if r.Method == "OPTIONS" {
+			if preflight && granted {
 				w.WriteHeader(http.StatusOK)
 				return
 			}
@@ -42,3 +86,33 @@ func CORS(cfg *CORSConfig) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// originPattern is a configured origin whose host begins with "*.".
+type originPattern struct {
+	scheme string
+	suffix string
+	port   string
+}
+
+func parseOriginPattern(origin string) (originPattern, bool) {
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme == "" || u.Path != "" {
+		return originPattern{}, false
+	}
+	suffix, ok := strings.CutPrefix(u.Hostname(), "*.")
+	if !ok || suffix == "" || strings.Contains(suffix, "*") {
+		return originPattern{}, false
+	}
+	return originPattern{scheme: u.Scheme, suffix: "." + strings.ToLower(suffix), port: u.Port()}, true
+}
+
+// matches reports whether origin has the pattern's scheme and port and a
+// host of one non-empty label followed by the pattern's suffix.
+func (p originPattern) matches(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme != p.scheme || u.Port() != p.port || u.Path != "" || u.User != nil {
+		return false
+	}
+	label, ok := strings.CutSuffix(strings.ToLower(u.Hostname()), p.suffix)
+	return ok && label != "" && !strings.Contains(label, ".")
+}
@@ -0,0 +1,227 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestCORSOrigins(t *testing.T) {
+	origins := []string{
+		"https://app.example.com",
+		"http://localhost:5173",
+		"https://*.preview.example.com",
+		"https://*.staging.example.com:8443",
+	}
+
+	tests := []struct {
+		name        string
+		origin      string
+		credentials bool
+		want        bool
+	}{
+		{name: "exact", origin: "https://app.example.com", want: true},
+		{name: "exact with port", origin: "http://localhost:5173", want: true},
+		{name: "port mismatch", origin: "http://localhost:3000", want: false},
+		{name: "default port not implied", origin: "https://app.example.com:443", want: false},
+		{name: "scheme mismatch", origin: "http://app.example.com", want: false},
+		{name: "wildcard label", origin: "https://pr-12.preview.example.com", want: true},
+		{name: "wildcard is case insensitive", origin: "https://PR-12.Preview.Example.com", want: true},
+		{name: "wildcard needs a label", origin: "https://preview.example.com", want: false},
+		{name: "wildcard matches one label", origin: "https://a.b.preview.example.com", want: false},
+		{name: "wildcard suffix boundary", origin: "https://evilpreview.example.com", want: false},
+		{name: "wildcard scheme mismatch", origin: "http://pr-12.preview.example.com", want: false},
+		{name: "wildcard port", origin: "https://pr-12.staging.example.com:8443", want: true},
+		{name: "wildcard port mismatch", origin: "https://pr-12.staging.example.com", want: false},
+		{name: "wildcard with userinfo", origin: "https://user@pr-12.preview.example.com", want: false},
+		{name: "null origin", origin: "null", want: false},
+		{name: "credentialed exact", origin: "https://app.example.com", credentials: true, want: true},
+		{name: "credentialed wildcard rejected", origin: "https://pr-12.preview.example.com", credentials: true, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := CORS(&CORSConfig{
+				Enabled:          true,
+				Origins:          origins,
+				AllowedMethods:   []string{"GET", "POST"},
+				AllowedHeaders:   []string{"Content-Type"},
+				AllowCredentials: tt.credentials,
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Origin", tt.origin)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			got := rec.Header().Get("Access-Control-Allow-Origin")
+			if tt.want && got != tt.origin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.origin)
+			}
+			if !tt.want && got != "" {
+				t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+			}
+
+			creds := rec.Header().Get("Access-Control-Allow-Credentials")
+			if wantCreds := tt.want && tt.credentials; (creds == "true") != wantCreds {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want set %v", creds, wantCreds)
+			}
+		})
+	}
+}
+
+func TestCORSVary(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		origin  string
+		headers map[string]string
+		want    []string
+	}{
+		{
+			name:   "allowed origin",
+			method: http.MethodGet,
+			origin: "https://app.example.com",
+			want:   []string{"Origin"},
+		},
+		{
+			name:   "disallowed origin",
+			method: http.MethodGet,
+			origin: "https://other.example.com",
+			want:   []string{"Origin"},
+		},
+		{
+			name:   "no origin",
+			method: http.MethodGet,
+			want:   []string{"Origin"},
+		},
+		{
+			name:    "preflight",
+			method:  http.MethodOptions,
+			origin:  "https://app.example.com",
+			headers: map[string]string{"Access-Control-Request-Method": "POST"},
+			want:    []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:   "options without request method",
+			method: http.MethodOptions,
+			origin: "https://app.example.com",
+			want:   []string{"Origin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := CORS(&CORSConfig{
+				Enabled: true,
+				Origins: []string{"https://app.example.com"},
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			req := httptest.NewRequest(tt.method, "/", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if got := rec.Header().Values("Vary"); !slices.Equal(got, tt.want) {
+				t.Errorf("Vary = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCORSDisabled(t *testing.T) {
+	handler := CORS(&CORSConfig{
+		Origins: []string{"https://app.example.com"},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+	}
+	if got := rec.Header().Values("Vary"); len(got) != 0 {
+		t.Errorf("Vary = %v, want none", got)
+	}
+}
+
+func TestCORSOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		origin        string
+		requestMethod string
+		wantStatus    int
+		wantNext      bool
+		wantAllow     bool
+	}{
+		{
+			name:          "preflight from an allowed origin",
+			origin:        "https://app.example.com",
+			requestMethod: "POST",
+			wantStatus:    http.StatusOK,
+			wantAllow:     true,
+		},
+		{
+			name:          "preflight from a disallowed origin",
+			origin:        "https://other.example.com",
+			requestMethod: "POST",
+			wantStatus:    http.StatusNoContent,
+			wantNext:      true,
+		},
+		{
+			name:       "non-preflight OPTIONS from an allowed origin",
+			origin:     "https://app.example.com",
+			wantStatus: http.StatusNoContent,
+			wantNext:   true,
+			wantAllow:  true,
+		},
+		{
+			name:       "OPTIONS without an origin",
+			wantStatus: http.StatusNoContent,
+			wantNext:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			handler := CORS(&CORSConfig{
+				Enabled:        true,
+				Origins:        []string{"https://app.example.com"},
+				AllowedMethods: []string{"GET", "POST"},
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+				w.Header().Set("Allow", "GET, HEAD, OPTIONS")
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+			req := httptest.NewRequest(http.MethodOptions, "/", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", tt.requestMethod)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if reached != tt.wantNext {
+				t.Errorf("next handler reached = %v, want %v", reached, tt.wantNext)
+			}
+			if allow := rec.Header().Get("Access-Control-Allow-Origin") != ""; allow != tt.wantAllow {
+				t.Errorf("Access-Control-Allow-Origin set = %v, want %v", allow, tt.wantAllow)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import "time"
+
+// Duration is a time.Duration written in configuration files and environment
+// variables as a string such as "30s" or "5m". It remembers whether it was
+// set, so an explicit "0" is kept rather than replaced by a default or
+// skipped by Merge.
+type Duration struct {
+	time.Duration
+	set bool
+}
+
+// NewDuration returns d as a set Duration.
+func NewDuration(d time.Duration) Duration {
+	return Duration{Duration: d, set: true}
+}
+
+// IsSet reports whether the duration was parsed from a file or environment
+// variable, or assigned by a default.
+func (d Duration) IsSet() bool {
+	return d.set
+}
+
+// SetDefault assigns def when the duration is unset.
+func (d *Duration) SetDefault(def time.Duration) {
+	if !d.set {
+		*d = NewDuration(def)
+	}
+}
+
+// Merge assigns overlay when it is set.
+func (d *Duration) Merge(overlay Duration) {
+	if overlay.set {
+		*d = overlay
+	}
+}
+
+// UnmarshalText parses a duration string such as "1m30s".
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = NewDuration(v)
+	return nil
+}
+
+// MarshalText formats the duration as a string such as "1m30s".
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/JaimeStill/go-lit/pkg/handlers"
+)
+
+// CacheRule sets Cache-Control on responses to requests whose path starts
+// with Prefix, such as "public, max-age=31536000, immutable" for
+// content-hashed bundles or "no-cache" for documents that must be
+// revalidated on every use.
+type CacheRule struct {
+	Prefix       string
+	CacheControl string
+}
+
+// ConditionalGet returns middleware that gives successful GET responses of up
+// to maxSize bytes a strong ETag, computed from a SHA-256 digest of the body,
+// and answers a matching If-None-Match with 304 Not Modified. Responses that
+// carry a precomputed ETag are not buffered; their ETag is compared as is.
+// Larger responses, responses that flush, and text/event-stream pass through
+// untouched once they exceed the limit or start streaming.
+//
+// Cache-Control is set from the rule with the longest matching prefix, on
+// GET and HEAD responses alike, unless the handler sets its own.
+func ConditionalGet(maxSize int, rules ...CacheRule) func(http.Handler) http.Handler {
+	cacheControl := func(path string) string {
+		var match CacheRule
+		for _, rule := range rules {
+			if strings.HasPrefix(path, rule.Prefix) && len(rule.Prefix) >= len(match.Prefix) {
+				match = rule
+			}
+		}
+		return match.CacheControl
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if cc := cacheControl(r.URL.Path); cc != "" {
+				w.Header().Set("Cache-Control", cc)
+			}
+			if r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &conditionalWriter{
+				ResponseWriter: w,
+				ifNoneMatch:    r.Header.Get("If-None-Match"),
+				maxSize:        maxSize,
+			}
+			next.ServeHTTP(cw, r)
+			cw.finish()
+		})
+	}
+}
+
+type conditionalMode int
+
+const (
+	conditionalBuffering conditionalMode = iota
+	conditionalPassthrough
+	conditionalDiscard
+)
+
+// conditionalWriter buffers a response until it completes, exceeds maxSize,
+// or flushes, deciding at each point whether the response can be validated.
+type conditionalWriter struct {
+	http.ResponseWriter
+	ifNoneMatch string
+	maxSize     int
+	buf         bytes.Buffer
+	mode        conditionalMode
+	wroteHeader bool
+}
+
+func (cw *conditionalWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		if cw.mode == conditionalPassthrough {
+			cw.ResponseWriter.WriteHeader(status)
+		}
+		return
+	}
+	cw.wroteHeader = true
+
+	h := cw.Header()
+	switch {
+	case status != http.StatusOK || isEventStream(h):
+		cw.passthrough(status)
+	case h.Get("ETag") != "":
+		if handlers.MatchesIfNoneMatch(cw.ifNoneMatch, h.Get("ETag")) {
+			cw.notModified()
+			cw.mode = conditionalDiscard
+			return
+		}
+		cw.passthrough(status)
+	case exceedsLength(h, cw.maxSize):
+		cw.passthrough(status)
+	}
+}
+
+func (cw *conditionalWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		if cw.Header().Get("Content-Type") == "" {
+			cw.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	switch cw.mode {
+	case conditionalDiscard:
+		return len(b), nil
+	case conditionalPassthrough:
+		return cw.ResponseWriter.Write(b)
+	}
+
+	if cw.buf.Len()+len(b) > cw.maxSize {
+		if err := cw.release(); err != nil {
+			return 0, err
+		}
+		return cw.ResponseWriter.Write(b)
+	}
+	return cw.buf.Write(b)
+}
+
+func (cw *conditionalWriter) Flush() {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.mode == conditionalBuffering {
+		cw.release()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (cw *conditionalWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// passthrough stops buffering and starts the response with status.
+func (cw *conditionalWriter) passthrough(status int) {
+	cw.mode = conditionalPassthrough
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+// release writes the buffered body unvalidated and passes the rest through.
+func (cw *conditionalWriter) release() error {
+	cw.passthrough(http.StatusOK)
+	_, err := cw.ResponseWriter.Write(cw.buf.Bytes())
+	cw.buf.Reset()
+	return err
+}
+
+// finish validates a response that was buffered to completion.
+func (cw *conditionalWriter) finish() {
+	if cw.mode != conditionalBuffering {
+		return
+	}
+
+	h := cw.Header()
+	sum := sha256.Sum256(cw.buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	h.Set("ETag", etag)
+
+	if handlers.MatchesIfNoneMatch(cw.ifNoneMatch, etag) {
+		cw.notModified()
+		return
+	}
+
+	h.Set("Content-Length", strconv.Itoa(cw.buf.Len()))
+	cw.ResponseWriter.WriteHeader(http.StatusOK)
+	cw.ResponseWriter.Write(cw.buf.Bytes())
+}
+
+// notModified writes a 304 response, dropping the headers that describe the
+// omitted body.
+func (cw *conditionalWriter) notModified() {
+	h := cw.Header()
+	h.Del("Content-Type")
+	h.Del("Content-Length")
+	h.Del("Content-Encoding")
+	cw.ResponseWriter.WriteHeader(http.StatusNotModified)
+}
+
+func isEventStream(h http.Header) bool {
+	mediaType, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	return mediaType == "text/event-stream"
+}
+
+func exceedsLength(h http.Header, maxSize int) bool {
+	n, err := strconv.Atoi(h.Get("Content-Length"))
+	return err == nil && n > maxSize
+}
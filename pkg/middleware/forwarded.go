@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ForwardedPrefixHeader is the header a reverse proxy sets to the path prefix
+// it strips before forwarding, such as /service-a.
+const ForwardedPrefixHeader = "X-Forwarded-Prefix"
+
+type forwardedPrefixKey struct{}
+
+// ForwardedPrefix records the X-Forwarded-Prefix header in the request context
+// when its value is one of the allowed prefixes, so handlers can build URLs as
+// clients see them. Other values are removed from the request, preventing
+// clients from spoofing the prefix. It must wrap the module.Router, since
+// modules read the prefix when routing.
+func ForwardedPrefix(allowed []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			prefix := strings.TrimSuffix(r.Header.Get(ForwardedPrefixHeader), "/")
+			if prefix == "" || !slices.Contains(allowed, prefix) {
+				r.Header.Del(ForwardedPrefixHeader)
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx := context.WithValue(r.Context(), forwardedPrefixKey{}, prefix)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ForwardedPrefixFromContext returns the prefix accepted by ForwardedPrefix,
+// or "" if the request carried none.
+func ForwardedPrefixFromContext(ctx context.Context) string {
+	prefix, _ := ctx.Value(forwardedPrefixKey{}).(string)
+	return prefix
+}
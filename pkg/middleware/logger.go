@@ -1,25 +1,114 @@
 package middleware
 
 import (
+	"context"
 	"log/slog"
+	"math/rand/v2"
 	"net/http"
+	"slices"
+	"sync"
 	"time"
 )
 
-// Logger returns middleware that logs HTTP requests with method, URI, remote address, and duration.
-func Logger(logger *slog.Logger) func(http.Handler) http.Handler {
+// LoggerOption configures the Logger middleware.
+type LoggerOption func(*loggerOptions)
+
+type loggerOptions struct {
+	skipPaths []string
+	sampling  float64
+}
+
+// WithSkipPaths disables logging for requests to the exact paths given,
+// such as health probes.
+func WithSkipPaths(paths ...string) LoggerOption {
+	return func(o *loggerOptions) {
+		o.skipPaths = append(o.skipPaths, paths...)
+	}
+}
+
+// WithSampling logs only the given fraction, between 0 and 1, of requests
+// answered with a 2xx status. Other responses are always logged.
+func WithSampling(rate float64) LoggerOption {
+	return func(o *loggerOptions) {
+		o.sampling = min(max(rate, 0), 1)
+	}
+}
+
+// Logger returns middleware that logs HTTP requests with method, URI, status, remote address, and duration.
+// The remote address is the client address resolved by RealIP when present.
+// Requests assigned an ID by RequestID are logged with it, along with any
+// attributes added by inner middleware and handlers through AddLogAttrs.
+func Logger(logger *slog.Logger, opts ...LoggerOption) func(http.Handler) http.Handler {
+	o := loggerOptions{sampling: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(o.skipPaths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			start := time.Now()
-			next.ServeHTTP(w, r)
-			logger.Info(
-				"request",
+			rw := newResponseWriter(w)
+			extra := &logAttrs{}
+			next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), logAttrsKey{}, extra)))
+
+			if rw.status >= 200 && rw.status < 300 && o.sampling < 1 && rand.Float64() >= o.sampling {
+				return
+			}
+
+			attrs := []any{
 				"method", r.Method,
 				"uri", r.URL.RequestURI(),
-				"addr", r.RemoteAddr,
+				"status", rw.status,
+				"addr", remoteAddr(r),
 				"duration", time.Since(start),
-			)
+			}
+			if id := RequestIDFromContext(r.Context()); id != "" {
+				attrs = append(attrs, "request_id", id)
+			}
+			logger.Info("request", append(attrs, extra.list()...)...)
 		})
 	}
 }
 
+// remoteAddr returns the client address resolved by RealIP, falling back to
+// the request's remote address.
+func remoteAddr(r *http.Request) string {
+	if ip, ok := ClientIPFromContext(r.Context()); ok {
+		return ip.String()
+	}
+	return r.RemoteAddr
+}
+
+type logAttrsKey struct{}
+
+// logAttrs collects attributes for a request's log entry. It is locked
+// because handlers may run on another goroutine, as under Timeout.
+type logAttrs struct {
+	mu    sync.Mutex
+	attrs []any
+}
+
+func (a *logAttrs) list() []any {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return slices.Clone(a.attrs)
+}
+
+// AddLogAttrs adds key-value pairs, in slog's alternating form, to the entry
+// Logger writes for the request, so inner middleware can record what it
+// resolved, such as the caller's identity. It has no effect on requests not
+// served through Logger.
+func AddLogAttrs(ctx context.Context, attrs ...any) {
+	a, ok := ctx.Value(logAttrsKey{}).(*logAttrs)
+	if !ok {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.attrs = append(a.attrs, attrs...)
+}
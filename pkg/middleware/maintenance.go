@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// MaintenanceOption configures Maintenance.
+type MaintenanceOption func(*maintenanceOptions)
+
+type maintenanceOptions struct {
+	page []byte
+}
+
+// WithMaintenancePage serves page as HTML to requests that accept text/html
+// in place of the JSON payload, for modules browsers load directly.
+func WithMaintenancePage(page []byte) MaintenanceOption {
+	return func(o *maintenanceOptions) { o.page = page }
+}
+
+// Maintenance returns middleware that answers every request with 503 and a
+// JSON "maintenance" error while on is set, and passes requests through
+// otherwise. The switch is read on each request, so flipping it takes effect
+// immediately. A positive retryAfter is sent as a Retry-After header in
+// whole seconds.
+//
+// Routes outside the modules it wraps, such as /healthz, are unaffected, so
+// orchestrators keep the process alive during maintenance.
+func Maintenance(on *atomic.Bool, retryAfter time.Duration, opts ...MaintenanceOption) func(http.Handler) http.Handler {
+	var o maintenanceOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var retry string
+	if retryAfter > 0 {
+		retry = strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !on.Load() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if retry != "" {
+				w.Header().Set("Retry-After", retry)
+			}
+			if o.page != nil && strings.Contains(r.Header.Get("Accept"), "text/html") {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				w.Header().Set("Cache-Control", "no-store")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write(o.page)
+				return
+			}
+			respondError(w, http.StatusServiceUnavailable, "maintenance")
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/JaimeStill/go-lit/pkg/handlers"
+)
+
+// MaxBytes returns middleware that limits request bodies to limit bytes.
+// A request whose Content-Length already exceeds the limit is rejected with
+// 413 and a JSON error before reaching the handler. Otherwise the body is
+// wrapped with http.MaxBytesReader, so reading past the limit fails with an
+// *http.MaxBytesError that handlers.DecodeJSON and handlers.BodyError report
+// as handlers.ErrBodyTooLarge. A limit of zero or less disables the check.
+func MaxBytes(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limit <= 0 || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.ContentLength > limit {
+				respondError(w, http.StatusRequestEntityTooLarge, handlers.BodyError(&http.MaxBytesError{Limit: limit}).Error())
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -1,34 +1,100 @@
 // Package middleware provides HTTP middleware management and application.
 package middleware
 
-import "net/http"
+import (
+	"log/slog"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+)
 
 // System manages a stack of HTTP middleware functions.
 type System interface {
 	Use(mw func(http.Handler) http.Handler)
+	UseNamed(name string, mw func(http.Handler) http.Handler)
 	Apply(handler http.Handler) http.Handler
+	Len() int
+	Names() []string
+}
+
+// SystemOption configures a System.
+type SystemOption func(*middleware)
+
+// WithChainLogger logs the composed middleware order at debug level the
+// first time the System applies its stack.
+func WithChainLogger(logger *slog.Logger) SystemOption {
+	return func(m *middleware) { m.logger = logger }
 }
 
 type middleware struct {
-	stack []func(http.Handler) http.Handler
+	stack  []func(http.Handler) http.Handler
+	names  []string
+	logger *slog.Logger
+	logged sync.Once
 }
 
 // New creates a middleware system.
-func New() System {
-	return &middleware{
+func New(opts ...SystemOption) System {
+	m := &middleware{
 		stack: []func(http.Handler) http.Handler{},
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
-// Use adds a middleware function to the stack.
+// Use adds a middleware function to the stack, named after the function
+// that created it, such as "middleware.Logger".
 func (m *middleware) Use(mw func(http.Handler) http.Handler) {
+	m.UseNamed(funcName(mw), mw)
+}
+
+// UseNamed adds a middleware function to the stack under the given name.
+func (m *middleware) UseNamed(name string, mw func(http.Handler) http.Handler) {
 	m.stack = append(m.stack, mw)
+	m.names = append(m.names, name)
 }
 
 // Apply wraps the handler with all middleware in the stack, applying them in reverse order.
 func (m *middleware) Apply(handler http.Handler) http.Handler {
+	if m.logger != nil {
+		m.logged.Do(func() {
+			m.logger.Debug("middleware chain", "order", m.Names())
+		})
+	}
 	for i := len(m.stack) - 1; i >= 0; i-- {
 		handler = m.stack[i](handler)
 	}
 	return handler
 }
+
+// Len returns the number of middleware functions in the stack.
+func (m *middleware) Len() int {
+	return len(m.stack)
+}
+
+// Names returns the names of the middleware in the stack, outermost first.
+func (m *middleware) Names() []string {
+	return append([]string{}, m.names...)
+}
+
+var closureSuffix = regexp.MustCompile(`(\.func\d+|-fm|\.\d+)+$`)
+
+// funcName derives a middleware name from the runtime name of fn, trimming
+// the package path and closure suffixes, so the closure returned by
+// middleware.Logger is named "middleware.Logger".
+func funcName(fn any) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "anonymous"
+	}
+	name := f.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return closureSuffix.ReplaceAllString(name, "")
+}
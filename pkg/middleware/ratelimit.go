@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit returns middleware that limits each client to cfg.Rate requests
+// per second with bursts of up to cfg.Burst, using a token bucket per client.
+// Requests over the limit are rejected with 429 and a Retry-After header
+// giving the seconds until a token is available. A disabled configuration
+// passes every request through.
+//
+// Place RateLimit after BearerAuth or APIKey so each authenticated caller has
+// its own bucket; see clientKey.
+func RateLimit(cfg *RateLimitConfig) func(http.Handler) http.Handler {
+	limiter := newRateLimiter(cfg.Rate, cfg.Burst, cfg.MaxKeys)
+	key := cfg.KeyFunc
+	if key == nil {
+		key = clientKey
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !cfg.Enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if wait, ok := limiter.allow(key(r), time.Now()); !ok {
+				seconds := int(math.Ceil(wait.Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+				respondError(w, http.StatusTooManyRequests, fmt.Sprintf("rate limit exceeded, retry in %ds", max(seconds, 1)))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientKey identifies the caller of r by the subject BearerAuth or the key
+// name APIKey accepted, falling back to the client IP for unauthenticated
+// requests.
+func clientKey(r *http.Request) string {
+	if p, ok := PrincipalFromContext(r.Context()); ok {
+		return "subject:" + p.Subject
+	}
+	if info, ok := KeyInfoFromContext(r.Context()); ok {
+		return "key:" + info.Name
+	}
+	return "ip:" + clientIP(r)
+}
+
+// clientIP returns the client address resolved by RealIP, falling back to
+// the host portion of the request's remote address.
+func clientIP(r *http.Request) string {
+	if ip, ok := ClientIPFromContext(r.Context()); ok {
+		return ip.String()
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// defaultRateLimitMaxKeys is the number of client buckets kept when a
+// configuration does not set MaxKeys.
+const defaultRateLimitMaxKeys = 10000
+
+type bucket struct {
+	key    string
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter holds token buckets in least recently used order, evicting the
+// oldest once maxKeys is reached. An evicted client starts again with a full
+// bucket. A maxKeys below 1 keeps defaultRateLimitMaxKeys buckets.
+type rateLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	maxKeys int
+	order   *list.List
+	buckets map[string]*list.Element
+}
+
+func newRateLimiter(rate float64, burst, maxKeys int) *rateLimiter {
+	if maxKeys < 1 {
+		maxKeys = defaultRateLimitMaxKeys
+	}
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		maxKeys: maxKeys,
+		order:   list.New(),
+		buckets: make(map[string]*list.Element),
+	}
+}
+
+// allow takes a token from the key's bucket, returning false and the time
+// until the next token when the bucket is empty.
+func (l *rateLimiter) allow(key string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var b *bucket
+	if e, ok := l.buckets[key]; ok {
+		l.order.MoveToFront(e)
+		b = e.Value.(*bucket)
+		b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+		b.last = now
+	} else {
+		if l.order.Len() >= l.maxKeys {
+			oldest := l.order.Back()
+			l.order.Remove(oldest)
+			delete(l.buckets, oldest.Value.(*bucket).key)
+		}
+		b = &bucket{key: key, tokens: l.burst, last: now}
+		l.buckets[key] = l.order.PushFront(b)
+	}
+
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / l.rate * float64(time.Second)), false
+	}
+	b.tokens--
+	return 0, true
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimitMaxKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxKeys int
+		keys    []string
+		want    []int
+	}{
+		{
+			name:    "unset keeps every client",
+			maxKeys: 0,
+			keys:    []string{"a", "b", "a"},
+			want:    []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:    "negative keeps every client",
+			maxKeys: -1,
+			keys:    []string{"a", "b", "a"},
+			want:    []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:    "evicts the least recently used",
+			maxKeys: 1,
+			keys:    []string{"a", "b", "a"},
+			want:    []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := RateLimit(&RateLimitConfig{
+				Enabled: true,
+				Rate:    0.001,
+				Burst:   1,
+				MaxKeys: tt.maxKeys,
+				KeyFunc: func(r *http.Request) string { return r.Header.Get("X-Client") },
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			for i, key := range tt.keys {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("X-Client", key)
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				if rec.Code != tt.want[i] {
+					t.Errorf("request %d (%s): status = %d, want %d", i, key, rec.Code, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterAllow(t *testing.T) {
+	start := time.Now()
+	tests := []struct {
+		name     string
+		at       []time.Duration
+		want     []bool
+		wantWait time.Duration
+	}{
+		{
+			name:     "burst then reject",
+			at:       []time.Duration{0, 0, 0},
+			want:     []bool{true, true, false},
+			wantWait: time.Second,
+		},
+		{
+			name: "refills at rate",
+			at:   []time.Duration{0, 0, time.Second},
+			want: []bool{true, true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newRateLimiter(1, 2, 0)
+			var wait time.Duration
+			for i, offset := range tt.at {
+				var ok bool
+				wait, ok = l.allow("client", start.Add(offset))
+				if ok != tt.want[i] {
+					t.Errorf("request %d: allowed = %v, want %v", i, ok, tt.want[i])
+				}
+			}
+			if wait != tt.wantWait {
+				t.Errorf("wait = %v, want %v", wait, tt.wantWait)
+			}
+		})
+	}
+}
+
+func TestRateLimitClientKey(t *testing.T) {
+	tests := []struct {
+		name string
+		auth func(http.Handler) http.Handler
+		reqs []map[string]string
+		want []int
+	}{
+		{
+			name: "varying an unauthenticated header shares the IP bucket",
+			reqs: []map[string]string{{"X-API-Key": "a"}, {"X-API-Key": "b"}},
+			want: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name: "bearer subjects get their own buckets",
+			auth: BearerAuth(func(_ context.Context, token string) (Principal, error) {
+				return Principal{Subject: token}, nil
+			}),
+			reqs: []map[string]string{
+				{"Authorization": "Bearer alice"},
+				{"Authorization": "Bearer bob"},
+				{"Authorization": "Bearer alice"},
+			},
+			want: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name: "API keys resolving to one holder share a bucket",
+			auth: APIKey(keyStore{"k1": {Name: "svc"}, "k2": {Name: "svc"}}),
+			reqs: []map[string]string{{"X-API-Key": "k1"}, {"X-API-Key": "k2"}},
+			want: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			handler = RateLimit(&RateLimitConfig{Enabled: true, Rate: 0.001, Burst: 1})(handler)
+			if tt.auth != nil {
+				handler = tt.auth(handler)
+			}
+
+			for i, headers := range tt.reqs {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				for k, v := range headers {
+					req.Header.Set(k, v)
+				}
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				if rec.Code != tt.want[i] {
+					t.Errorf("request %d: status = %d, want %d", i, rec.Code, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// keyStore is a KeyStore backed by a map.
+type keyStore map[string]*KeyInfo
+
+func (s keyStore) Lookup(key string) (*KeyInfo, bool) {
+	info, ok := s[key]
+	return info, ok
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+type clientIPKey struct{}
+
+// RealIP resolves the client address of requests arriving through trusted
+// proxies and records it in the request context. X-Forwarded-For and
+// X-Real-IP are honored only when the immediate peer is within
+// trustedProxies. X-Forwarded-For is walked from the right, skipping trusted
+// proxies, so the client is the rightmost untrusted address and entries a
+// client prepends cannot spoof it; X-Real-IP is used when X-Forwarded-For is
+// absent. Otherwise the client is the peer itself.
+//
+// The resolved address is available through ClientIPFromContext and is what
+// Logger and RateLimit use in place of the request's RemoteAddr. It must wrap
+// the module.Router so that every module sees it.
+func RealIP(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	trusted := func(addr netip.Addr) bool {
+		for _, prefix := range trustedProxies {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			peer, ok := parseAddr(r.RemoteAddr)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ip := peer
+			if trusted(peer) {
+				ip = forwardedClient(r.Header, peer, trusted)
+			}
+			ctx := context.WithValue(r.Context(), clientIPKey{}, ip)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ClientIPFromContext returns the client address resolved by RealIP.
+func ClientIPFromContext(ctx context.Context) (netip.Addr, bool) {
+	ip, ok := ctx.Value(clientIPKey{}).(netip.Addr)
+	return ip, ok
+}
+
+// forwardedClient returns the rightmost X-Forwarded-For address that is not
+// a trusted proxy. If every hop is trusted the leftmost is returned, and a
+// malformed entry ends the walk at the last address known to be valid.
+func forwardedClient(h http.Header, peer netip.Addr, trusted func(netip.Addr) bool) netip.Addr {
+	var hops []string
+	for _, v := range h.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+	if len(hops) == 0 {
+		if ip, ok := parseAddr(h.Get("X-Real-IP")); ok {
+			return ip
+		}
+		return peer
+	}
+
+	client := peer
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip, ok := parseAddr(hops[i])
+		if !ok {
+			break
+		}
+		client = ip
+		if !trusted(ip) {
+			break
+		}
+	}
+	return client
+}
+
+// parseAddr parses an IP address with or without a port, unmapping
+// IPv4-mapped IPv6 addresses and dropping zones so they match prefixes.
+func parseAddr(s string) (netip.Addr, bool) {
+	s = strings.TrimSpace(s)
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	ip, err := netip.ParseAddr(strings.Trim(s, "[]"))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return ip.Unmap().WithZone(""), true
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestRealIP(t *testing.T) {
+	trusted := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("fd00::/8"),
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		realIP     string
+		want       string
+	}{
+		{name: "direct client", remoteAddr: "203.0.113.9:5000", want: "203.0.113.9"},
+		{name: "spoofed forwarded-for from untrusted peer", remoteAddr: "203.0.113.9:5000", forwarded: []string{"1.2.3.4"}, want: "203.0.113.9"},
+		{name: "spoofed real-ip from untrusted peer", remoteAddr: "203.0.113.9:5000", realIP: "1.2.3.4", want: "203.0.113.9"},
+		{name: "trusted proxy", remoteAddr: "10.0.0.1:5000", forwarded: []string{"198.51.100.7"}, want: "198.51.100.7"},
+		{name: "trusted chain walked right to left", remoteAddr: "10.0.0.1:5000", forwarded: []string{"198.51.100.7, 10.0.0.3, 10.0.0.2"}, want: "198.51.100.7"},
+		{name: "client-prepended entry ignored", remoteAddr: "10.0.0.1:5000", forwarded: []string{"1.2.3.4, 198.51.100.7, 10.0.0.2"}, want: "198.51.100.7"},
+		{name: "repeated headers", remoteAddr: "10.0.0.1:5000", forwarded: []string{"1.2.3.4, 198.51.100.7", "10.0.0.2"}, want: "198.51.100.7"},
+		{name: "every hop trusted", remoteAddr: "10.0.0.1:5000", forwarded: []string{"10.0.0.3, 10.0.0.2"}, want: "10.0.0.3"},
+		{name: "malformed entry stops the walk", remoteAddr: "10.0.0.1:5000", forwarded: []string{"198.51.100.7, garbage, 10.0.0.2"}, want: "10.0.0.2"},
+		{name: "malformed last entry", remoteAddr: "10.0.0.1:5000", forwarded: []string{"198.51.100.7, garbage"}, want: "10.0.0.1"},
+		{name: "real-ip from trusted proxy", remoteAddr: "10.0.0.1:5000", realIP: "198.51.100.7", want: "198.51.100.7"},
+		{name: "forwarded-for preferred to real-ip", remoteAddr: "10.0.0.1:5000", forwarded: []string{"198.51.100.7"}, realIP: "1.2.3.4", want: "198.51.100.7"},
+		{name: "ipv6 proxy and client", remoteAddr: "[fd00::1]:5000", forwarded: []string{"2001:db8::5"}, want: "2001:db8::5"},
+		{name: "ipv4-mapped peer", remoteAddr: "[::ffff:10.0.0.1]:5000", forwarded: []string{"198.51.100.7"}, want: "198.51.100.7"},
+		{name: "forwarded entry with port", remoteAddr: "10.0.0.1:5000", forwarded: []string{"198.51.100.7:4711"}, want: "198.51.100.7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got netip.Addr
+			var ok bool
+			h := RealIP(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, ok = ClientIPFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			if !ok {
+				t.Fatal("ClientIPFromContext() ok = false, want the resolved address")
+			}
+			if got.String() != tt.want {
+				t.Errorf("client IP = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recover returns middleware that recovers from handler panics, logging the
+// panic and stack trace at error level. A 500 JSON error is written if the
+// response has not started; otherwise the panic is only logged, since the
+// status has already been sent. The request ID set by an inner RequestID is
+// included in both. http.ErrAbortHandler is re-panicked so the
+// server aborts the response as intended.
+func Recover(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := newResponseWriter(w)
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(rec)
+				}
+
+				logger.Error(
+					"panic recovered",
+					"panic", rec,
+					"method", r.Method,
+					"path", r.URL.Path,
+					"request_id", rw.Header().Get(RequestIDHeader),
+					"response_started", rw.wroteHeader,
+					"stack", string(debug.Stack()),
+				)
+				if !rw.wroteHeader {
+					respondError(rw, http.StatusInternalServerError, "internal server error")
+				}
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
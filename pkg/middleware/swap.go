@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Swappable holds middleware that can be replaced while requests are being
+// served, such as CORS or rate limiting rebuilt from a reloaded
+// configuration. Requests already inside the previous middleware finish
+// there; later requests go through the replacement. Middleware keeping
+// state, such as rate limit buckets, starts afresh when replaced.
+type Swappable struct {
+	current atomic.Pointer[swapEntry]
+}
+
+type swapEntry struct {
+	mw func(http.Handler) http.Handler
+}
+
+// NewSwappable creates a Swappable initially applying mw.
+func NewSwappable(mw func(http.Handler) http.Handler) *Swappable {
+	s := &Swappable{}
+	s.Swap(mw)
+	return s
+}
+
+// Swap replaces the middleware applied to subsequent requests.
+func (s *Swappable) Swap(mw func(http.Handler) http.Handler) {
+	s.current.Store(&swapEntry{mw: mw})
+}
+
+// Middleware returns middleware applying the current middleware of s. Each
+// replacement is composed with the next handler once, on the first request
+// after the swap.
+func (s *Swappable) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		type composed struct {
+			entry   *swapEntry
+			handler http.Handler
+		}
+		var cache atomic.Pointer[composed]
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			entry := s.current.Load()
+			c := cache.Load()
+			if c == nil || c.entry != entry {
+				c = &composed{entry: entry, handler: entry.mw(next)}
+				cache.Store(c)
+			}
+			c.handler.ServeHTTP(w, r)
+		})
+	}
+}
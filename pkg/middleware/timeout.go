@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrTimeout is the cause of a request context canceled by Timeout.
+var ErrTimeout = errors.New("request timed out")
+
+type timeoutKey struct{}
+
+// Timeout returns middleware that cancels the request context with cause
+// ErrTimeout once d has elapsed and answers 504 with a JSON error if the
+// handler has not started its response. Writes made by the handler after the
+// timeout fail with http.ErrHandlerTimeout.
+//
+// Event streams are exempt: requests that accept text/event-stream pass
+// through untouched, and the deadline is lifted once a handler starts a
+// text/event-stream response.
+//
+// A Timeout nested inside another, such as route-level middleware beneath a
+// module-level Timeout, replaces the outer deadline rather than adding its
+// own, so individual routes can extend or shorten it. The replacement is
+// measured from the start of the request, and a d of zero or less removes the
+// deadline entirely.
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if t, ok := r.Context().Value(timeoutKey{}).(*deadline); ok {
+				t.reset(d)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if d <= 0 || acceptsEventStream(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithCancelCause(r.Context())
+			defer cancel(nil)
+
+			// The writer is expired before the context is canceled, so a
+			// handler that observes the cancellation can no longer write.
+			tw := &timeoutWriter{w: w, header: w.Header().Clone()}
+			t := newDeadline(d, func() {
+				tw.expire(true)
+				cancel(ErrTimeout)
+			})
+			tw.deadline = t
+			defer t.stop()
+
+			ctx = context.WithValue(ctx, timeoutKey{}, t)
+
+			done := make(chan struct{})
+			panicked := make(chan any, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- p
+					}
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case p := <-panicked:
+				panic(p)
+			case <-done:
+			case <-ctx.Done():
+				tw.expire(errors.Is(context.Cause(ctx), ErrTimeout))
+			}
+		})
+	}
+}
+
+// acceptsEventStream reports whether the request negotiates an event stream.
+func acceptsEventStream(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		if strings.Contains(accept, "text/event-stream") {
+			return true
+		}
+	}
+	return false
+}
+
+// deadline cancels a request once its duration has elapsed since start.
+// Unlike a context deadline it can be moved or lifted after the request
+// has started.
+type deadline struct {
+	mu    sync.Mutex
+	start time.Time
+	timer *time.Timer
+}
+
+func newDeadline(d time.Duration, expire func()) *deadline {
+	return &deadline{
+		start: time.Now(),
+		timer: time.AfterFunc(d, expire),
+	}
+}
+
+func (t *deadline) reset(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if d <= 0 {
+		t.timer.Stop()
+		return
+	}
+	t.timer.Reset(max(d-time.Since(t.start), 0))
+}
+
+func (t *deadline) stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.timer.Stop()
+}
+
+// timeoutWriter serializes the handler's writes with the timeout response.
+// The handler writes headers to its own map, copied to the underlying
+// writer when the response starts, so the timeout response never races with
+// header changes made by a handler that is still running.
+type timeoutWriter struct {
+	mu          sync.Mutex
+	w           http.ResponseWriter
+	header      http.Header
+	deadline    *deadline
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	tw.writeHeader(status)
+}
+
+func (tw *timeoutWriter) writeHeader(status int) {
+	if tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+
+	if strings.HasPrefix(tw.header.Get("Content-Type"), "text/event-stream") {
+		tw.deadline.stop()
+	}
+
+	dst := tw.w.Header()
+	clear(dst)
+	maps.Copy(dst, tw.header)
+	tw.w.WriteHeader(status)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.writeHeader(http.StatusOK)
+	return tw.w.Write(b)
+}
+
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	tw.writeHeader(http.StatusOK)
+	if f, ok := tw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// expire stops further handler writes. When the deadline caused the
+// expiry and the response has not started, a 504 is written. Only the
+// first call has any effect.
+func (tw *timeoutWriter) expire(timedOut bool) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	tw.timedOut = true
+	if !timedOut || tw.wroteHeader {
+		return
+	}
+	respondError(tw.w, http.StatusGatewayTimeout, ErrTimeout.Error())
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeoutExpiry(t *testing.T) {
+	writeErr := make(chan error, 1)
+	h := Timeout(20 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		if cause := context.Cause(r.Context()); !errors.Is(cause, ErrTimeout) {
+			t.Errorf("context cause = %v, want ErrTimeout", cause)
+		}
+		w.Header().Set("X-Late", "1")
+		_, err := w.Write([]byte("late"))
+		writeErr <- err
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if err := <-writeErr; !errors.Is(err, http.ErrHandlerTimeout) {
+		t.Errorf("write after expiry error = %v, want http.ErrHandlerTimeout", err)
+	}
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusGatewayTimeout)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, ErrTimeout.Error()) || strings.Contains(body, "late") {
+		t.Errorf("body = %q, want only the timeout error", body)
+	}
+	if rec.Header().Get("X-Late") != "" {
+		t.Error("header set after expiry reached the response")
+	}
+}
+
+func TestTimeoutPassesThrough(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeout     time.Duration
+		accept      string
+		contentType string
+		delay       time.Duration
+	}{
+		{name: "finishes in time", timeout: time.Second},
+		{name: "no deadline", timeout: 0, delay: 40 * time.Millisecond},
+		{name: "event stream requested", timeout: 20 * time.Millisecond, accept: "text/event-stream", delay: 60 * time.Millisecond},
+		{name: "event stream started", timeout: 20 * time.Millisecond, contentType: "text/event-stream", delay: 60 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Timeout(tt.timeout)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+					w.WriteHeader(http.StatusOK)
+					w.(http.Flusher).Flush()
+				}
+				time.Sleep(tt.delay)
+				if err := r.Context().Err(); err != nil {
+					t.Errorf("context error = %v, want nil", err)
+				}
+				if _, err := w.Write([]byte("done")); err != nil {
+					t.Errorf("Write() error = %v", err)
+				}
+			}))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK || rec.Body.String() != "done" {
+				t.Errorf("response = %d %q, want 200 \"done\"", rec.Code, rec.Body)
+			}
+		})
+	}
+}
+
+func TestTimeoutNestedOverride(t *testing.T) {
+	inner := Timeout(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(60 * time.Millisecond)
+		w.Write([]byte("done"))
+	}))
+	h := Timeout(20 * time.Millisecond)(inner)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "done" {
+		t.Errorf("response = %d %q, want 200 \"done\"", rec.Code, rec.Body)
+	}
+}
+
+func TestTimeoutRepanics(t *testing.T) {
+	h := Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Errorf("recovered %v, want the handler's panic", p)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	t.Error("ServeHTTP returned, want it to re-panic")
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/JaimeStill/go-lit/pkg/middleware"
+
+// Tracing returns middleware that continues the caller's trace from a W3C
+// traceparent header and wraps each request in a server span from tp. The
+// span is stored in the request context, so handlers can start child spans
+// with trace.SpanFromContext(ctx).TracerProvider(). Responses with a 5xx
+// status mark the span as failed, and panics are recorded before being
+// re-raised.
+//
+// Spans are named after the matched route pattern, such as
+// "GET /prompts/{id}", which is known once http.ServeMux has routed the
+// request. Apply Tracing as route group middleware to get these names;
+// elsewhere spans are named by method alone.
+func Tracing(tp trace.TracerProvider) func(http.Handler) http.Handler {
+	tracer := tp.Tracer(tracerName)
+	propagator := propagation.TraceContext{}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			name := r.Method
+			attrs := []attribute.KeyValue{
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+			}
+			if r.Pattern != "" {
+				route := r.Pattern
+				if _, path, ok := strings.Cut(route, " "); ok {
+					route = path
+				}
+				name = r.Method + " " + route
+				attrs = append(attrs, attribute.String("http.route", route))
+			}
+			if id := RequestIDFromContext(r.Context()); id != "" {
+				attrs = append(attrs, attribute.String("request.id", id))
+			}
+
+			ctx, span := tracer.Start(ctx, name,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(attrs...),
+			)
+			defer span.End()
+
+			rw := newResponseWriter(w)
+			defer func() {
+				if rec := recover(); rec != nil {
+					span.RecordError(fmt.Errorf("panic: %v", rec))
+					span.SetStatus(codes.Error, "panic")
+					panic(rec)
+				}
+			}()
+
+			next.ServeHTTP(rw, r.WithContext(ctx))
+
+			span.SetAttributes(attribute.Int("http.response.status_code", rw.status))
+			if rw.status >= 500 {
+				span.SetStatus(codes.Error, http.StatusText(rw.status))
+			}
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/JaimeStill/go-lit/pkg/handlers"
+	"github.com/JaimeStill/go-lit/pkg/openapi"
+)
+
+// ValidationError is the response body written when a request violates its
+// documented schema.
+type ValidationError struct {
+	Error      string              `json:"error"`
+	RequestID  string              `json:"request_id,omitempty"`
+	Violations []openapi.Violation `json:"violations,omitempty"`
+}
+
+// ValidateRequests returns middleware that checks requests against the
+// operations documented in spec. Path, query, header, and cookie parameters
+// are coerced to their schema types and validated, and application/json
+// bodies are decoded and validated against the request body schema. Requests
+// with violations are rejected with 400 and a ValidationError listing each one,
+// and bodies exceeding a MaxBytes limit with 413.
+//
+// Requests that do not match a documented operation, and bodies of other
+// content types, pass through unchanged. Because modules strip their prefix
+// before applying middleware, a spec path also matches with leading static
+// segments removed, so /api/prompts/{id} matches /prompts/{id}.
+//
+// The spec is compiled on the first request, so the middleware can be
+// placed in a route group's chain before the group's operations are
+// registered, letting authentication and rate limiting run ahead of it.
+func ValidateRequests(spec *openapi.Spec) func(http.Handler) http.Handler {
+	routes := sync.OnceValue(func() routeTable { return compileRoutes(spec) })
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			item, op, pathValues := routes().match(r.Method, r.URL.Path)
+			if op == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			violations := validateParameters(spec, item.EffectiveParameters(op), r, pathValues)
+
+			bodyViolations, err := validateBody(spec, op, r)
+			if err != nil {
+				status := http.StatusBadRequest
+				if errors.Is(err, handlers.ErrBodyTooLarge) {
+					status = http.StatusRequestEntityTooLarge
+				}
+				handlers.RespondJSON(w, status, ValidationError{
+					Error:     err.Error(),
+					RequestID: RequestIDFromContext(r.Context()),
+				})
+				return
+			}
+			violations = append(violations, bodyViolations...)
+
+			if len(violations) > 0 {
+				handlers.RespondJSON(w, http.StatusBadRequest, ValidationError{
+					Error:      "request validation failed",
+					RequestID:  RequestIDFromContext(r.Context()),
+					Violations: violations,
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type routeTable []route
+
+type route struct {
+	segments []string
+	item     *openapi.PathItem
+}
+
+func compileRoutes(spec *openapi.Spec) routeTable {
+	table := make(routeTable, 0, len(spec.Paths))
+	for _, path := range slices.Sorted(maps.Keys(spec.Paths)) {
+		table = append(table, route{segments: splitPath(path), item: spec.Paths[path]})
+	}
+	return table
+}
+
+// match finds the operation for method and path. Exact matches are preferred
+// over matches with leading static segments removed, which account for a module
+// prefix stripped before routing, and fewer removed segments are preferred over
+// more. Among matches at the same offset, the route with the most static
+// segments wins.
+func (t routeTable) match(method, path string) (*openapi.PathItem, *openapi.Operation, map[string]string) {
+	segments := splitPath(path)
+
+	for offset := 0; ; offset++ {
+		var (
+			bestItem   *openapi.PathItem
+			best       *openapi.Operation
+			bestValues map[string]string
+			bestScore  = -1
+			candidates bool
+		)
+		for _, rt := range t {
+			if len(rt.segments) < offset || slices.ContainsFunc(rt.segments[:offset], isParam) {
+				continue
+			}
+			candidates = true
+			op := rt.item.Operation(method)
+			if op == nil {
+				continue
+			}
+			values, score, ok := matchSegments(rt.segments[offset:], segments)
+			if ok && score > bestScore {
+				bestItem, best, bestValues, bestScore = rt.item, op, values, score
+			}
+		}
+		if best != nil {
+			return bestItem, best, bestValues
+		}
+		if !candidates {
+			return nil, nil, nil
+		}
+	}
+}
+
+func matchSegments(template, segments []string) (map[string]string, int, bool) {
+	values := make(map[string]string)
+	score := 0
+
+	for i, seg := range template {
+		if isParam(seg) {
+			name := strings.Trim(seg, "{}")
+			if name == "$" {
+				return values, score, i == len(segments)
+			}
+			if rest, ok := strings.CutSuffix(name, "..."); ok {
+				values[rest] = strings.Join(segments[min(i, len(segments)):], "/")
+				return values, score, true
+			}
+			if i >= len(segments) || segments[i] == "" {
+				return nil, 0, false
+			}
+			values[name] = segments[i]
+			continue
+		}
+		if i >= len(segments) || segments[i] != seg {
+			return nil, 0, false
+		}
+		score++
+	}
+
+	return values, score, len(template) == len(segments)
+}
+
+func splitPath(path string) []string {
+	path = strings.TrimPrefix(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func validateParameters(spec *openapi.Spec, params []*openapi.Parameter, r *http.Request, pathValues map[string]string) []openapi.Violation {
+	var violations []openapi.Violation
+	query := r.URL.Query()
+
+	for _, p := range params {
+		p = spec.ResolveParameter(p)
+		if p == nil {
+			continue
+		}
+
+		location := p.In + "." + p.Name
+		schema := spec.ResolveSchema(p.Schema)
+
+		var raw []string
+		switch p.In {
+		case "path":
+			if v, ok := pathValues[p.Name]; ok {
+				raw = []string{v}
+			}
+		case "query":
+			if schema != nil && schema.Type == "object" {
+				continue
+			}
+			raw = query[p.Name]
+		case "header":
+			raw = r.Header.Values(p.Name)
+		case "cookie":
+			if c, err := r.Cookie(p.Name); err == nil {
+				raw = []string{c.Value}
+			}
+		}
+
+		if len(raw) == 0 {
+			if p.Required {
+				violations = append(violations, openapi.Violation{Location: location, Message: "is required"})
+			}
+			continue
+		}
+
+		value, ok := coerce(schema, raw)
+		if !ok {
+			violations = append(violations, openapi.Violation{Location: location, Message: "must be " + schema.Type})
+			continue
+		}
+		violations = append(violations, spec.ValidateValue(schema, value, location)...)
+	}
+
+	return violations
+}
+
+// coerce converts raw parameter strings to the JSON value described by schema.
+// Arrays accept repeated values or a single comma-separated value.
+func coerce(schema *openapi.Schema, raw []string) (any, bool) {
+	if schema == nil {
+		return raw[0], true
+	}
+
+	if schema.Type == "array" {
+		if len(raw) == 1 {
+			raw = strings.Split(raw[0], ",")
+		}
+		items := make([]any, 0, len(raw))
+		for _, s := range raw {
+			item, ok := coerceScalar(schema.Items, s)
+			if !ok {
+				return nil, false
+			}
+			items = append(items, item)
+		}
+		return items, true
+	}
+
+	return coerceScalar(schema, raw[0])
+}
+
+func coerceScalar(schema *openapi.Schema, s string) (any, bool) {
+	if schema == nil {
+		return s, true
+	}
+	switch schema.Type {
+	case "integer":
+		if _, err := strconv.ParseInt(s, 10, 64); err != nil {
+			return nil, false
+		}
+		return json.Number(s), true
+	case "number":
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return nil, false
+		}
+		return json.Number(s), true
+	case "boolean":
+		b, err := strconv.ParseBool(s)
+		return b, err == nil
+	}
+	return s, true
+}
+
+// validateBody decodes and validates an application/json request body,
+// restoring it so the handler can read it again. A body that is not valid
+// JSON is returned as an error, as is one exceeding a MaxBytes limit.
+func validateBody(spec *openapi.Spec, op *openapi.Operation, r *http.Request) ([]openapi.Violation, error) {
+	body := spec.ResolveRequestBody(op.RequestBody)
+	if body == nil {
+		return nil, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	content := body.Content[mediaType]
+	if mediaType != "application/json" || content == nil {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, handlers.BodyError(err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		if body.Required {
+			return []openapi.Violation{{Location: "body", Message: "is required"}}, nil
+		}
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON body: %w", err)
+	}
+
+	return spec.ValidateValue(content.Schema, value, "body"), nil
+}
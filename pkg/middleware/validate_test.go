@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/go-lit/pkg/openapi"
+)
+
+func validationSpec() *openapi.Spec {
+	spec := openapi.NewSpec("test", "0.1.0")
+	spec.Components.AddSchemas(map[string]*openapi.Schema{
+		"Item": {
+			Type:       "object",
+			Required:   []string{"name"},
+			Properties: map[string]*openapi.Schema{"name": {Type: "string"}},
+		},
+	})
+
+	tags := openapi.QueryParam("tags", "array", "Tag IDs", false)
+	tags.Schema.Items = &openapi.Schema{Type: "integer"}
+
+	spec.Paths["/items"] = &openapi.PathItem{
+		Post: openapi.NewOperation("Create item").
+			Param(openapi.HeaderParam("X-Tenant", "Tenant", true)).
+			RequestJSON("Item", true).
+			Response(200, openapi.ResponseEmpty("OK")).
+			Build(),
+	}
+	spec.Paths["/items/latest"] = &openapi.PathItem{
+		Get: openapi.NewOperation("Latest items").
+			Param(openapi.QueryParam("limit", "integer", "Page size", true)).
+			Response(200, openapi.ResponseEmpty("OK")).
+			Build(),
+	}
+	spec.Paths["/items/{id}"] = &openapi.PathItem{
+		Get: openapi.NewOperation("Find item").
+			Param(
+				&openapi.Parameter{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer"}},
+				openapi.QueryParam("verbose", "boolean", "Include details", false),
+				tags,
+			).
+			Response(200, openapi.ResponseEmpty("OK")).
+			Build(),
+	}
+	return spec
+}
+
+func TestValidateRequests(t *testing.T) {
+	h := ValidateRequests(validationSpec())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		header      map[string]string
+		body        string
+		status      int
+		wantError   string
+		wantLocated string
+	}{
+		{name: "literal segment beats param", method: "GET", target: "/items/latest?limit=10", status: http.StatusOK},
+		{name: "param segment", method: "GET", target: "/items/42", status: http.StatusOK},
+		{name: "module prefix stripped", method: "GET", target: "/42", status: http.StatusOK},
+		{name: "missing required query", method: "GET", target: "/items/latest", status: http.StatusBadRequest, wantLocated: "query.limit"},
+		{name: "bad int path", method: "GET", target: "/items/abc", status: http.StatusBadRequest, wantLocated: "path.id"},
+		{name: "bad bool query", method: "GET", target: "/items/42?verbose=maybe", status: http.StatusBadRequest, wantLocated: "query.verbose"},
+		{name: "bool query", method: "GET", target: "/items/42?verbose=true", status: http.StatusOK},
+		{name: "comma-separated array query", method: "GET", target: "/items/42?tags=1,2,3", status: http.StatusOK},
+		{name: "repeated array query", method: "GET", target: "/items/42?tags=1&tags=2", status: http.StatusOK},
+		{name: "bad array item", method: "GET", target: "/items/42?tags=1&tags=x", status: http.StatusBadRequest, wantLocated: "query.tags"},
+		{
+			name:        "missing required header",
+			method:      "POST",
+			target:      "/items",
+			header:      map[string]string{"Content-Type": "application/json"},
+			body:        `{"name":"a"}`,
+			status:      http.StatusBadRequest,
+			wantLocated: "header.X-Tenant",
+		},
+		{
+			name:        "missing required body",
+			method:      "POST",
+			target:      "/items",
+			header:      map[string]string{"Content-Type": "application/json", "X-Tenant": "t"},
+			status:      http.StatusBadRequest,
+			wantLocated: "body",
+		},
+		{
+			name:        "body violates schema",
+			method:      "POST",
+			target:      "/items",
+			header:      map[string]string{"Content-Type": "application/json", "X-Tenant": "t"},
+			body:        `{"name":5}`,
+			status:      http.StatusBadRequest,
+			wantLocated: "body",
+		},
+		{
+			name:      "invalid JSON body",
+			method:    "POST",
+			target:    "/items",
+			header:    map[string]string{"Content-Type": "application/json", "X-Tenant": "t"},
+			body:      `{"name":`,
+			status:    http.StatusBadRequest,
+			wantError: "invalid JSON body",
+		},
+		{
+			name:   "valid body",
+			method: "POST",
+			target: "/items",
+			header: map[string]string{"Content-Type": "application/json; charset=utf-8", "X-Tenant": "t"},
+			body:   `{"name":"a"}`,
+			status: http.StatusOK,
+		},
+		{
+			name:   "undocumented content type passes through",
+			method: "POST",
+			target: "/items",
+			header: map[string]string{"Content-Type": "text/plain", "X-Tenant": "t"},
+			body:   "not json",
+			status: http.StatusOK,
+		},
+		{name: "unknown path passes through", method: "GET", target: "/other/path", status: http.StatusOK},
+		{name: "undocumented method passes through", method: "DELETE", target: "/items/abc", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status == http.StatusOK {
+				return
+			}
+
+			var got ValidationError
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if tt.wantError != "" && !strings.Contains(got.Error, tt.wantError) {
+				t.Errorf("error = %q, want it to contain %q", got.Error, tt.wantError)
+			}
+			if tt.wantLocated != "" {
+				found := false
+				for _, v := range got.Violations {
+					if strings.HasPrefix(v.Location, tt.wantLocated) {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("violations = %+v, want one at %s", got.Violations, tt.wantLocated)
+				}
+			}
+		})
+	}
+}
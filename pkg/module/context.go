@@ -0,0 +1,33 @@
+package module
+
+import "context"
+
+type contextKey int
+
+const (
+	prefixKey contextKey = iota
+	externalPrefixKey
+	originalPathKey
+)
+
+// PrefixFromContext returns the full mounted prefix of the module serving the
+// request, such as /admin/users for a child module, or "" outside a module.
+func PrefixFromContext(ctx context.Context) string {
+	prefix, _ := ctx.Value(prefixKey).(string)
+	return prefix
+}
+
+// ExternalPrefixFromContext returns the serving module's prefix as clients see
+// it, including any forwarded prefix, such as /service-a/app. Without a
+// forwarded prefix it equals PrefixFromContext.
+func ExternalPrefixFromContext(ctx context.Context) string {
+	prefix, _ := ctx.Value(externalPrefixKey).(string)
+	return prefix
+}
+
+// OriginalPathFromContext returns the request path before any module stripped
+// its prefix, or "" outside a module.
+func OriginalPathFromContext(ctx context.Context) string {
+	path, _ := ctx.Value(originalPathKey).(string)
+	return path
+}
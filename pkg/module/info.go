@@ -0,0 +1,79 @@
+package module
+
+import (
+	"cmp"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/JaimeStill/go-lit/pkg/handlers"
+)
+
+// Info describes a mounted module for diagnostics. Chain lists the names of
+// the module's middleware, outermost first.
+type Info struct {
+	Name        string   `json:"name"`
+	Prefix      string   `json:"prefix"`
+	Description string   `json:"description,omitempty"`
+	Middleware  int      `json:"middleware"`
+	Chain       []string `json:"chain"`
+	Ready       bool     `json:"ready"`
+}
+
+func (m *Module) info() Info {
+	return Info{
+		Name:        m.name,
+		Prefix:      m.Prefix(),
+		Description: m.description,
+		Middleware:  m.middleware.Len(),
+		Chain:       m.middleware.Names(),
+		Ready:       m.Ready(),
+	}
+}
+
+// Modules returns the currently mounted modules, including children mounted
+// on them, ordered by full prefix.
+func (r *Router) Modules() []Info {
+	r.mu.RLock()
+	mounted := slices.Collect(maps.Values(r.modules))
+	if r.root != nil {
+		mounted = append(mounted, r.root)
+	}
+	r.mu.RUnlock()
+
+	var infos []Info
+	var collect func(m *Module)
+	collect = func(m *Module) {
+		infos = append(infos, m.info())
+		for _, child := range m.children {
+			collect(child)
+		}
+	}
+	for _, m := range mounted {
+		collect(m)
+	}
+
+	slices.SortFunc(infos, func(a, b Info) int {
+		return cmp.Compare(a.Prefix, b.Prefix)
+	})
+	return infos
+}
+
+// Readiness returns the readiness of each mounted module, including children,
+// by name.
+func (r *Router) Readiness() map[string]bool {
+	infos := r.Modules()
+	status := make(map[string]bool, len(infos))
+	for _, info := range infos {
+		status[info.Name] = info.Ready
+	}
+	return status
+}
+
+// ModulesHandler returns a handler that responds with Modules as JSON,
+// suitable for HandleNative("GET /debug/modules", ...).
+func (r *Router) ModulesHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		handlers.RespondJSON(w, http.StatusOK, r.Modules())
+	}
+}
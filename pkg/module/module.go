@@ -4,69 +4,261 @@
 package module
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 
+	"github.com/JaimeStill/go-lit/pkg/lifecycle"
 	"github.com/JaimeStill/go-lit/pkg/middleware"
+	"github.com/JaimeStill/go-lit/pkg/routes"
 )
 
 // Module represents an isolated HTTP handler group with a path prefix
-// and middleware chain. Modules can be mounted onto a Router.
+// and middleware chain. Modules can be mounted onto a Router or, as children,
+// onto another Module.
 type Module struct {
-	prefix     string
-	router     http.Handler
-	middleware middleware.System
+	name        string
+	description string
+	readiness   lifecycle.ReadinessChecker
+	forwarded   string
+	logger      *slog.Logger
+	prefix      string
+	router      http.Handler
+	middleware  middleware.System
+	routes      routes.RouteTable
+	parent      *Module
+	children    map[string]*Module
+
+	// mu serializes changes to the chain with building handler, the
+	// composed chain cached until Use, UseNamed, or Mount changes it.
+	mu      sync.Mutex
+	handler atomic.Pointer[http.Handler]
+}
+
+// Option configures a Module.
+type Option func(*Module)
+
+// WithName sets the module name reported by Router.Modules.
+// The default is the prefix without its leading slash, or "root" for "/".
+func WithName(name string) Option {
+	return func(m *Module) { m.name = name }
+}
+
+// WithDescription sets the module description reported by Router.Modules.
+func WithDescription(description string) Option {
+	return func(m *Module) { m.description = description }
+}
+
+// WithReadiness sets the checker that reports whether the module is ready.
+// Modules without one are always ready.
+func WithReadiness(checker lifecycle.ReadinessChecker) Option {
+	return func(m *Module) { m.readiness = checker }
+}
+
+// WithForwardedPrefix sets the prefix a reverse proxy strips before forwarding
+// to the module, such as /service-a, so ExternalPrefixFromContext reports the
+// module's path as clients see it. It takes precedence over a prefix accepted
+// by middleware.ForwardedPrefix.
+func WithForwardedPrefix(prefix string) Option {
+	return func(m *Module) { m.forwarded = strings.TrimSuffix(prefix, "/") }
+}
+
+// WithLogger sets the logger the module logs its composed middleware order
+// to, at debug level, the first time it serves a request.
+func WithLogger(logger *slog.Logger) Option {
+	return func(m *Module) { m.logger = logger }
 }
 
 // New creates a Module with the given path prefix and HTTP handler.
-// Panics if the prefix is invalid (must start with "/" and be single-level).
-func New(prefix string, router http.Handler) *Module {
+// The prefix may span several segments, such as /internal/agents, or be "/"
+// for a root module that receives paths unchanged.
+// Panics if the prefix is invalid (must start with "/" and contain no empty
+// segments or trailing slash).
+func New(prefix string, router http.Handler, opts ...Option) *Module {
 	if err := validatePrefix(prefix); err != nil {
 		panic(err)
 	}
-	return &Module{
-		prefix:     prefix,
-		router:     router,
-		middleware: middleware.New(),
+	m := &Module{
+		name:   defaultName(prefix),
+		prefix: prefix,
+		router: router,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	var mwOpts []middleware.SystemOption
+	if m.logger != nil {
+		mwOpts = append(mwOpts, middleware.WithChainLogger(m.logger.With("module", m.name)))
 	}
+	m.middleware = middleware.New(mwOpts...)
+	return m
+}
+
+// Name returns the module name.
+func (m *Module) Name() string {
+	return m.name
+}
+
+// Ready reports whether the module's readiness checker, if any, is ready.
+func (m *Module) Ready() bool {
+	return m.readiness == nil || m.readiness.Ready()
 }
 
 // Handler returns the module's handler with all middleware applied.
+// Requests for a child module pass through this middleware before the child's.
+// The chain is composed on first use and reused by every request until Use,
+// UseNamed, or Mount changes the module, so middleware is built once rather
+// than per request.
 func (m *Module) Handler() http.Handler {
-	return m.middleware.Apply(m.router)
+	if h := m.handler.Load(); h != nil {
+		return *h
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if h := m.handler.Load(); h != nil {
+		return *h
+	}
+	h := m.middleware.Apply(http.HandlerFunc(m.dispatch))
+	m.handler.Store(&h)
+	return h
 }
 
-// Prefix returns the module's path prefix.
+// Prefix returns the module's full mounted path, including the prefixes of
+// any parent modules.
 func (m *Module) Prefix() string {
+	if m.parent == nil {
+		return m.prefix
+	}
+	if parent := m.parent.Prefix(); parent != "/" {
+		return parent + m.prefix
+	}
 	return m.prefix
 }
 
+// externalPrefix returns the module's full prefix behind the forwarded prefix
+// of its outermost module or, failing that, of the request.
+func (m *Module) externalPrefix(ctx context.Context) string {
+	top := m
+	for top.parent != nil {
+		top = top.parent
+	}
+	forwarded := top.forwarded
+	if forwarded == "" {
+		forwarded = middleware.ForwardedPrefixFromContext(ctx)
+	}
+	if prefix := m.Prefix(); prefix != "/" {
+		return forwarded + prefix
+	}
+	if forwarded == "" {
+		return "/"
+	}
+	return forwarded
+}
+
+// Mount registers a child module at its prefix relative to this module, so a
+// child created with New("/users", h) mounted on /admin serves /admin/users.
+// Requests matching the child's prefix are routed through the child's
+// middleware and handler; all others reach this module's handler.
+// Children must be mounted before the module serves requests; mount top-level
+// modules on the Router to add them at runtime.
+func (m *Module) Mount(child *Module) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.children == nil {
+		m.children = make(map[string]*Module)
+	}
+	child.parent = m
+	m.children[child.prefix] = child
+	m.handler.Store(nil)
+}
+
+func (m *Module) dispatch(w http.ResponseWriter, req *http.Request) {
+	if child, ok := matchModule(m.children, req.URL.Path); ok {
+		child.Serve(w, req)
+		return
+	}
+	m.router.ServeHTTP(w, req)
+}
+
 // Serve handles HTTP requests by stripping the module prefix from the path
-// before routing to the module's handler chain.
+// before routing to the module's handler chain. The module's full prefix and
+// the path as received by the outermost module are recorded in the request
+// context; see PrefixFromContext, ExternalPrefixFromContext, and
+// OriginalPathFromContext.
 func (m *Module) Serve(w http.ResponseWriter, req *http.Request) {
-	path := extractPath(req.URL.Path, m.prefix)
-	request := cloneRequest(req, path)
+	ctx := context.WithValue(req.Context(), prefixKey, m.Prefix())
+	ctx = context.WithValue(ctx, externalPrefixKey, m.externalPrefix(ctx))
+	if _, ok := ctx.Value(originalPathKey).(string); !ok {
+		ctx = context.WithValue(ctx, originalPathKey, req.URL.Path)
+	}
+	request := cloneRequest(req.WithContext(ctx), m.prefix)
 	m.Handler().ServeHTTP(w, request)
 }
 
-// Use adds middleware to the module's chain.
+// Use adds middleware to the module's chain, named after the function that
+// created it.
 func (m *Module) Use(mw func(http.Handler) http.Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.middleware.Use(mw)
+	m.handler.Store(nil)
+}
+
+// UseNamed adds middleware to the module's chain under the given name.
+func (m *Module) UseNamed(name string, mw func(http.Handler) http.Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.middleware.UseNamed(name, mw)
+	m.handler.Store(nil)
+}
+
+// Middleware returns the names of the middleware in the module's chain,
+// outermost first.
+func (m *Module) Middleware() []string {
+	return m.middleware.Names()
+}
+
+// SetRoutes records the routes the module's handler serves, with paths
+// relative to the module prefix, for diagnostics.
+func (m *Module) SetRoutes(table routes.RouteTable) {
+	m.routes = table
 }
 
-func cloneRequest(req *http.Request, path string) *http.Request {
+// Routes returns the routes recorded with SetRoutes, or nil if none were.
+func (m *Module) Routes() routes.RouteTable {
+	return m.routes
+}
+
+// cloneRequest returns a copy of req with the prefix stripped from the path.
+// An escaped RawPath is stripped the same way, so escapes such as %2F in the
+// remainder survive, and is dropped only when it no longer encodes the path.
+func cloneRequest(req *http.Request, prefix string) *http.Request {
 	request := new(http.Request)
 	*request = *req
 	request.URL = new(url.URL)
 	*request.URL = *req.URL
-	request.URL.Path = path
+	request.URL.Path = extractPath(req.URL.Path, prefix)
 	request.URL.RawPath = ""
+
+	if req.URL.RawPath != "" && strings.HasPrefix(req.URL.RawPath, prefix) {
+		raw := extractPath(req.URL.RawPath, prefix)
+		if unescaped, err := url.PathUnescape(raw); err == nil && unescaped == request.URL.Path {
+			request.URL.RawPath = raw
+		}
+	}
 	return request
 }
 
 func extractPath(fullPath, prefix string) string {
+	if prefix == "/" {
+		return fullPath
+	}
 	path := fullPath[len(prefix):]
 	if path == "" {
 		return "/"
@@ -74,6 +266,13 @@ func extractPath(fullPath, prefix string) string {
 	return path
 }
 
+func defaultName(prefix string) string {
+	if prefix == "/" {
+		return "root"
+	}
+	return strings.TrimPrefix(prefix, "/")
+}
+
 func validatePrefix(prefix string) error {
 	if prefix == "" {
 		return fmt.Errorf("module prefix cannot be empty")
@@ -81,9 +280,11 @@ func validatePrefix(prefix string) error {
 	if !strings.HasPrefix(prefix, "/") {
 		return fmt.Errorf("module prefix must start with /: %s", prefix)
 	}
-	if strings.Count(prefix, "/") != 1 {
-		return fmt.Errorf("module prefix must be single-level sub-path: %s", prefix)
+	if prefix == "/" {
+		return nil
+	}
+	if strings.HasSuffix(prefix, "/") || strings.Contains(prefix, "//") {
+		return fmt.Errorf("module prefix must not contain empty segments: %s", prefix)
 	}
 	return nil
 }
-
@@ -0,0 +1,222 @@
+package module
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/JaimeStill/go-lit/pkg/middleware"
+)
+
+// counting returns middleware that counts how many times it is composed.
+func counting(composed *int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		*composed++
+		return next
+	}
+}
+
+func noContent(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestModuleComposesOnce(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests int
+		change   func(m *Module)
+		want     int
+	}{
+		{name: "one request", requests: 1, want: 1},
+		{name: "many requests", requests: 5, want: 1},
+		{
+			name:     "Use rebuilds",
+			requests: 2,
+			change:   func(m *Module) { m.Use(func(next http.Handler) http.Handler { return next }) },
+			want:     2,
+		},
+		{
+			name:     "Mount rebuilds",
+			requests: 2,
+			change:   func(m *Module) { m.Mount(New("/child", http.HandlerFunc(noContent))) },
+			want:     2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var composed int
+			m := New("/api", http.HandlerFunc(noContent))
+			m.Use(counting(&composed))
+
+			serve := func() {
+				rec := httptest.NewRecorder()
+				m.Serve(rec, httptest.NewRequest(http.MethodGet, "/api/items", nil))
+				if rec.Code != http.StatusNoContent {
+					t.Fatalf("status = %d, want %d", rec.Code, http.StatusNoContent)
+				}
+			}
+
+			for range tt.requests {
+				serve()
+			}
+			if tt.change != nil {
+				tt.change(m)
+				serve()
+			}
+
+			if composed != tt.want {
+				t.Errorf("composed %d times, want %d", composed, tt.want)
+			}
+		})
+	}
+}
+
+func TestModuleChildDispatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantBy     string
+		wantPath   string
+		wantPrefix string
+	}{
+		{name: "parent", path: "/admin/settings", wantBy: "admin", wantPath: "/settings", wantPrefix: "/admin"},
+		{name: "child", path: "/admin/users/42", wantBy: "users", wantPath: "/42", wantPrefix: "/admin/users"},
+		{name: "nested child", path: "/admin/users/roles/7", wantBy: "roles", wantPath: "/7", wantPrefix: "/admin/users/roles"},
+		{name: "child prefix alone", path: "/admin/users", wantBy: "users", wantPath: "/", wantPrefix: "/admin/users"},
+		{name: "whole segments only", path: "/admin/usersx", wantBy: "admin", wantPath: "/usersx", wantPrefix: "/admin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := New("/users", echo("users"))
+			users.Mount(New("/roles", echo("roles")))
+			admin := New("/admin", echo("admin"))
+			admin.Mount(users)
+
+			r := NewRouter()
+			r.Mount(admin)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if got := rec.Header().Get("X-Served-By"); got != tt.wantBy {
+				t.Errorf("served by %q, want %q", got, tt.wantBy)
+			}
+			if got := rec.Header().Get("X-Path"); got != tt.wantPath {
+				t.Errorf("path = %q, want %q", got, tt.wantPath)
+			}
+			if got := rec.Header().Get("X-Prefix"); got != tt.wantPrefix {
+				t.Errorf("prefix = %q, want %q", got, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestModuleMiddlewareOrder(t *testing.T) {
+	var order []string
+	record := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	child := New("/child", http.HandlerFunc(noContent))
+	child.UseNamed("child-first", record("child-first"))
+	child.UseNamed("child-second", record("child-second"))
+
+	parent := New("/parent", http.HandlerFunc(noContent))
+	parent.UseNamed("parent-first", record("parent-first"))
+	parent.UseNamed("parent-second", record("parent-second"))
+	parent.Mount(child)
+
+	tests := []struct {
+		name string
+		path string
+		want []string
+	}{
+		{name: "parent", path: "/parent/items", want: []string{"parent-first", "parent-second"}},
+		{name: "child", path: "/parent/child/items", want: []string{"parent-first", "parent-second", "child-first", "child-second"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order = nil
+			parent.Serve(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if !slices.Equal(order, tt.want) {
+				t.Errorf("middleware ran in order %v, want %v", order, tt.want)
+			}
+		})
+	}
+
+	if got, want := parent.Middleware(), []string{"parent-first", "parent-second"}; !slices.Equal(got, want) {
+		t.Errorf("Middleware() = %v, want %v", got, want)
+	}
+}
+
+// TestModuleSkippedMiddlewareAllocations verifies that middleware skipped by
+// middleware.Only or middleware.Unless adds no allocations to a request
+// served through a module.
+func TestModuleSkippedMiddlewareAllocations(t *testing.T) {
+	allocating := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Applied", "true")
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	tests := []struct {
+		name string
+		mw   func(http.Handler) http.Handler
+	}{
+		{name: "Only", mw: middleware.Only(allocating, middleware.PathPrefix("/dist/"))},
+		{name: "Unless", mw: middleware.Unless(allocating, middleware.Path("/openapi.json"))},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+	rec := httptest.NewRecorder()
+
+	bare := New("/api", http.HandlerFunc(noContent))
+	base := testing.AllocsPerRun(100, func() { bare.Serve(rec, req) })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New("/api", http.HandlerFunc(noContent))
+			m.Use(tt.mw)
+
+			got := testing.AllocsPerRun(100, func() { m.Serve(rec, req) })
+			if got != base {
+				t.Errorf("allocations per request = %v, want %v as without the middleware", got, base)
+			}
+		})
+	}
+}
+
+func TestModuleSwappableComposesOncePerSwap(t *testing.T) {
+	var first, second int
+	swap := middleware.NewSwappable(counting(&first))
+
+	m := New("/api", http.HandlerFunc(noContent))
+	m.Use(swap.Middleware())
+
+	serve := func() {
+		m.Serve(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/items", nil))
+	}
+
+	serve()
+	serve()
+	if first != 1 {
+		t.Errorf("before swap: composed %d times over two requests, want 1", first)
+	}
+
+	swap.Swap(counting(&second))
+	serve()
+	serve()
+	if first != 1 || second != 1 {
+		t.Errorf("after swap: composed previous %d and replacement %d times, want 1 and 1", first, second)
+	}
+}
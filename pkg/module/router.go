@@ -1,14 +1,45 @@
 package module
 
 import (
+	"maps"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
+	"sync"
+)
+
+// TrailingSlash is the policy a Router applies to paths ending in a slash.
+type TrailingSlash int
+
+const (
+	// TrailingSlashStrip routes the request as if the trailing slash were absent,
+	// leaving the original request unmodified. This is the default.
+	TrailingSlashStrip TrailingSlash = iota
+
+	// TrailingSlashRedirect redirects to the path without the trailing slash,
+	// preserving the query string. GET and HEAD requests receive 301; other
+	// methods receive 308 so clients resend the body.
+	TrailingSlashRedirect
+
+	// TrailingSlashNone routes the path as received.
+	TrailingSlashNone
 )
 
 // Router routes requests to mounted modules or native handlers.
+// A module mounted at "/" is the root module: it receives requests that match
+// neither another module's prefix nor a native handler.
+//
+// Modules can be mounted and unmounted while the router serves requests.
+// A request is routed using the modules mounted when it arrives, so requests
+// already in flight to an unmounted module finish normally.
 type Router struct {
-	modules map[string]*Module
-	native  *http.ServeMux
+	mu       sync.RWMutex
+	modules  map[string]*Module
+	root     *Module
+	native   *http.ServeMux
+	notFound http.Handler
+	slash    TrailingSlash
 }
 
 // NewRouter creates a Router for mounting modules and native handlers.
@@ -25,37 +56,139 @@ func (r *Router) HandleNative(pattern string, handler http.HandlerFunc) {
 	r.native.HandleFunc(pattern, handler)
 }
 
-// Mount registers a module at its configured prefix.
+// SetTrailingSlash configures how paths ending in a slash are routed.
+// It must be called before the router serves requests.
+func (r *Router) SetTrailingSlash(policy TrailingSlash) {
+	r.slash = policy
+}
+
+// NotFound configures the handler for requests that match no module and no
+// native handler. A mounted root module takes precedence. If not set, the
+// native ServeMux 404 behavior applies.
+func (r *Router) NotFound(handler http.Handler) {
+	r.notFound = handler
+}
+
+// Mount registers a module at its configured prefix, replacing any module
+// already mounted there.
 func (r *Router) Mount(m *Module) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if m.prefix == "/" {
+		r.root = m
+		return
+	}
 	r.modules[m.prefix] = m
 }
 
-// ServeHTTP routes requests to the matching module or falls back to native handlers.
+// Unmount removes the module mounted at prefix, reporting whether one was.
+func (r *Router) Unmount(prefix string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if prefix == "/" {
+		mounted := r.root != nil
+		r.root = nil
+		return mounted
+	}
+	if _, ok := r.modules[prefix]; !ok {
+		return false
+	}
+	delete(r.modules, prefix)
+	return true
+}
+
+// Mounted returns the prefixes of the mounted modules in sorted order.
+func (r *Router) Mounted() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	prefixes := slices.Sorted(maps.Keys(r.modules))
+	if r.root != nil {
+		prefixes = append([]string{"/"}, prefixes...)
+	}
+	return prefixes
+}
+
+// ServeHTTP routes requests to the module with the longest prefix matching
+// whole path segments, then to a matching native handler, and finally to the
+// root module if one is mounted. Without a root module, unmatched requests
+// reach the NotFound handler or, if none is set, the native mux.
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	path := normalizePath(req)
-	prefix := extractPrefix(path)
+	req, ok := r.normalize(w, req)
+	if !ok {
+		return
+	}
+	path := req.URL.Path
+
+	r.mu.RLock()
+	m, ok := matchModule(r.modules, path)
+	root := r.root
+	r.mu.RUnlock()
 
-	if m, ok := r.modules[prefix]; ok {
+	if ok {
 		m.Serve(w, req)
 		return
 	}
 
+	if root != nil || r.notFound != nil {
+		if _, pattern := r.native.Handler(req); pattern == "" {
+			if root != nil {
+				root.Serve(w, req)
+			} else {
+				r.notFound.ServeHTTP(w, req)
+			}
+			return
+		}
+	}
+
 	r.native.ServeHTTP(w, req)
 }
 
-func extractPrefix(path string) string {
-	parts := strings.SplitN(path, "/", 3)
-	if len(parts) >= 2 {
-		return "/" + parts[1]
+// matchModule finds the module for path by trying the path itself and then
+// each shorter segment boundary, so /internal/agents/run prefers a module at
+// /internal/agents over one at /internal.
+func matchModule(modules map[string]*Module, path string) (*Module, bool) {
+	for prefix := path; prefix != ""; {
+		if m, ok := modules[prefix]; ok {
+			return m, true
+		}
+		i := strings.LastIndex(prefix, "/")
+		if i < 0 {
+			break
+		}
+		prefix = prefix[:i]
 	}
-	return path
+	return nil, false
 }
 
-func normalizePath(req *http.Request) string {
+// normalize applies the trailing slash policy, returning the request to route
+// or false if a redirect was written.
+func (r *Router) normalize(w http.ResponseWriter, req *http.Request) (*http.Request, bool) {
 	path := req.URL.Path
-	if len(path) > 1 && strings.HasSuffix(path, "/") {
-		path = strings.TrimSuffix(path, "/")
-		req.URL.Path = path
+	if r.slash == TrailingSlashNone || len(path) <= 1 || !strings.HasSuffix(path, "/") {
+		return req, true
 	}
-	return path
+
+	if r.slash == TrailingSlashRedirect {
+		target := strings.TrimSuffix(req.URL.EscapedPath(), "/")
+		if req.URL.RawQuery != "" {
+			target += "?" + req.URL.RawQuery
+		}
+		status := http.StatusPermanentRedirect
+		if req.Method == http.MethodGet || req.Method == http.MethodHead {
+			status = http.StatusMovedPermanently
+		}
+		http.Redirect(w, req, target, status)
+		return nil, false
+	}
+
+	request := new(http.Request)
+	*request = *req
+	request.URL = new(url.URL)
+	*request.URL = *req.URL
+	request.URL.Path = strings.TrimSuffix(path, "/")
+	request.URL.RawPath = strings.TrimSuffix(req.URL.RawPath, "/")
+	return request, true
 }
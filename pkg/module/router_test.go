@@ -0,0 +1,148 @@
+package module
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// echo returns a handler that reports name and the path it received.
+func echo(name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Served-By", name)
+		w.Header().Set("X-Path", r.URL.Path)
+		w.Header().Set("X-Prefix", PrefixFromContext(r.Context()))
+	})
+}
+
+func TestRouterDispatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		root       bool
+		notFound   bool
+		path       string
+		wantBy     string
+		wantPath   string
+		wantPrefix string
+		wantStatus int
+	}{
+		{name: "module prefix", path: "/api/items", wantBy: "api", wantPath: "/items", wantPrefix: "/api"},
+		{name: "module prefix alone", path: "/api", wantBy: "api", wantPath: "/", wantPrefix: "/api"},
+		{name: "longest prefix", path: "/internal/agents/run", wantBy: "agents", wantPath: "/run", wantPrefix: "/internal/agents"},
+		{name: "shorter prefix", path: "/internal/other", wantBy: "internal", wantPath: "/other", wantPrefix: "/internal"},
+		{name: "whole segments only", path: "/apix", wantStatus: http.StatusNotFound},
+		{name: "native handler", path: "/healthz", wantBy: "native", wantPath: "/healthz"},
+		{name: "native before root", root: true, path: "/healthz", wantBy: "native", wantPath: "/healthz"},
+		{name: "root module", root: true, path: "/app/page", wantBy: "root", wantPath: "/app/page", wantPrefix: "/"},
+		{name: "module before root", root: true, path: "/api/items", wantBy: "api", wantPath: "/items", wantPrefix: "/api"},
+		{name: "not found handler", notFound: true, path: "/missing", wantBy: "notfound", wantPath: "/missing"},
+		{name: "root before not found", root: true, notFound: true, path: "/missing", wantBy: "root", wantPath: "/missing", wantPrefix: "/"},
+		{name: "native 404", path: "/missing", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter()
+			r.Mount(New("/api", echo("api")))
+			r.Mount(New("/internal", echo("internal")))
+			r.Mount(New("/internal/agents", echo("agents")))
+			r.HandleNative("GET /healthz", echo("native").ServeHTTP)
+			if tt.root {
+				r.Mount(New("/", echo("root")))
+			}
+			if tt.notFound {
+				r.NotFound(echo("notfound"))
+			}
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if tt.wantStatus != 0 {
+				if rec.Code != tt.wantStatus {
+					t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+				}
+				return
+			}
+			if got := rec.Header().Get("X-Served-By"); got != tt.wantBy {
+				t.Errorf("served by %q, want %q", got, tt.wantBy)
+			}
+			if got := rec.Header().Get("X-Path"); got != tt.wantPath {
+				t.Errorf("path = %q, want %q", got, tt.wantPath)
+			}
+			if got := rec.Header().Get("X-Prefix"); got != tt.wantPrefix {
+				t.Errorf("prefix = %q, want %q", got, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestRouterTrailingSlash(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       TrailingSlash
+		method       string
+		path         string
+		wantStatus   int
+		wantLocation string
+		wantPath     string
+	}{
+		{name: "strip", policy: TrailingSlashStrip, method: http.MethodGet, path: "/api/items/", wantStatus: http.StatusOK, wantPath: "/items"},
+		{name: "redirect GET", policy: TrailingSlashRedirect, method: http.MethodGet, path: "/api/items/?page=2", wantStatus: http.StatusMovedPermanently, wantLocation: "/api/items?page=2"},
+		{name: "redirect POST", policy: TrailingSlashRedirect, method: http.MethodPost, path: "/api/items/", wantStatus: http.StatusPermanentRedirect, wantLocation: "/api/items"},
+		{name: "none", policy: TrailingSlashNone, method: http.MethodGet, path: "/api/items/", wantStatus: http.StatusOK, wantPath: "/items/"},
+		{name: "root path kept", policy: TrailingSlashRedirect, method: http.MethodGet, path: "/", wantStatus: http.StatusOK, wantPath: "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter()
+			r.SetTrailingSlash(tt.policy)
+			r.Mount(New("/api", echo("api")))
+			r.Mount(New("/", echo("root")))
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("Location = %q, want %q", got, tt.wantLocation)
+			}
+			if got := rec.Header().Get("X-Path"); got != tt.wantPath {
+				t.Errorf("path = %q, want %q", got, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestRouterMountAndUnmount(t *testing.T) {
+	r := NewRouter()
+	r.Mount(New("/api", echo("first")))
+	r.Mount(New("/api", echo("second")))
+	r.Mount(New("/", echo("root")))
+
+	if got, want := r.Mounted(), []string{"/", "/api"}; !slices.Equal(got, want) {
+		t.Errorf("Mounted() = %v, want %v", got, want)
+	}
+
+	serve := func() string {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/items", nil))
+		return rec.Header().Get("X-Served-By")
+	}
+	if got := serve(); got != "second" {
+		t.Errorf("served by %q, want the replacing module", got)
+	}
+
+	if !r.Unmount("/api") {
+		t.Errorf("Unmount(/api) = false, want true")
+	}
+	if r.Unmount("/api") {
+		t.Errorf("second Unmount(/api) = true, want false")
+	}
+	if got := serve(); got != "root" {
+		t.Errorf("served by %q after unmount, want root", got)
+	}
+}
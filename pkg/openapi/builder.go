@@ -0,0 +1,123 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// OperationBuilder constructs an Operation through chained calls.
+// The result is an ordinary *Operation, so built and literal operations
+// can be mixed freely.
+type OperationBuilder struct {
+	op   *Operation
+	errs []error
+}
+
+// NewOperation starts building an operation with the given summary.
+func NewOperation(summary string) *OperationBuilder {
+	return &OperationBuilder{
+		op: &Operation{
+			Summary:   summary,
+			Responses: make(Responses),
+		},
+	}
+}
+
+// ID sets the operation ID. When omitted, routes derive one from the route.
+func (b *OperationBuilder) ID(operationID string) *OperationBuilder {
+	b.op.OperationID = operationID
+	return b
+}
+
+// Description sets the operation description.
+func (b *OperationBuilder) Description(description string) *OperationBuilder {
+	b.op.Description = description
+	return b
+}
+
+// Tag appends tags to the operation.
+func (b *OperationBuilder) Tag(tags ...string) *OperationBuilder {
+	b.op.Tags = append(b.op.Tags, tags...)
+	return b
+}
+
+// Param appends parameters to the operation.
+func (b *OperationBuilder) Param(params ...*Parameter) *OperationBuilder {
+	b.op.Parameters = append(b.op.Parameters, params...)
+	return b
+}
+
+// RequestJSON sets an application/json request body referencing a schema in
+// components/schemas.
+func (b *OperationBuilder) RequestJSON(schemaName string, required bool) *OperationBuilder {
+	return b.RequestBody(RequestBodyJSON(schemaName, required))
+}
+
+// RequestBody sets the request body.
+func (b *OperationBuilder) RequestBody(body *RequestBody) *OperationBuilder {
+	b.op.RequestBody = body
+	return b
+}
+
+// Response declares the response for a status code, StatusDefault, or a
+// status range such as Status5XX.
+func (b *OperationBuilder) Response(status StatusCode, response *Response) *OperationBuilder {
+	if _, ok := b.op.Responses[status]; ok {
+		b.errs = append(b.errs, fmt.Errorf("duplicate response for status %s", status))
+	}
+	b.op.Responses[status] = response
+	return b
+}
+
+// ResponseJSON declares an application/json response referencing a schema in
+// components/schemas.
+func (b *OperationBuilder) ResponseJSON(status StatusCode, description, schemaName string) *OperationBuilder {
+	return b.Response(status, ResponseJSON(description, schemaName))
+}
+
+// ErrorResponses declares the standard error response for each status code:
+// 400 BadRequest, 404 NotFound, 409 Conflict, and 500 InternalError.
+func (b *OperationBuilder) ErrorResponses(statuses ...StatusCode) *OperationBuilder {
+	for _, status := range statuses {
+		switch status {
+		case 400:
+			b.Response(status, ErrBadRequest())
+		case 404:
+			b.Response(status, ErrNotFound())
+		case 409:
+			b.Response(status, ErrConflict())
+		case 500:
+			b.Response(status, ErrInternal())
+		default:
+			b.errs = append(b.errs, fmt.Errorf("no standard error response for status %s", status))
+		}
+	}
+	return b
+}
+
+// Deprecated marks the operation as deprecated.
+func (b *OperationBuilder) Deprecated() *OperationBuilder {
+	b.op.Deprecated = true
+	return b
+}
+
+// Security sets the operation's security requirements, overriding the
+// document-level requirements.
+func (b *OperationBuilder) Security(reqs ...SecurityRequirement) *OperationBuilder {
+	b.op.Security = append([]SecurityRequirement{}, reqs...)
+	return b
+}
+
+// Build returns the constructed operation.
+// Panics if the operation declares no responses or a builder call was invalid,
+// since operations are defined at startup and such errors are programming mistakes.
+func (b *OperationBuilder) Build() *Operation {
+	errs := b.errs
+	if len(b.op.Responses) == 0 {
+		errs = append(errs, errors.New("no responses declared"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		panic(fmt.Errorf("operation %q: %w", b.op.Summary, err))
+	}
+	return b.op
+}
@@ -2,10 +2,17 @@ package openapi
 
 import "maps"
 
-// NewComponents creates a Components instance with common shared schemas and responses.
-// Includes PageRequest schema and standard error responses (BadRequest, NotFound, Conflict).
+// NewComponents creates a Components instance with common shared schemas and parameters.
+// Includes PageRequest schema and pagination query parameters (Page, PageSize, Search, Sort).
+// Standard error responses are installed by RegisterStandardResponses.
 func NewComponents() *Components {
 	return &Components{
+		Parameters: map[string]*Parameter{
+			"Page":     QueryParam("page", "integer", "Page number (1-indexed)", false),
+			"PageSize": QueryParam("page_size", "integer", "Results per page", false),
+			"Search":   QueryParam("search", "string", "Search query", false),
+			"Sort":     QueryParam("sort", "string", "Comma-separated sort fields. Prefix with - for descending", false),
+		},
 		Schemas: map[string]*Schema{
 			"PageRequest": {
 				Type: "object",
@@ -17,43 +24,60 @@ func NewComponents() *Components {
 				},
 			},
 		},
-		Responses: map[string]*Response{
-			"BadRequest": {
-				Description: "Invalid request",
-				Content: map[string]*MediaType{
-					"application/json": {
-						Schema: &Schema{
-							Type: "object",
-							Properties: map[string]*Schema{
-								"error": {Type: "string", Description: "Error message"},
-							},
-						},
-					},
-				},
-			},
-			"NotFound": {
-				Description: "Resource not found",
-				Content: map[string]*MediaType{
-					"application/json": {
-						Schema: &Schema{
-							Type: "object",
-							Properties: map[string]*Schema{
-								"error": {Type: "string", Description: "Error message"},
-							},
-						},
-					},
-				},
-			},
-			"Conflict": {
-				Description: "Resource conflict (duplicate name)",
-				Content: map[string]*MediaType{
-					"application/json": {
-						Schema: &Schema{
-							Type: "object",
-							Properties: map[string]*Schema{
-								"error": {Type: "string", Description: "Error message"},
-							},
-						},
+	}
+}
+
+// RegisterStandardResponses installs the canonical Error schema and the shared
+// BadRequest, NotFound, Conflict, and InternalError responses that describe
+// bodies written by handlers.RespondError. Definitions already present in the
+// spec are left unchanged.
+func RegisterStandardResponses(spec *Spec) {
+	if spec.Components == nil {
+		spec.Components = &Components{}
+	}
+	c := spec.Components
+
+	if c.Schemas == nil {
+		c.Schemas = make(map[string]*Schema)
+	}
+	if _, ok := c.Schemas["Error"]; !ok {
+		c.Schemas["Error"] = errorSchema()
+	}
+
+	if c.Responses == nil {
+		c.Responses = make(map[string]*Response)
+	}
+	for name, desc := range standardResponses {
+		if _, ok := c.Responses[name]; !ok {
+			c.Responses[name] = ResponseJSON(desc, "Error")
+		}
+	}
+}
+
+var standardResponses = map[string]string{
+	"BadRequest":    "Invalid request",
+	"NotFound":      "Resource not found",
+	"Conflict":      "Resource conflict (duplicate name)",
+	"InternalError": "Internal server error",
+}
+
+func errorSchema() *Schema {
+	return &Schema{
+		Type:          "object",
+		Required:      []string{"error"},
+		PropertyOrder: []string{"error", "request_id", "violations"},
+		Properties: map[string]*Schema{
+			"error":      {Type: "string", Description: "Error message"},
+			"request_id": {Type: "string", Description: "Request ID to quote when reporting the error"},
+			"violations": {
+				Type:        "array",
+				Description: "Schema violations, present when request validation fails",
+				Items: &Schema{
+					Type:     "object",
+					Required: []string{"location", "message"},
+					Properties: map[string]*Schema{
+						"location": {Type: "string", Description: "Offending value, such as query.page or body.name"},
+						"message":  {Type: "string"},
 					},
 				},
 			},
@@ -61,6 +85,18 @@ func NewComponents() *Components {
 	}
 }
 
+// ErrBadRequest references the shared BadRequest response.
+func ErrBadRequest() *Response { return ResponseRef("BadRequest") }
+
+// ErrNotFound references the shared NotFound response.
+func ErrNotFound() *Response { return ResponseRef("NotFound") }
+
+// ErrConflict references the shared Conflict response.
+func ErrConflict() *Response { return ResponseRef("Conflict") }
+
+// ErrInternal references the shared InternalError response.
+func ErrInternal() *Response { return ResponseRef("InternalError") }
+
 // AddSchemas merges the provided schemas into the Components schemas map.
 func (c *Components) AddSchemas(schemas map[string]*Schema) {
 	maps.Copy(c.Schemas, schemas)
@@ -68,6 +104,49 @@ func (c *Components) AddSchemas(schemas map[string]*Schema) {
 
 // AddResponses merges the provided responses into the Components responses map.
 func (c *Components) AddResponses(responses map[string]*Response) {
+	if c.Responses == nil {
+		c.Responses = make(map[string]*Response, len(responses))
+	}
 	maps.Copy(c.Responses, responses)
 }
 
+// AddParameters merges the provided parameters into the Components parameters map.
+func (c *Components) AddParameters(params map[string]*Parameter) {
+	if c.Parameters == nil {
+		c.Parameters = make(map[string]*Parameter, len(params))
+	}
+	maps.Copy(c.Parameters, params)
+}
+
+// AddRequestBodies merges the provided request bodies into the Components request bodies map.
+func (c *Components) AddRequestBodies(bodies map[string]*RequestBody) {
+	if c.RequestBodies == nil {
+		c.RequestBodies = make(map[string]*RequestBody, len(bodies))
+	}
+	maps.Copy(c.RequestBodies, bodies)
+}
+
+// AddHeaders merges the provided headers into the Components headers map.
+func (c *Components) AddHeaders(headers map[string]*Header) {
+	if c.Headers == nil {
+		c.Headers = make(map[string]*Header, len(headers))
+	}
+	maps.Copy(c.Headers, headers)
+}
+
+// AddLinks merges the provided links into the Components links map.
+func (c *Components) AddLinks(links map[string]*Link) {
+	if c.Links == nil {
+		c.Links = make(map[string]*Link, len(links))
+	}
+	maps.Copy(c.Links, links)
+}
+
+// AddSecuritySchemes merges the provided schemes into the Components security schemes map.
+func (c *Components) AddSecuritySchemes(schemes map[string]*SecurityScheme) {
+	if c.SecuritySchemes == nil {
+		c.SecuritySchemes = make(map[string]*SecurityScheme, len(schemes))
+	}
+	maps.Copy(c.SecuritySchemes, schemes)
+}
+
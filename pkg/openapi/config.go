@@ -1,6 +1,6 @@
 package openapi
 
-import "os"
+import "github.com/JaimeStill/go-lit/pkg/envvar"
 
 type Config struct {
 	Title       string `toml:"title"`
@@ -15,7 +15,9 @@ type ConfigEnv struct {
 func (c *Config) Finalize(env *ConfigEnv) error {
 	c.loadDefaults()
 	if env != nil {
-		c.loadEnv(env)
+		if err := c.loadEnv(env); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -38,15 +40,19 @@ func (c *Config) loadDefaults() {
 	}
 }
 
-func (c *Config) loadEnv(env *ConfigEnv) {
+func (c *Config) loadEnv(env *ConfigEnv) error {
+	var r envvar.Reader
+
 	if env.Title != "" {
-		if v := os.Getenv(env.Title); v != "" {
+		if v := r.Get(env.Title); v != "" {
 			c.Title = v
 		}
 	}
 	if env.Description != "" {
-		if v := os.Getenv(env.Description); v != "" {
+		if v := r.Get(env.Description); v != "" {
 			c.Description = v
 		}
 	}
+
+	return r.Err()
 }
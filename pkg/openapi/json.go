@@ -6,13 +6,15 @@ import (
 )
 
 // MarshalJSON serializes a Spec to formatted JSON bytes.
+// Output is byte-stable for the same input: paths, components, and other maps
+// are emitted in sorted key order, and schema properties follow PropertyOrder.
 func MarshalJSON(spec *Spec) ([]byte, error) {
 	return json.MarshalIndent(spec, "", "  ")
 }
 
 // WriteJSON serializes a Spec to formatted JSON and writes it to a file.
 func WriteJSON(spec *Spec, filename string) error {
-	data, err := json.MarshalIndent(spec, "", "  ")
+	data, err := MarshalJSON(spec)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,148 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Merge combines the paths, components, servers, and tags of each source
+// specification into dst. Definitions present in both documents are kept
+// when their content is identical; a definition whose content differs from
+// the one already in dst is reported as a conflict and dst keeps its own.
+// All conflicts are returned joined into a single error.
+//
+// Document-level fields such as Info and Security are not merged.
+func Merge(dst *Spec, srcs ...*Spec) error {
+	var errs []error
+
+	for _, src := range srcs {
+		if src == nil {
+			continue
+		}
+		errs = append(errs, mergePaths(dst, src)...)
+		errs = append(errs, mergeComponents(dst, src)...)
+		errs = append(errs, mergeServers(dst, src)...)
+		errs = append(errs, mergeTags(dst, src)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+func mergePaths(dst, src *Spec) []error {
+	var errs []error
+
+	if dst.Paths == nil && len(src.Paths) > 0 {
+		dst.Paths = make(map[string]*PathItem, len(src.Paths))
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(src.Paths)) {
+		item := dst.Paths[path]
+		if item == nil {
+			item = &PathItem{}
+			dst.Paths[path] = item
+		}
+
+		switch src := src.Paths[path]; {
+		case len(item.Parameters) == 0:
+			item.Parameters = src.Parameters
+		case len(src.Parameters) > 0 && !reflect.DeepEqual(item.Parameters, src.Parameters):
+			errs = append(errs, fmt.Errorf("paths %s: conflicting path parameters", path))
+		}
+
+		existing := maps.Collect(item.operations())
+		for method, op := range src.Paths[path].operations() {
+			if prev, ok := existing[method]; ok {
+				if !reflect.DeepEqual(prev, op) {
+					errs = append(errs, fmt.Errorf("paths %s %s: conflicting operation definitions", method, path))
+				}
+				continue
+			}
+			if err := item.SetOperation(method, op); err != nil {
+				errs = append(errs, fmt.Errorf("paths %s %s: %w", method, path, err))
+			}
+		}
+	}
+
+	return errs
+}
+
+func mergeComponents(dst, src *Spec) []error {
+	if src.Components == nil {
+		return nil
+	}
+	if dst.Components == nil {
+		dst.Components = &Components{}
+	}
+
+	d, s := dst.Components, src.Components
+	var errs []error
+	errs = append(errs, mergeMap("components.schemas", &d.Schemas, s.Schemas)...)
+	errs = append(errs, mergeMap("components.responses", &d.Responses, s.Responses)...)
+	errs = append(errs, mergeMap("components.parameters", &d.Parameters, s.Parameters)...)
+	errs = append(errs, mergeMap("components.requestBodies", &d.RequestBodies, s.RequestBodies)...)
+	errs = append(errs, mergeMap("components.headers", &d.Headers, s.Headers)...)
+	errs = append(errs, mergeMap("components.links", &d.Links, s.Links)...)
+	errs = append(errs, mergeMap("components.securitySchemes", &d.SecuritySchemes, s.SecuritySchemes)...)
+	return errs
+}
+
+// mergeMap copies entries from src into dst, allocating dst when needed.
+// Entries already present in dst must have identical content.
+func mergeMap[V any](where string, dst *map[string]V, src map[string]V) []error {
+	if len(src) == 0 {
+		return nil
+	}
+	if *dst == nil {
+		*dst = make(map[string]V, len(src))
+	}
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(src)) {
+		if prev, ok := (*dst)[name]; ok {
+			if !reflect.DeepEqual(prev, src[name]) {
+				errs = append(errs, fmt.Errorf("%s.%s: conflicting definitions", where, name))
+			}
+			continue
+		}
+		(*dst)[name] = src[name]
+	}
+	return errs
+}
+
+func mergeServers(dst, src *Spec) []error {
+	var errs []error
+
+	for _, server := range src.Servers {
+		idx := slices.IndexFunc(dst.Servers, func(s *Server) bool { return s.URL == server.URL })
+		if idx < 0 {
+			dst.Servers = append(dst.Servers, server)
+			continue
+		}
+		if !reflect.DeepEqual(dst.Servers[idx], server) {
+			errs = append(errs, fmt.Errorf("servers %s: conflicting definitions", server.URL))
+		}
+	}
+
+	return errs
+}
+
+func mergeTags(dst, src *Spec) []error {
+	var errs []error
+
+	for _, tag := range src.Tags {
+		idx := slices.IndexFunc(dst.Tags, func(t *Tag) bool { return t.Name == tag.Name })
+		if idx >= 0 {
+			prev := dst.Tags[idx]
+			if prev.Description != "" && tag.Description != "" && prev.Description != tag.Description {
+				errs = append(errs, fmt.Errorf("tags %s: conflicting descriptions", tag.Name))
+				continue
+			}
+		}
+		dst.AddTag(tag.Name, tag.Description)
+	}
+
+	return errs
+}
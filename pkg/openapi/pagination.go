@@ -0,0 +1,102 @@
+package openapi
+
+import "github.com/JaimeStill/go-lit/pkg/pagination"
+
+// PageResultSchema creates the pagination.PageResult envelope schema with
+// data items referencing the named schema in components/schemas.
+func PageResultSchema(itemSchemaName string) *Schema {
+	return &Schema{
+		Type:          "object",
+		Required:      []string{"data", "total", "page", "page_size", "total_pages"},
+		PropertyOrder: []string{"data", "total", "page", "page_size", "total_pages"},
+		Properties: map[string]*Schema{
+			"data":        {Type: "array", Items: SchemaRef(itemSchemaName)},
+			"total":       {Type: "integer", Description: "Total number of matching items"},
+			"page":        {Type: "integer", Description: "Current page number (1-indexed)"},
+			"page_size":   {Type: "integer", Description: "Results per page"},
+			"total_pages": {Type: "integer", Description: "Total number of pages"},
+		},
+	}
+}
+
+// PageQueryParams returns the page, page_size, search, and sort query
+// parameters read by pagination.PageRequestFromQuery. The page_size default
+// and maximum reflect the configuration limits.
+func PageQueryParams(cfg pagination.Config) []*Parameter {
+	minPage := 1.0
+	maxPageSize := float64(cfg.MaxPageSize)
+
+	page := QueryParam("page", "integer", "Page number (1-indexed)", false)
+	page.Schema.Minimum = &minPage
+	page.Schema.Default = 1
+
+	pageSize := QueryParam("page_size", "integer", "Results per page", false)
+	pageSize.Schema.Minimum = &minPage
+	pageSize.Schema.Maximum = &maxPageSize
+	pageSize.Schema.Default = cfg.DefaultPageSize
+
+	return []*Parameter{
+		page,
+		pageSize,
+		QueryParam("search", "string", "Search query", false),
+		QueryParam("sort", "string", "Comma-separated sort fields. Prefix with - for descending", false),
+	}
+}
+
+// PageHeaders returns the Link and X-Total-Count response headers written
+// by pagination.PageResult.WriteLinkHeader.
+func PageHeaders() map[string]*Header {
+	return Headers(
+		HeaderString("Link", "RFC 8288 links to the first, prev, next, and last pages"),
+		NamedHeader{
+			Name:   "X-Total-Count",
+			Header: &Header{Description: "Total number of matching items", Schema: &Schema{Type: "integer"}},
+		},
+	)
+}
+
+// FilterQueryParams returns a query parameter for each field and operator
+// accepted by pagination.ParseFilters under specs, named field for OpEq and
+// field[op] otherwise, such as created_at[gte].
+func FilterQueryParams(specs ...pagination.FilterSpec) []*Parameter {
+	var params []*Parameter
+	for _, spec := range specs {
+		ops := spec.Operators
+		if len(ops) == 0 {
+			ops = []pagination.Operator{pagination.OpEq}
+		}
+		for _, op := range ops {
+			name := spec.Field
+			if op != pagination.OpEq {
+				name += "[" + string(op) + "]"
+			}
+			param := QueryParam(name, filterSchemaType(spec.Type), filterDescription(spec.Field, op), false)
+			if spec.Type == pagination.FilterTime {
+				param.Description += " (RFC 3339 time or date)"
+			}
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+func filterSchemaType(typ pagination.FilterType) string {
+	if typ == pagination.FilterTime {
+		return "string"
+	}
+	return string(typ)
+}
+
+var filterVerbs = map[pagination.Operator]string{
+	pagination.OpEq:       "equals",
+	pagination.OpNe:       "does not equal",
+	pagination.OpGt:       "is greater than",
+	pagination.OpGte:      "is at least",
+	pagination.OpLt:       "is less than",
+	pagination.OpLte:      "is at most",
+	pagination.OpContains: "contains",
+}
+
+func filterDescription(field string, op pagination.Operator) string {
+	return "Only items whose " + field + " " + filterVerbs[op] + " the value"
+}
@@ -0,0 +1,270 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// SchemaFrom generates a Schema for T by reflecting over its structure.
+// See SchemaOf for the mapping rules.
+func SchemaFrom[T any]() *Schema {
+	return SchemaOf(reflect.TypeFor[T]())
+}
+
+// SchemaOf generates a Schema for the given type.
+//
+// Struct fields follow their json tags: renamed fields use the tag name,
+// "-" fields are skipped, and embedded structs without a tag name are
+// flattened. A field is required unless it is a pointer, is tagged
+// omitempty or omitzero, or carries the openapi "optional" flag.
+//
+// Go types map to JSON Schema as follows: bool to boolean, integers to
+// integer, floats to number, strings to string, time.Time to a date-time
+// string, uuid.UUID to a uuid string, []byte to a byte string, slices and
+// arrays to array, and maps to object with additionalProperties. Interfaces
+// and types with custom JSON marshaling produce an unconstrained schema;
+// text marshalers produce string.
+//
+// Per-field metadata is read from the openapi struct tag as comma-separated
+// entries: title=..., description=..., example=..., const=..., format=...,
+// and the flags optional, readonly, and writeonly. Example and const values
+// are parsed as JSON when possible. Values may contain commas; an entry that
+// is not a recognized key continues the previous value.
+//
+// Properties are serialized in struct declaration order, with fields of
+// embedded structs at the position of the embedding.
+//
+// Recursive types are emitted as a $ref to components/schemas/<TypeName> at
+// the point of recursion, so the type must be registered under its Go name.
+func SchemaOf(t reflect.Type) *Schema {
+	g := &schemaGenerator{visiting: make(map[reflect.Type]bool)}
+	return g.schema(t)
+}
+
+type schemaGenerator struct {
+	visiting map[reflect.Type]bool
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.PkgPath() == "github.com/google/uuid" && t.Name() == "UUID":
+		return &Schema{Type: "string", Format: "uuid"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return &Schema{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return MapOf(g.schema(t.Elem()))
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		return &Schema{}
+	}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *Schema {
+	if g.visiting[t] {
+		return SchemaRef(t.Name())
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(s, t)
+
+	if len(s.Properties) == 0 {
+		s.Properties = nil
+	}
+	return s
+}
+
+func (g *schemaGenerator) addFields(s *Schema, t reflect.Type) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		name, opts := parseJSONTag(field.Tag.Get("json"))
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		ft := field.Type
+		if field.Anonymous && name == "" {
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(s, ft)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		meta := parseOpenAPITag(field.Tag.Get("openapi"))
+		if _, exists := s.Properties[name]; !exists {
+			s.PropertyOrder = append(s.PropertyOrder, name)
+		}
+		s.Properties[name] = withMetadata(g.schema(field.Type), meta)
+
+		optional := field.Type.Kind() == reflect.Pointer ||
+			hasOption(opts, "omitempty") ||
+			hasOption(opts, "omitzero") ||
+			meta.optional
+		if !optional {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// withMetadata applies field metadata. OpenAPI 3.1 permits these keywords
+// alongside $ref, so referenced schemas are annotated in place.
+func withMetadata(s *Schema, meta fieldMetadata) *Schema {
+	if meta.title != "" {
+		s.Title = meta.title
+	}
+	if meta.description != "" {
+		s.Description = meta.description
+	}
+	if meta.example != nil {
+		s.Example = meta.example
+	}
+	if meta.constant != nil {
+		s.Const = meta.constant
+	}
+	if meta.format != "" {
+		s.Format = meta.format
+	}
+	if meta.readOnly {
+		s.ReadOnly = true
+	}
+	if meta.writeOnly {
+		s.WriteOnly = true
+	}
+	return s
+}
+
+type fieldMetadata struct {
+	title       string
+	description string
+	example     any
+	constant    any
+	format      string
+	optional    bool
+	readOnly    bool
+	writeOnly   bool
+}
+
+func parseOpenAPITag(tag string) fieldMetadata {
+	var meta fieldMetadata
+	if tag == "" {
+		return meta
+	}
+
+	var entries []string
+	for part := range strings.SplitSeq(tag, ",") {
+		key, _, _ := strings.Cut(part, "=")
+		switch key {
+		case "title", "description", "example", "const", "format",
+			"optional", "readonly", "writeonly":
+			entries = append(entries, part)
+		default:
+			if len(entries) > 0 {
+				entries[len(entries)-1] += "," + part
+			}
+		}
+	}
+
+	for _, entry := range entries {
+		key, value, _ := strings.Cut(entry, "=")
+		switch key {
+		case "title":
+			meta.title = value
+		case "description":
+			meta.description = value
+		case "format":
+			meta.format = value
+		case "optional":
+			meta.optional = true
+		case "readonly":
+			meta.readOnly = true
+		case "writeonly":
+			meta.writeOnly = true
+		case "example":
+			meta.example = parseTagValue(value)
+		case "const":
+			meta.constant = parseTagValue(value)
+		}
+	}
+
+	return meta
+}
+
+// parseTagValue decodes a tag value as JSON, falling back to the raw string.
+func parseTagValue(value string) any {
+	var v any
+	if err := json.Unmarshal([]byte(value), &v); err == nil {
+		return v
+	}
+	return value
+}
+
+func parseJSONTag(tag string) (string, string) {
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, opts
+}
+
+func hasOption(opts, option string) bool {
+	for opt := range strings.SplitSeq(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package openapi
+
+import "strings"
+
+// ResolveSchema follows a schema $ref into components, returning s unchanged
+// when it is not a reference and nil when the reference does not resolve.
+func (spec *Spec) ResolveSchema(s *Schema) *Schema {
+	if s == nil || s.Ref == "" {
+		return s
+	}
+	return resolve(spec, s.Ref, schemaRefPrefix, func(c *Components) map[string]*Schema { return c.Schemas })
+}
+
+// ResolveParameter follows a parameter $ref into components, returning p
+// unchanged when it is not a reference and nil when the reference does not resolve.
+func (spec *Spec) ResolveParameter(p *Parameter) *Parameter {
+	if p == nil || p.Ref == "" {
+		return p
+	}
+	return resolve(spec, p.Ref, parameterRefPrefix, func(c *Components) map[string]*Parameter { return c.Parameters })
+}
+
+// ResolveRequestBody follows a request body $ref into components, returning b
+// unchanged when it is not a reference and nil when the reference does not resolve.
+func (spec *Spec) ResolveRequestBody(b *RequestBody) *RequestBody {
+	if b == nil || b.Ref == "" {
+		return b
+	}
+	return resolve(spec, b.Ref, requestBodyRefPrefix, func(c *Components) map[string]*RequestBody { return c.RequestBodies })
+}
+
+func resolve[V any](spec *Spec, ref, prefix string, component func(*Components) map[string]V) V {
+	var zero V
+	if spec.Components == nil || !strings.HasPrefix(ref, prefix) {
+		return zero
+	}
+	return component(spec.Components)[strings.TrimPrefix(ref, prefix)]
+}
@@ -0,0 +1,53 @@
+package openapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"github.com/JaimeStill/go-lit/pkg/handlers"
+)
+
+// serveDocument returns a handler for a pre-marshaled document. The ETag and
+// a gzip-compressed variant are computed once, so each request either answers
+// If-None-Match with 304 or writes one of the prepared bodies. Each variant
+// has its own ETag, and If-None-Match is compared only against the ETag of
+// the variant the request negotiates, so a client holding one variant is
+// never told it has the other.
+func serveDocument(contentType string, data []byte) http.HandlerFunc {
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	gzipETag := `"` + hex.EncodeToString(sum[:16]) + `-gzip"`
+
+	var buf bytes.Buffer
+	zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	zw.Write(data)
+	zw.Close()
+	compressed := buf.Bytes()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("Vary", "Accept-Encoding")
+
+		body, tag := data, etag
+		if handlers.AcceptsGzip(r.Header.Get("Accept-Encoding")) {
+			body, tag = compressed, gzipETag
+			h.Set("Content-Encoding", "gzip")
+		}
+		h.Set("ETag", tag)
+
+		if handlers.MatchesIfNoneMatch(r.Header.Get("If-None-Match"), tag) {
+			h.Del("Content-Encoding")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		h.Set("Content-Type", contentType)
+		h.Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}
+}
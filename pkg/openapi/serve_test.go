@@ -0,0 +1,68 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServeDocumentConditional(t *testing.T) {
+	handler := serveDocument("application/json", []byte(`{"openapi":"3.1.0"}`))
+
+	// Fetch each variant once to learn its ETag.
+	etagFor := func(acceptEncoding string) string {
+		req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Header().Get("ETag")
+	}
+	identity := etagFor("")
+	gzipped := etagFor("gzip")
+	if identity == "" || gzipped == "" || identity == gzipped {
+		t.Fatalf("variant ETags = %q and %q, want two distinct tags", identity, gzipped)
+	}
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		ifNoneMatch    string
+		wantStatus     int
+		wantETag       string
+		wantEncoding   string
+	}{
+		{name: "identity cached", ifNoneMatch: identity, wantStatus: http.StatusNotModified, wantETag: identity},
+		{name: "gzip cached", acceptEncoding: "gzip", ifNoneMatch: gzipped, wantStatus: http.StatusNotModified, wantETag: gzipped},
+		{name: "identity held, gzip negotiated", acceptEncoding: "gzip", ifNoneMatch: identity, wantStatus: http.StatusOK, wantETag: gzipped, wantEncoding: "gzip"},
+		{name: "gzip held, identity negotiated", ifNoneMatch: gzipped, wantStatus: http.StatusOK, wantETag: identity},
+		{name: "gzip refused", acceptEncoding: "gzip;q=0", ifNoneMatch: gzipped, wantStatus: http.StatusOK, wantETag: identity},
+		{name: "weak match", acceptEncoding: "gzip", ifNoneMatch: "W/" + gzipped, wantStatus: http.StatusNotModified, wantETag: gzipped},
+		{name: "wildcard", ifNoneMatch: "*", wantStatus: http.StatusNotModified, wantETag: identity},
+		{name: "no validator", acceptEncoding: "gzip", wantStatus: http.StatusOK, wantETag: gzipped, wantEncoding: "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", got, tt.wantETag)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if tt.wantStatus == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("304 body has %d bytes, want none", rec.Body.Len())
+			}
+		})
+	}
+}
@@ -1,14 +1,20 @@
 package openapi
 
-import "net/http"
+import (
+	"maps"
+	"net/http"
+	"slices"
+)
 
 // Spec represents a complete OpenAPI 3.1 specification document.
 type Spec struct {
-	OpenAPI    string               `json:"openapi"`
-	Info       *Info                `json:"info"`
-	Servers    []*Server            `json:"servers,omitempty"`
-	Paths      map[string]*PathItem `json:"paths"`
-	Components *Components          `json:"components,omitempty"`
+	OpenAPI    string                `json:"openapi"`
+	Info       *Info                 `json:"info"`
+	Servers    []*Server             `json:"servers,omitempty"`
+	Tags       []*Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem  `json:"paths"`
+	Components *Components           `json:"components,omitempty"`
+	Security   []SecurityRequirement `json:"security,omitempty"`
 }
 
 func NewSpec(title, version string) *Spec {
@@ -31,11 +37,95 @@ func (s *Spec) SetDescription(desc string) {
 	s.Info.Description = desc
 }
 
-func ServeSpec(specBytes []byte) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json; charset=utf-8")
-		w.WriteHeader(http.StatusOK)
-		w.Write(specBytes)
+// AddTag registers a tag in the order first seen. Adding an existing tag
+// merges it, keeping the first non-empty description.
+func (s *Spec) AddTag(name, description string) {
+	for _, tag := range s.Tags {
+		if tag.Name == name {
+			if tag.Description == "" {
+				tag.Description = description
+			}
+			return
+		}
+	}
+	s.Tags = append(s.Tags, &Tag{Name: name, Description: description})
+}
+
+// AddSecurity appends a document-level security requirement applied to
+// every operation that does not declare its own.
+func (s *Spec) AddSecurity(req SecurityRequirement) {
+	s.Security = append(s.Security, req)
+}
+
+// OperationRef identifies a documented operation by HTTP method and path.
+type OperationRef struct {
+	Method    string
+	Path      string
+	Operation *Operation
+}
+
+// Operations returns every documented operation ordered by path and then by
+// method (GET, POST, PUT, PATCH, DELETE, OPTIONS, HEAD).
+func (s *Spec) Operations() []OperationRef {
+	var refs []OperationRef
+	for _, path := range slices.Sorted(maps.Keys(s.Paths)) {
+		for method, op := range s.Paths[path].operations() {
+			refs = append(refs, OperationRef{Method: method, Path: path, Operation: op})
+		}
+	}
+	return refs
+}
+
+// Schema returns the named schema from components, or nil if it is not registered.
+func (s *Spec) Schema(name string) *Schema {
+	if s.Components == nil {
+		return nil
 	}
+	return s.Components.Schemas[name]
 }
 
+// HasPath reports whether the specification documents the path.
+func (s *Spec) HasPath(path string) bool {
+	_, ok := s.Paths[path]
+	return ok
+}
+
+// Filter returns a copy of the specification documenting only the operations
+// keep accepts, with paths left empty removed and tags limited to those the
+// remaining operations use. Components are shared with the original, so
+// unused definitions remain and the result should not be modified.
+func (s *Spec) Filter(keep func(OperationRef) bool) *Spec {
+	filtered := *s
+	filtered.Paths = make(map[string]*PathItem)
+	used := make(map[string]bool)
+
+	for _, ref := range s.Operations() {
+		if !keep(ref) {
+			continue
+		}
+		item := filtered.Paths[ref.Path]
+		if item == nil {
+			item = &PathItem{Parameters: s.Paths[ref.Path].Parameters}
+			filtered.Paths[ref.Path] = item
+		}
+		item.SetOperation(ref.Method, ref.Operation)
+		for _, tag := range ref.Operation.Tags {
+			used[tag] = true
+		}
+	}
+
+	filtered.Tags = nil
+	for _, tag := range s.Tags {
+		if used[tag.Name] {
+			filtered.Tags = append(filtered.Tags, tag)
+		}
+	}
+	return &filtered
+}
+
+// ServeSpec returns a handler that serves pre-marshaled JSON spec bytes.
+// Responses carry an ETag, answer a matching If-None-Match with 304, and are
+// gzip-compressed when the client's Accept-Encoding allows it.
+func ServeSpec(specBytes []byte) http.HandlerFunc {
+	return serveDocument("application/json; charset=utf-8", specBytes)
+}
@@ -3,6 +3,18 @@
 // with the routes system to auto-generate specifications at server startup.
 package openapi
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
 // Info provides metadata about the API.
 type Info struct {
 	Title       string `json:"title"`
@@ -10,6 +22,12 @@ type Info struct {
 	Description string `json:"description,omitempty"`
 }
 
+// Tag groups operations and provides a description for the group.
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
 // Server represents a server URL for the API.
 type Server struct {
 	URL         string `json:"url"`
@@ -18,45 +36,289 @@ type Server struct {
 
 // PathItem describes operations available on a single path.
 type PathItem struct {
-	Get    *Operation `json:"get,omitempty"`
-	Post   *Operation `json:"post,omitempty"`
-	Put    *Operation `json:"put,omitempty"`
-	Delete *Operation `json:"delete,omitempty"`
+	Get     *Operation `json:"get,omitempty"`
+	Post    *Operation `json:"post,omitempty"`
+	Put     *Operation `json:"put,omitempty"`
+	Patch   *Operation `json:"patch,omitempty"`
+	Delete  *Operation `json:"delete,omitempty"`
+	Options *Operation `json:"options,omitempty"`
+	Head    *Operation `json:"head,omitempty"`
+
+	// Parameters apply to every operation on the path. An operation
+	// parameter with the same name and location overrides one listed here.
+	Parameters []*Parameter `json:"parameters,omitempty"`
+}
+
+// SetOperation assigns the operation to the field matching the HTTP method.
+// Returns an error if the method cannot be represented on a PathItem.
+func (p *PathItem) SetOperation(method string, op *Operation) error {
+	switch method {
+	case "GET":
+		p.Get = op
+	case "POST":
+		p.Post = op
+	case "PUT":
+		p.Put = op
+	case "PATCH":
+		p.Patch = op
+	case "DELETE":
+		p.Delete = op
+	case "OPTIONS":
+		p.Options = op
+	case "HEAD":
+		p.Head = op
+	default:
+		return fmt.Errorf("unsupported operation method: %s", method)
+	}
+	return nil
+}
+
+// Operation returns the operation registered for the HTTP method, or nil.
+func (p *PathItem) Operation(method string) *Operation {
+	for m, op := range p.operations() {
+		if m == method {
+			return op
+		}
+	}
+	return nil
+}
+
+// EffectiveParameters returns the parameters that apply to op on this path:
+// the path-level parameters, overridden by operation parameters with the same
+// name and location. Parameters are compared as written, without resolving $refs.
+func (p *PathItem) EffectiveParameters(op *Operation) []*Parameter {
+	params := slices.Clone(op.Parameters)
+	for _, shared := range p.Parameters {
+		overridden := slices.ContainsFunc(op.Parameters, func(own *Parameter) bool {
+			return own.In == shared.In && own.Name == shared.Name && own.Ref == shared.Ref
+		})
+		if !overridden {
+			params = append(params, shared)
+		}
+	}
+	return params
+}
+
+// HoistParameters moves parameters shared by every operation on the path to
+// the path level, so they are declared once. Operations are replaced with
+// copies rather than modified, since operation definitions are often shared
+// package variables. Paths with fewer than two operations are left unchanged.
+// Hoisting is idempotent.
+func (p *PathItem) HoistParameters() {
+	var methods []string
+	effective := make(map[string][]*Parameter)
+	for method, op := range p.operations() {
+		methods = append(methods, method)
+		effective[method] = p.EffectiveParameters(op)
+	}
+	if len(methods) < 2 {
+		return
+	}
+
+	var shared []*Parameter
+	for _, candidate := range effective[methods[0]] {
+		if everyOperationHas(effective, candidate) {
+			shared = append(shared, candidate)
+		}
+	}
+
+	p.Parameters = shared
+	for _, method := range methods {
+		result := *p.Operation(method)
+		result.Parameters = nil
+		for _, param := range effective[method] {
+			if !containsParameter(shared, param) {
+				result.Parameters = append(result.Parameters, param)
+			}
+		}
+		p.SetOperation(method, &result)
+	}
+}
+
+func everyOperationHas(effective map[string][]*Parameter, param *Parameter) bool {
+	for _, params := range effective {
+		if !containsParameter(params, param) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsParameter(params []*Parameter, param *Parameter) bool {
+	return slices.ContainsFunc(params, func(p *Parameter) bool { return reflect.DeepEqual(p, param) })
+}
+
+// operations yields the path item's non-nil operations keyed by HTTP method
+// in a fixed order.
+func (p *PathItem) operations() iter.Seq2[string, *Operation] {
+	return func(yield func(string, *Operation) bool) {
+		ops := []struct {
+			method string
+			op     *Operation
+		}{
+			{"GET", p.Get},
+			{"POST", p.Post},
+			{"PUT", p.Put},
+			{"PATCH", p.Patch},
+			{"DELETE", p.Delete},
+			{"OPTIONS", p.Options},
+			{"HEAD", p.Head},
+		}
+		for _, o := range ops {
+			if o.op != nil && !yield(o.method, o.op) {
+				return
+			}
+		}
+	}
 }
 
 // Operation describes a single API operation on a path.
 type Operation struct {
-	Summary     string            `json:"summary,omitempty"`
-	Description string            `json:"description,omitempty"`
-	Tags        []string          `json:"tags,omitempty"`
-	Parameters  []*Parameter      `json:"parameters,omitempty"`
-	RequestBody *RequestBody      `json:"requestBody,omitempty"`
-	Responses   map[int]*Response `json:"responses"`
+	OperationID string       `json:"operationId,omitempty"`
+	Summary     string       `json:"summary,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Parameters  []*Parameter `json:"parameters,omitempty"`
+	RequestBody *RequestBody `json:"requestBody,omitempty"`
+	Responses   Responses    `json:"responses"`
+	Deprecated  bool         `json:"deprecated,omitempty"`
+
+	// Security overrides the document-level requirements when non-nil.
+	// An empty, non-nil slice opts the operation out of authentication.
+	Security []SecurityRequirement `json:"security,omitzero"`
+
+	// Servers overrides the document-level servers, such as for an
+	// operation served only on a specific host.
+	Servers []*Server `json:"servers,omitempty"`
+}
+
+// Responses maps status codes to the responses an operation can return.
+// Keys are HTTP status codes, StatusDefault, or a range such as Status5XX,
+// so literals keep integer keys: Responses{200: ..., StatusDefault: ...}.
+type Responses map[StatusCode]*Response
+
+// ResponsesFrom converts a map keyed by plain integers to Responses.
+func ResponsesFrom(m map[int]*Response) Responses {
+	responses := make(Responses, len(m))
+	for status, r := range m {
+		responses[StatusCode(status)] = r
+	}
+	return responses
+}
+
+// StatusCode is a Responses key. It serializes as the status code, such as
+// "200", as "default" for StatusDefault, and as "1XX" through "5XX" for ranges.
+type StatusCode int
+
+// Response keys that are not individual status codes.
+const (
+	StatusDefault StatusCode = 0
+	Status1XX     StatusCode = 1
+	Status2XX     StatusCode = 2
+	Status3XX     StatusCode = 3
+	Status4XX     StatusCode = 4
+	Status5XX     StatusCode = 5
+)
+
+// String returns the key as written in the specification.
+func (c StatusCode) String() string {
+	switch {
+	case c == StatusDefault:
+		return "default"
+	case c >= Status1XX && c <= Status5XX:
+		return strconv.Itoa(int(c)) + "XX"
+	default:
+		return strconv.Itoa(int(c))
+	}
+}
+
+// Valid reports whether the key is default, a status range, or a status
+// code between 100 and 599.
+func (c StatusCode) Valid() bool {
+	return c >= StatusDefault && c <= Status5XX || c >= 100 && c <= 599
+}
+
+// MarshalText implements encoding.TextMarshaler so map keys serialize as strings.
+func (c StatusCode) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *StatusCode) UnmarshalText(text []byte) error {
+	s := string(text)
+	switch {
+	case s == "default":
+		*c = StatusDefault
+		return nil
+	case len(s) == 3 && strings.HasSuffix(strings.ToUpper(s), "XX") && s[0] >= '1' && s[0] <= '5':
+		*c = StatusCode(s[0] - '0')
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid response status %q", s)
+	}
+	*c = StatusCode(n)
+	return nil
 }
 
 // Parameter describes a single operation parameter (path, query, header, or cookie).
+// A parameter with Ref set refers to components/parameters and carries no other fields.
 type Parameter struct {
-	Name        string  `json:"name"`
-	In          string  `json:"in"`
+	Name        string  `json:"name,omitempty"`
+	In          string  `json:"in,omitempty"`
 	Required    bool    `json:"required,omitempty"`
 	Description string  `json:"description,omitempty"`
-	Schema      *Schema `json:"schema"`
+	Deprecated  bool    `json:"deprecated,omitempty"`
+	Style       string  `json:"style,omitempty"`
+	Explode     *bool   `json:"explode,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+	Ref         string  `json:"$ref,omitempty"`
 }
 
 // RequestBody describes a single request body.
+// A request body with Ref set refers to components/requestBodies.
 type RequestBody struct {
 	Description string                `json:"description,omitempty"`
 	Required    bool                  `json:"required,omitempty"`
-	Content     map[string]*MediaType `json:"content"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+	Ref         string                `json:"$ref,omitempty"`
 }
 
 // Response describes a single response from an API operation.
+// Description is required unless Ref refers to a response in components.
 type Response struct {
-	Description string                `json:"description"`
+	Description string                `json:"description,omitempty"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
 	Content     map[string]*MediaType `json:"content,omitempty"`
+	Links       map[string]*Link      `json:"links,omitempty"`
 	Ref         string                `json:"$ref,omitempty"`
 }
 
+// Link describes a follow-up operation reachable from a response.
+// Parameters map the target operation's parameter names to values or
+// runtime expressions, such as "$response.body#/id".
+type Link struct {
+	OperationID string         `json:"operationId,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Ref         string         `json:"$ref,omitempty"`
+}
+
+// Header describes a single response header.
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+	Ref         string  `json:"$ref,omitempty"`
+}
+
+// NamedHeader pairs a header with its name for building Response.Headers.
+type NamedHeader struct {
+	Name   string
+	Header *Header
+}
+
 // MediaType provides schema and examples for a media type.
 type MediaType struct {
 	Schema *Schema `json:"schema,omitempty"`
@@ -68,6 +330,7 @@ type MediaType struct {
 type Schema struct {
 	Type        string             `json:"type,omitempty"`
 	Format      string             `json:"format,omitempty"`
+	Title       string             `json:"title,omitempty"`
 	Description string             `json:"description,omitempty"`
 	Properties  map[string]*Schema `json:"properties,omitempty"`
 	Required    []string           `json:"required,omitempty"`
@@ -77,20 +340,179 @@ type Schema struct {
 	Example any   `json:"example,omitempty"`
 	Default any   `json:"default,omitempty"`
 	Enum    []any `json:"enum,omitempty"`
+	Const   any   `json:"const,omitempty"`
+
+	// ReadOnly properties appear only in responses; WriteOnly properties
+	// appear only in requests.
+	ReadOnly  bool `json:"readOnly,omitempty"`
+	WriteOnly bool `json:"writeOnly,omitempty"`
+
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64 `json:"multipleOf,omitempty"`
+	MinLength        *int     `json:"minLength,omitempty"`
+	MaxLength        *int     `json:"maxLength,omitempty"`
+	Pattern          string   `json:"pattern,omitempty"`
+
+	MinItems    *int `json:"minItems,omitempty"`
+	MaxItems    *int `json:"maxItems,omitempty"`
+	UniqueItems bool `json:"uniqueItems,omitempty"`
+
+	OneOf         []*Schema      `json:"oneOf,omitempty"`
+	AnyOf         []*Schema      `json:"anyOf,omitempty"`
+	AllOf         []*Schema      `json:"allOf,omitempty"`
+	Not           *Schema        `json:"not,omitempty"`
+	Discriminator *Discriminator `json:"discriminator,omitempty"`
+
+	// AdditionalProperties constrains the values of undeclared object properties.
+	// Set NoAdditionalProperties to emit additionalProperties: false instead.
+	AdditionalProperties   *Schema `json:"-"`
+	NoAdditionalProperties bool    `json:"-"`
 
-	Minimum   *float64 `json:"minimum,omitempty"`
-	Maximum   *float64 `json:"maximum,omitempty"`
-	MinLength *int     `json:"minLength,omitempty"`
-	MaxLength *int     `json:"maxLength,omitempty"`
-	Pattern   string   `json:"pattern,omitempty"`
+	// Nullable permits null in addition to Type. Per OpenAPI 3.1 it is
+	// serialized as a type array, such as ["string", "null"]. A schema
+	// without a Type, such as a $ref or oneOf, is serialized as an anyOf of
+	// the schema and {"type": "null"} instead.
+	Nullable bool `json:"-"`
+
+	// PropertyOrder lists property names in the order they are serialized.
+	// SchemaFrom records struct declaration order; properties not listed
+	// follow in alphabetical order.
+	PropertyOrder []string `json:"-"`
 }
 
-// Components holds reusable schema and response definitions.
+// MarshalJSON serializes the schema, emitting type as an array when the
+// schema is nullable, properties in PropertyOrder, and additionalProperties
+// as either a schema or false. A nullable schema without a type is wrapped
+// in an anyOf permitting null.
+func (s Schema) MarshalJSON() ([]byte, error) {
+	if s.Nullable && s.Type == "" {
+		inner := s
+		inner.Nullable = false
+		return json.Marshal(struct {
+			AnyOf []*Schema `json:"anyOf"`
+		}{AnyOf: []*Schema{&inner, {Type: "null"}}})
+	}
+
+	type alias Schema
+	aux := struct {
+		Type any `json:"type,omitempty"`
+		alias
+		Properties           *orderedProperties `json:"properties,omitempty"`
+		AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	}{alias: alias(s)}
+
+	if len(s.Properties) > 0 {
+		aux.Properties = &orderedProperties{properties: s.Properties, order: s.PropertyOrder}
+	}
+
+	switch {
+	case s.Nullable && s.Type != "":
+		aux.Type = []string{s.Type, "null"}
+	case s.Type != "":
+		aux.Type = s.Type
+	}
+
+	switch {
+	case s.NoAdditionalProperties:
+		aux.AdditionalProperties = false
+	case s.AdditionalProperties != nil:
+		aux.AdditionalProperties = s.AdditionalProperties
+	}
+
+	return json.Marshal(aux)
+}
+
+// orderedProperties serializes a properties map with the names in order
+// first and the remainder sorted, so output is stable across runs.
+type orderedProperties struct {
+	properties map[string]*Schema
+	order      []string
+}
+
+func (p *orderedProperties) MarshalJSON() ([]byte, error) {
+	names := make([]string, 0, len(p.properties))
+	for _, name := range p.order {
+		if _, ok := p.properties[name]; ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(p.properties)) {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(p.properties[name])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// Discriminator identifies which oneOf or anyOf schema applies to a payload
+// based on the value of a property. Mapping values are schema references.
+type Discriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
+}
+
+// Components holds reusable schema, response, header, and security scheme definitions.
 type Components struct {
-	Schemas   map[string]*Schema   `json:"schemas,omitempty"`
-	Responses map[string]*Response `json:"responses,omitempty"`
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	Responses       map[string]*Response       `json:"responses,omitempty"`
+	Parameters      map[string]*Parameter      `json:"parameters,omitempty"`
+	RequestBodies   map[string]*RequestBody    `json:"requestBodies,omitempty"`
+	Headers         map[string]*Header         `json:"headers,omitempty"`
+	Links           map[string]*Link           `json:"links,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme defines an authentication mechanism usable by operations.
+// Type is one of apiKey, http, or oauth2.
+type SecurityScheme struct {
+	Type         string      `json:"type"`
+	Description  string      `json:"description,omitempty"`
+	Name         string      `json:"name,omitempty"`
+	In           string      `json:"in,omitempty"`
+	Scheme       string      `json:"scheme,omitempty"`
+	BearerFormat string      `json:"bearerFormat,omitempty"`
+	Flows        *OAuthFlows `json:"flows,omitempty"`
+}
+
+// OAuthFlows lists the OAuth2 flows supported by a security scheme.
+type OAuthFlows struct {
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+}
+
+// OAuthFlow configures a single OAuth2 flow.
+type OAuthFlow struct {
+	TokenURL string            `json:"tokenUrl"`
+	Scopes   map[string]string `json:"scopes"`
 }
 
+// SecurityRequirement maps security scheme names to the scopes they require.
+// Schemes that do not use scopes map to an empty list.
+type SecurityRequirement map[string][]string
+
 // SchemaRef creates a JSON reference to a schema in components/schemas.
 func SchemaRef(name string) *Schema {
 	return &Schema{Ref: "#/components/schemas/" + name}
@@ -101,6 +523,150 @@ func ResponseRef(name string) *Response {
 	return &Response{Ref: "#/components/responses/" + name}
 }
 
+// ParameterRef creates a JSON reference to a parameter in components/parameters.
+func ParameterRef(name string) *Parameter {
+	return &Parameter{Ref: "#/components/parameters/" + name}
+}
+
+// RequestBodyRef creates a JSON reference to a request body in components/requestBodies.
+func RequestBodyRef(name string) *RequestBody {
+	return &RequestBody{Ref: "#/components/requestBodies/" + name}
+}
+
+// LinkRef creates a JSON reference to a link in components/links.
+func LinkRef(name string) *Link {
+	return &Link{Ref: "#/components/links/" + name}
+}
+
+// HeaderString creates a named response header with a string schema.
+func HeaderString(name, description string) NamedHeader {
+	return NamedHeader{
+		Name:   name,
+		Header: &Header{Description: description, Schema: &Schema{Type: "string"}},
+	}
+}
+
+// HeaderRef creates a named response header referencing components/headers.
+func HeaderRef(name, component string) NamedHeader {
+	return NamedHeader{
+		Name:   name,
+		Header: &Header{Ref: "#/components/headers/" + component},
+	}
+}
+
+// Headers builds a Response.Headers map from named headers.
+func Headers(headers ...NamedHeader) map[string]*Header {
+	m := make(map[string]*Header, len(headers))
+	for _, h := range headers {
+		m[h.Name] = h.Header
+	}
+	return m
+}
+
+// BearerAuth creates an HTTP bearer authentication scheme.
+func BearerAuth() *SecurityScheme {
+	return &SecurityScheme{Type: "http", Scheme: "bearer"}
+}
+
+// APIKeyHeader creates an API key scheme read from the named request header.
+func APIKeyHeader(name string) *SecurityScheme {
+	return &SecurityScheme{Type: "apiKey", In: "header", Name: name}
+}
+
+// OAuth2ClientCredentials creates an OAuth2 scheme using the client credentials flow.
+func OAuth2ClientCredentials(tokenURL string, scopes map[string]string) *SecurityScheme {
+	if scopes == nil {
+		scopes = map[string]string{}
+	}
+	return &SecurityScheme{
+		Type: "oauth2",
+		Flows: &OAuthFlows{
+			ClientCredentials: &OAuthFlow{TokenURL: tokenURL, Scopes: scopes},
+		},
+	}
+}
+
+// Require creates a security requirement for the named scheme and scopes.
+func Require(scheme string, scopes ...string) SecurityRequirement {
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return SecurityRequirement{scheme: scopes}
+}
+
+// NoSecurity returns the requirement list that opts an operation out of
+// document-level security.
+func NoSecurity() []SecurityRequirement {
+	return []SecurityRequirement{}
+}
+
+// Nullable returns a copy of the schema that also permits null. Schemas
+// with a Type are serialized with a type array; others, such as a SchemaRef
+// or a oneOf, are wrapped in an anyOf with {"type": "null"}.
+func Nullable(s *Schema) *Schema {
+	nullable := *s
+	nullable.Nullable = true
+	return &nullable
+}
+
+// NumberRange creates a number schema bounded by min and max.
+// When exclusive is true the bounds are emitted as exclusiveMinimum and
+// exclusiveMaximum, which OpenAPI 3.1 expresses as numbers.
+func NumberRange(min, max float64, exclusive bool) *Schema {
+	s := &Schema{Type: "number"}
+	if exclusive {
+		s.ExclusiveMinimum = &min
+		s.ExclusiveMaximum = &max
+	} else {
+		s.Minimum = &min
+		s.Maximum = &max
+	}
+	return s
+}
+
+// EnumOf creates a schema restricted to the given values, keeping enums tied
+// to their Go constants. The type is string or integer according to T.
+func EnumOf[T ~string | ~int](values ...T) *Schema {
+	s := &Schema{Type: "string", Enum: make([]any, len(values))}
+	if reflect.TypeFor[T]().Kind() == reflect.Int {
+		s.Type = "integer"
+	}
+	for i, v := range values {
+		s.Enum[i] = v
+	}
+	return s
+}
+
+// EnumOfDefault creates an enum schema like EnumOf, with the first value as the default.
+func EnumOfDefault[T ~string | ~int](values ...T) *Schema {
+	s := EnumOf(values...)
+	if len(values) > 0 {
+		s.Default = values[0]
+	}
+	return s
+}
+
+// MapOf creates an object schema whose arbitrary string keys map to values
+// matching valueSchema.
+func MapOf(valueSchema *Schema) *Schema {
+	return &Schema{Type: "object", AdditionalProperties: valueSchema}
+}
+
+// OneOf creates a schema matching exactly one of the given schemas.
+func OneOf(schemas ...*Schema) *Schema {
+	return &Schema{OneOf: schemas}
+}
+
+// AnyOf creates a schema matching at least one of the given schemas.
+func AnyOf(schemas ...*Schema) *Schema {
+	return &Schema{AnyOf: schemas}
+}
+
+// AllOf creates a schema matching all of the given schemas.
+func AllOf(schemas ...*Schema) *Schema {
+	return &Schema{AllOf: schemas}
+}
+
 // RequestBodyJSON creates a request body with JSON content type referencing a schema.
 func RequestBodyJSON(schemaName string, required bool) *RequestBody {
 	return &RequestBody{
@@ -111,6 +677,40 @@ func RequestBodyJSON(schemaName string, required bool) *RequestBody {
 	}
 }
 
+// RequestBodyContent creates a request body accepting several media types,
+// such as application/json and application/x-www-form-urlencoded. The content
+// map pairs each media type with a schema name in components/schemas; an
+// empty name documents the media type without a schema.
+func RequestBodyContent(content map[string]string, required bool) *RequestBody {
+	return &RequestBody{
+		Required: required,
+		Content:  mediaTypes(content),
+	}
+}
+
+// ResponseContent creates a response offering several media types, chosen by
+// content negotiation on the Accept header. The content map pairs each media
+// type with a schema name in components/schemas; an empty name documents the
+// media type without a schema.
+func ResponseContent(description string, content map[string]string) *Response {
+	return &Response{
+		Description: description,
+		Content:     mediaTypes(content),
+	}
+}
+
+func mediaTypes(content map[string]string) map[string]*MediaType {
+	result := make(map[string]*MediaType, len(content))
+	for mediaType, schemaName := range content {
+		mt := &MediaType{}
+		if schemaName != "" {
+			mt.Schema = SchemaRef(schemaName)
+		}
+		result[mediaType] = mt
+	}
+	return result
+}
+
 // ResponseJSON creates a response with JSON content type referencing a schema.
 func ResponseJSON(description, schemaName string) *Response {
 	return &Response{
@@ -121,6 +721,31 @@ func ResponseJSON(description, schemaName string) *Response {
 	}
 }
 
+// SSEDone is the data payload of the terminal event of a server-sent event stream.
+const SSEDone = "[DONE]"
+
+// ResponseSSE creates a text/event-stream response. OpenAPI 3.1 has no
+// keyword for stream items, so by convention the stream is documented as an
+// array whose items are the data payloads, referencing a schema in
+// components/schemas. The description notes the terminal SSEDone event.
+func ResponseSSE(description, eventSchemaName string) *Response {
+	return &Response{
+		Description: fmt.Sprintf("%s. Each event's data is a JSON %s; the stream ends with a data: %s event.",
+			strings.TrimSuffix(description, "."), eventSchemaName, SSEDone),
+		Content: map[string]*MediaType{
+			"text/event-stream": {
+				Schema: &Schema{Type: "array", Items: SchemaRef(eventSchemaName)},
+			},
+		},
+	}
+}
+
+// ResponseEmpty creates a response with no content, such as those
+// returned by HEAD and OPTIONS operations.
+func ResponseEmpty(description string) *Response {
+	return &Response{Description: description}
+}
+
 // PathParam creates a required path parameter with UUID format.
 func PathParam(name, description string) *Parameter {
 	return &Parameter{
@@ -143,3 +768,39 @@ func QueryParam(name, typ, description string, required bool) *Parameter {
 	}
 }
 
+// QueryObjectParam creates a query parameter encoded as a deepObject,
+// such as filter[name]=x&filter[status]=y, referencing a schema in components/schemas.
+func QueryObjectParam(name, schemaName, description string, required bool) *Parameter {
+	explode := true
+	return &Parameter{
+		Name:        name,
+		In:          "query",
+		Required:    required,
+		Description: description,
+		Style:       "deepObject",
+		Explode:     &explode,
+		Schema:      SchemaRef(schemaName),
+	}
+}
+
+// HeaderParam creates a string header parameter.
+func HeaderParam(name, description string, required bool) *Parameter {
+	return &Parameter{
+		Name:        name,
+		In:          "header",
+		Required:    required,
+		Description: description,
+		Schema:      &Schema{Type: "string"},
+	}
+}
+
+// CookieParam creates a string cookie parameter.
+func CookieParam(name, description string, required bool) *Parameter {
+	return &Parameter{
+		Name:        name,
+		In:          "cookie",
+		Required:    required,
+		Description: description,
+		Schema:      &Schema{Type: "string"},
+	}
+}
@@ -0,0 +1,42 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNullableMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema *Schema
+		want   string
+	}{
+		{
+			name:   "typed",
+			schema: Nullable(&Schema{Type: "string"}),
+			want:   `{"type":["string","null"]}`,
+		},
+		{
+			name:   "ref",
+			schema: Nullable(SchemaRef("Prompt")),
+			want:   `{"anyOf":[{"$ref":"#/components/schemas/Prompt"},{"type":"null"}]}`,
+		},
+		{
+			name:   "oneOf",
+			schema: Nullable(&Schema{OneOf: []*Schema{{Type: "string"}, {Type: "integer"}}}),
+			want:   `{"anyOf":[{"oneOf":[{"type":"string"},{"type":"integer"}]},{"type":"null"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.schema)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
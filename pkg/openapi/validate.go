@@ -0,0 +1,322 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	schemaRefPrefix      = "#/components/schemas/"
+	responseRefPrefix    = "#/components/responses/"
+	parameterRefPrefix   = "#/components/parameters/"
+	requestBodyRefPrefix = "#/components/requestBodies/"
+	headerRefPrefix      = "#/components/headers/"
+	linkRefPrefix        = "#/components/links/"
+)
+
+// parameterStyles lists the serialization styles permitted for each parameter location.
+var parameterStyles = map[string][]string{
+	"path":   {"matrix", "label", "simple"},
+	"query":  {"form", "spaceDelimited", "pipeDelimited", "deepObject"},
+	"header": {"simple"},
+	"cookie": {"form"},
+}
+
+// Validate checks the specification for structural errors and returns all
+// problems found joined into a single error. It verifies that:
+//   - every $ref resolves to an entry in Components
+//   - every operation declares at least one response
+//   - every response declares a description
+//   - every response key is a status code, a status range, or default
+//   - every path parameter in a URL template has a matching Parameter,
+//     declared on the operation or the path, and every declared path
+//     parameter appears in the template
+//   - parameter names are unique per location within an operation
+//   - parameter locations and styles are valid for each other
+//   - operation IDs are unique across the document
+//   - every link targets an operation ID declared in the document
+func (s *Spec) Validate() error {
+	v := &validator{spec: s, operationIDs: make(map[string]string)}
+
+	for _, path := range slices.Sorted(maps.Keys(s.Paths)) {
+		item := s.Paths[path]
+		v.parameters(path, item.Parameters)
+		for method, op := range item.operations() {
+			v.operation(method+" "+path, path, item, op)
+		}
+	}
+
+	if s.Components != nil {
+		for _, name := range slices.Sorted(maps.Keys(s.Components.Schemas)) {
+			v.schema("components.schemas."+name, s.Components.Schemas[name])
+		}
+		for _, name := range slices.Sorted(maps.Keys(s.Components.Responses)) {
+			v.response("components.responses."+name, s.Components.Responses[name])
+		}
+		for _, name := range slices.Sorted(maps.Keys(s.Components.Parameters)) {
+			v.parameter("components.parameters."+name, s.Components.Parameters[name])
+		}
+		for _, name := range slices.Sorted(maps.Keys(s.Components.RequestBodies)) {
+			v.requestBody("components.requestBodies."+name, s.Components.RequestBodies[name])
+		}
+		for _, name := range slices.Sorted(maps.Keys(s.Components.Headers)) {
+			v.header("components.headers."+name, s.Components.Headers[name])
+		}
+		for _, name := range slices.Sorted(maps.Keys(s.Components.Links)) {
+			v.link("components.links."+name, s.Components.Links[name])
+		}
+	}
+
+	// Links may target operations declared later in the document, so their
+	// operation IDs are checked once every operation has been seen.
+	for _, l := range v.links {
+		if _, ok := v.operationIDs[l.operationID]; !ok {
+			v.fail(l.where, "link targets unknown operationId %q", l.operationID)
+		}
+	}
+
+	return errors.Join(v.errs...)
+}
+
+type validator struct {
+	spec         *Spec
+	operationIDs map[string]string
+	links        []pendingLink
+	errs         []error
+}
+
+type pendingLink struct {
+	where       string
+	operationID string
+}
+
+func (v *validator) fail(where, format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", where, fmt.Sprintf(format, args...)))
+}
+
+func (v *validator) operation(where, path string, item *PathItem, op *Operation) {
+	if op.OperationID != "" {
+		if prev, ok := v.operationIDs[op.OperationID]; ok {
+			v.fail(where, "duplicate operationId %q (also on %s)", op.OperationID, prev)
+		} else {
+			v.operationIDs[op.OperationID] = where
+		}
+	}
+
+	if len(op.Responses) == 0 {
+		v.fail(where, "no responses declared")
+	}
+
+	seen := v.parameters(where, op.Parameters)
+	for _, p := range item.Parameters {
+		if p = v.spec.ResolveParameter(p); p != nil {
+			seen[p.In+":"+p.Name] = true
+		}
+	}
+
+	templated := PathTemplateParams(path)
+	for _, name := range templated {
+		if !seen["path:"+name] {
+			v.fail(where, "path parameter %q is not declared", name)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(seen)) {
+		if name, ok := strings.CutPrefix(key, "path:"); ok && !slices.Contains(templated, name) {
+			v.fail(where, "path parameter %q has no matching segment in the path", name)
+		}
+	}
+
+	if op.RequestBody != nil {
+		v.requestBody(where+" requestBody", op.RequestBody)
+	}
+
+	for _, status := range slices.Sorted(maps.Keys(op.Responses)) {
+		if !status.Valid() {
+			v.fail(where, "invalid response status %s", status)
+		}
+		v.response(fmt.Sprintf("%s response %s", where, status), op.Responses[status])
+	}
+}
+
+// parameters validates a parameter list, reporting duplicates by location and
+// name, and returns the set of in:name keys it declares.
+func (v *validator) parameters(where string, params []*Parameter) map[string]bool {
+	seen := make(map[string]bool, len(params))
+	for _, p := range params {
+		v.parameter(where, p)
+		if p = v.spec.ResolveParameter(p); p == nil {
+			continue
+		}
+		key := p.In + ":" + p.Name
+		if seen[key] {
+			v.fail(where, "duplicate %s parameter %q", p.In, p.Name)
+		}
+		seen[key] = true
+	}
+	return seen
+}
+
+func (v *validator) parameter(where string, p *Parameter) {
+	if p == nil {
+		v.fail(where, "parameter is nil")
+		return
+	}
+	if p.Ref != "" {
+		v.ref(where, p.Ref)
+		return
+	}
+	styles, ok := parameterStyles[p.In]
+	if !ok {
+		v.fail(where, "parameter %q has invalid location %q", p.Name, p.In)
+	} else if p.Style != "" && !slices.Contains(styles, p.Style) {
+		v.fail(where, "parameter %q: style %q is not valid in %s (must be one of %s)",
+			p.Name, p.Style, p.In, strings.Join(styles, ", "))
+	}
+	v.schema(fmt.Sprintf("%s parameter %s", where, p.Name), p.Schema)
+}
+
+func (v *validator) requestBody(where string, b *RequestBody) {
+	if b == nil {
+		v.fail(where, "request body is nil")
+		return
+	}
+	if b.Ref != "" {
+		v.ref(where, b.Ref)
+		return
+	}
+	for _, ct := range slices.Sorted(maps.Keys(b.Content)) {
+		if mt := b.Content[ct]; mt != nil {
+			v.schema(where+" "+ct, mt.Schema)
+		}
+	}
+}
+
+func (v *validator) response(where string, r *Response) {
+	if r == nil {
+		v.fail(where, "response is nil")
+		return
+	}
+	if r.Ref != "" {
+		v.ref(where, r.Ref)
+		return
+	}
+	if r.Description == "" {
+		v.fail(where, "response description is required")
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.Headers)) {
+		v.header(where+" header "+name, r.Headers[name])
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.Links)) {
+		v.link(where+" link "+name, r.Links[name])
+	}
+	for _, ct := range slices.Sorted(maps.Keys(r.Content)) {
+		if mt := r.Content[ct]; mt != nil {
+			v.schema(where+" "+ct, mt.Schema)
+		}
+	}
+}
+
+func (v *validator) header(where string, h *Header) {
+	if h == nil {
+		v.fail(where, "header is nil")
+		return
+	}
+	if h.Ref != "" {
+		v.ref(where, h.Ref)
+		return
+	}
+	v.schema(where, h.Schema)
+}
+
+func (v *validator) link(where string, l *Link) {
+	switch {
+	case l == nil:
+		v.fail(where, "link is nil")
+	case l.Ref != "":
+		v.ref(where, l.Ref)
+	case l.OperationID == "":
+		v.fail(where, "link must declare an operationId")
+	default:
+		v.links = append(v.links, pendingLink{where: where, operationID: l.OperationID})
+	}
+}
+
+func (v *validator) schema(where string, s *Schema) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		v.ref(where, s.Ref)
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.Properties)) {
+		v.schema(where+"."+name, s.Properties[name])
+	}
+	v.schema(where+"[]", s.Items)
+	v.schemas(where+".oneOf", s.OneOf)
+	v.schemas(where+".anyOf", s.AnyOf)
+	v.schemas(where+".allOf", s.AllOf)
+	v.schema(where+".not", s.Not)
+	v.schema(where+".additionalProperties", s.AdditionalProperties)
+
+	if s.Discriminator != nil {
+		for _, key := range slices.Sorted(maps.Keys(s.Discriminator.Mapping)) {
+			target := s.Discriminator.Mapping[key]
+			if !strings.HasPrefix(target, "#") {
+				target = schemaRefPrefix + target
+			}
+			v.ref(where+".discriminator."+key, target)
+		}
+	}
+}
+
+func (v *validator) schemas(where string, list []*Schema) {
+	for i, s := range list {
+		v.schema(fmt.Sprintf("%s[%d]", where, i), s)
+	}
+}
+
+func (v *validator) ref(where, ref string) {
+	var ok bool
+	c := v.spec.Components
+
+	switch {
+	case strings.HasPrefix(ref, schemaRefPrefix):
+		ok = c != nil && c.Schemas[strings.TrimPrefix(ref, schemaRefPrefix)] != nil
+	case strings.HasPrefix(ref, responseRefPrefix):
+		ok = c != nil && c.Responses[strings.TrimPrefix(ref, responseRefPrefix)] != nil
+	case strings.HasPrefix(ref, parameterRefPrefix):
+		ok = c != nil && c.Parameters[strings.TrimPrefix(ref, parameterRefPrefix)] != nil
+	case strings.HasPrefix(ref, requestBodyRefPrefix):
+		ok = c != nil && c.RequestBodies[strings.TrimPrefix(ref, requestBodyRefPrefix)] != nil
+	case strings.HasPrefix(ref, headerRefPrefix):
+		ok = c != nil && c.Headers[strings.TrimPrefix(ref, headerRefPrefix)] != nil
+	case strings.HasPrefix(ref, linkRefPrefix):
+		ok = c != nil && c.Links[strings.TrimPrefix(ref, linkRefPrefix)] != nil
+	default:
+		v.fail(where, "unsupported $ref %q", ref)
+		return
+	}
+
+	if !ok {
+		v.fail(where, "unresolved $ref %q", ref)
+	}
+}
+
+// PathTemplateParams returns the parameter names in a URL template, such as "id"
+// in /prompts/{id}. Wildcard suffixes are trimmed and {$} is ignored.
+func PathTemplateParams(path string) []string {
+	var names []string
+	for segment := range strings.SplitSeq(path, "/") {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+		name := strings.TrimSuffix(strings.Trim(segment, "{}"), "...")
+		if name != "$" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
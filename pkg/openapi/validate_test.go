@@ -0,0 +1,19 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateNilRequestBody(t *testing.T) {
+	spec := NewSpec("test", "0.1.0")
+	spec.Components.RequestBodies = map[string]*RequestBody{"Empty": nil}
+
+	err := spec.Validate()
+	if err == nil {
+		t.Fatal("Validate() error = nil, want nil request body reported")
+	}
+	if want := "components.requestBodies.Empty: request body is nil"; !strings.Contains(err.Error(), want) {
+		t.Errorf("Validate() error = %q, want it to contain %q", err, want)
+	}
+}
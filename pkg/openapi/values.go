@@ -0,0 +1,266 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sync"
+	"unicode/utf8"
+)
+
+// Violation describes a value that does not satisfy its schema.
+// Location identifies the value, such as query.page or body.config.name.
+type Violation struct {
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+// ValidateValue checks a decoded JSON value against schema and returns every
+// violation found. References are resolved into the spec's components.
+//
+// Values are expected in the form produced by encoding/json decoding into
+// an any, with json.Number or float64 for numbers. Properties marked
+// readOnly are not required, since the value is assumed to be a request.
+func (spec *Spec) ValidateValue(schema *Schema, value any, location string) []Violation {
+	c := &valueChecker{spec: spec}
+	c.check(schema, value, location)
+	return c.violations
+}
+
+var patterns sync.Map
+
+type valueChecker struct {
+	spec       *Spec
+	violations []Violation
+}
+
+func (c *valueChecker) fail(location, format string, args ...any) {
+	c.violations = append(c.violations, Violation{Location: location, Message: fmt.Sprintf(format, args...)})
+}
+
+// matches reports whether value satisfies schema without recording violations.
+func (c *valueChecker) matches(schema *Schema, value any, location string) bool {
+	sub := &valueChecker{spec: c.spec}
+	sub.check(schema, value, location)
+	return len(sub.violations) == 0
+}
+
+func (c *valueChecker) check(schema *Schema, value any, location string) {
+	if schema != nil && schema.Nullable && value == nil {
+		return
+	}
+	if schema != nil && schema.Ref != "" {
+		resolved := c.spec.ResolveSchema(schema)
+		if resolved == nil {
+			c.fail(location, "unresolved $ref %q", schema.Ref)
+			return
+		}
+		schema = resolved
+	}
+	if schema == nil {
+		return
+	}
+
+	if value == nil {
+		if schema.Type != "" && !schema.Nullable {
+			c.fail(location, "must be %s, not null", schema.Type)
+		}
+		return
+	}
+
+	if schema.Type != "" && !hasType(schema.Type, value) {
+		c.fail(location, "must be %s", schema.Type)
+		return
+	}
+
+	if len(schema.Enum) > 0 && !slices.ContainsFunc(schema.Enum, func(e any) bool { return equalValues(e, value) }) {
+		c.fail(location, "must be one of %v", schema.Enum)
+	}
+	if schema.Const != nil && !equalValues(schema.Const, value) {
+		c.fail(location, "must be %v", schema.Const)
+	}
+
+	switch v := value.(type) {
+	case string:
+		c.checkString(schema, v, location)
+	case json.Number, float64:
+		n, _ := toFloat(v)
+		c.checkNumber(schema, n, location)
+	case []any:
+		c.checkArray(schema, v, location)
+	case map[string]any:
+		c.checkObject(schema, v, location)
+	}
+
+	for _, sub := range schema.AllOf {
+		c.check(sub, value, location)
+	}
+	if len(schema.AnyOf) > 0 && !slices.ContainsFunc(schema.AnyOf, func(s *Schema) bool { return c.matches(s, value, location) }) {
+		c.fail(location, "must match at least one anyOf schema")
+	}
+	if len(schema.OneOf) > 0 {
+		matched := 0
+		for _, sub := range schema.OneOf {
+			if c.matches(sub, value, location) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			c.fail(location, "must match exactly one oneOf schema (matched %d)", matched)
+		}
+	}
+	if schema.Not != nil && c.matches(schema.Not, value, location) {
+		c.fail(location, "must not match the not schema")
+	}
+}
+
+func (c *valueChecker) checkString(schema *Schema, s, location string) {
+	length := utf8.RuneCountInString(s)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		c.fail(location, "must be at least %d characters", *schema.MinLength)
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		c.fail(location, "must be at most %d characters", *schema.MaxLength)
+	}
+	if schema.Pattern != "" {
+		re, err := compilePattern(schema.Pattern)
+		if err != nil {
+			c.fail(location, "invalid pattern %q: %v", schema.Pattern, err)
+		} else if !re.MatchString(s) {
+			c.fail(location, "must match pattern %q", schema.Pattern)
+		}
+	}
+}
+
+func (c *valueChecker) checkNumber(schema *Schema, n float64, location string) {
+	if schema.Minimum != nil && n < *schema.Minimum {
+		c.fail(location, "must be at least %v", *schema.Minimum)
+	}
+	if schema.Maximum != nil && n > *schema.Maximum {
+		c.fail(location, "must be at most %v", *schema.Maximum)
+	}
+	if schema.ExclusiveMinimum != nil && n <= *schema.ExclusiveMinimum {
+		c.fail(location, "must be greater than %v", *schema.ExclusiveMinimum)
+	}
+	if schema.ExclusiveMaximum != nil && n >= *schema.ExclusiveMaximum {
+		c.fail(location, "must be less than %v", *schema.ExclusiveMaximum)
+	}
+	if schema.MultipleOf != nil && *schema.MultipleOf > 0 {
+		if q := n / *schema.MultipleOf; q != math.Trunc(q) {
+			c.fail(location, "must be a multiple of %v", *schema.MultipleOf)
+		}
+	}
+}
+
+func (c *valueChecker) checkArray(schema *Schema, items []any, location string) {
+	if schema.MinItems != nil && len(items) < *schema.MinItems {
+		c.fail(location, "must contain at least %d items", *schema.MinItems)
+	}
+	if schema.MaxItems != nil && len(items) > *schema.MaxItems {
+		c.fail(location, "must contain at most %d items", *schema.MaxItems)
+	}
+	if schema.UniqueItems {
+		for i := 1; i < len(items); i++ {
+			if slices.ContainsFunc(items[:i], func(prev any) bool { return equalValues(prev, items[i]) }) {
+				c.fail(location, "must not contain duplicate items")
+				break
+			}
+		}
+	}
+	if schema.Items != nil {
+		for i, item := range items {
+			c.check(schema.Items, item, fmt.Sprintf("%s[%d]", location, i))
+		}
+	}
+}
+
+func (c *valueChecker) checkObject(schema *Schema, obj map[string]any, location string) {
+	for _, name := range schema.Required {
+		if _, ok := obj[name]; ok {
+			continue
+		}
+		if prop := c.spec.ResolveSchema(schema.Properties[name]); prop != nil && prop.ReadOnly {
+			continue
+		}
+		c.fail(location+"."+name, "is required")
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(obj)) {
+		where := location + "." + name
+		if prop, ok := schema.Properties[name]; ok {
+			c.check(prop, obj[name], where)
+			continue
+		}
+		switch {
+		case schema.NoAdditionalProperties:
+			c.fail(where, "is not a permitted property")
+		case schema.AdditionalProperties != nil:
+			c.check(schema.AdditionalProperties, obj[name], where)
+		}
+	}
+}
+
+func hasType(typ string, value any) bool {
+	switch typ {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	case "integer":
+		n, ok := toFloat(value)
+		return ok && n == math.Trunc(n) && !math.IsInf(n, 0)
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	}
+	return true
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// equalValues compares JSON values, treating numbers of any representation
+// as equal when they hold the same value.
+func equalValues(a, b any) bool {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
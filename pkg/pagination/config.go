@@ -1,15 +1,33 @@
 package pagination
 
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/JaimeStill/go-lit/pkg/envvar"
+)
+
 // Config holds pagination settings for controlling page size limits.
 type Config struct {
 	DefaultPageSize int `toml:"default_page_size"`
 	MaxPageSize     int `toml:"max_page_size"`
 }
 
-// Finalize applies default values to any unset configuration fields.
-func (c *Config) Finalize() error {
+// ConfigEnv maps environment variable names for pagination configuration.
+type ConfigEnv struct {
+	DefaultPageSize string
+	MaxPageSize     string
+}
+
+// Finalize applies defaults, loads environment variable overrides, and validates the configuration.
+func (c *Config) Finalize(env *ConfigEnv) error {
 	c.loadDefaults()
-	return nil
+	if env != nil {
+		if err := c.loadEnv(env); err != nil {
+			return err
+		}
+	}
+	return c.validate()
 }
 
 // Merge applies non-zero values from the overlay configuration.
@@ -31,3 +49,34 @@ func (c *Config) loadDefaults() {
 	}
 }
 
+func (c *Config) loadEnv(env *ConfigEnv) error {
+	var r envvar.Reader
+
+	if env.DefaultPageSize != "" {
+		if v := r.Get(env.DefaultPageSize); v != "" {
+			if size, err := strconv.Atoi(v); err == nil {
+				c.DefaultPageSize = size
+			}
+		}
+	}
+
+	if env.MaxPageSize != "" {
+		if v := r.Get(env.MaxPageSize); v != "" {
+			if size, err := strconv.Atoi(v); err == nil {
+				c.MaxPageSize = size
+			}
+		}
+	}
+
+	return r.Err()
+}
+
+func (c *Config) validate() error {
+	if c.DefaultPageSize < 1 {
+		return fmt.Errorf("invalid default_page_size: %d (must be at least 1)", c.DefaultPageSize)
+	}
+	if c.MaxPageSize < c.DefaultPageSize {
+		return fmt.Errorf("invalid max_page_size: %d (must be at least default_page_size %d)", c.MaxPageSize, c.DefaultPageSize)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package pagination
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidFilter is wrapped by the errors ParseFilters returns.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// FilterType is the type a filter value is parsed as.
+type FilterType string
+
+const (
+	FilterString FilterType = "string"
+	FilterInt    FilterType = "integer"
+	FilterBool   FilterType = "boolean"
+	// FilterTime accepts RFC 3339 timestamps and dates such as 2024-01-01,
+	// which are read as midnight UTC.
+	FilterTime FilterType = "time"
+)
+
+// Operator compares a field with a filter value.
+type Operator string
+
+const (
+	OpEq       Operator = "eq"
+	OpNe       Operator = "ne"
+	OpGt       Operator = "gt"
+	OpGte      Operator = "gte"
+	OpLt       Operator = "lt"
+	OpLte      Operator = "lte"
+	OpContains Operator = "contains"
+)
+
+// FilterSpec declares a field that may be filtered, the type its values are
+// parsed as, and the operators it accepts. A spec without operators accepts
+// only OpEq. OpContains applies only to FilterString fields.
+type FilterSpec struct {
+	Field     string
+	Type      FilterType
+	Operators []Operator
+}
+
+func (s FilterSpec) allows(op Operator) bool {
+	if len(s.Operators) == 0 {
+		return op == OpEq
+	}
+	return slices.Contains(s.Operators, op)
+}
+
+// Filter is a parsed filter condition. Value holds a string, int, bool, or
+// time.Time according to the spec's FilterType.
+type Filter struct {
+	Field string
+	Op    Operator
+	Value any
+}
+
+// Filters are conditions that must all hold.
+type Filters []Filter
+
+// FilterError reports every filter parameter that failed to parse, keyed by
+// the query parameter, such as "created_at[gte]", with the reason.
+type FilterError struct {
+	Params map[string]string
+}
+
+func (e *FilterError) Error() string {
+	parts := make([]string, 0, len(e.Params))
+	for _, param := range slices.Sorted(maps.Keys(e.Params)) {
+		parts = append(parts, param+": "+e.Params[param])
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidFilter, strings.Join(parts, "; "))
+}
+
+func (e *FilterError) Unwrap() error {
+	return ErrInvalidFilter
+}
+
+// ParseFilters reads the filters declared by specs from query. A parameter
+// named for a field, such as status=active, compares with OpEq, and a
+// bracketed operator selects another, such as created_at[gte]=2024-01-01.
+// Parameters that name no declared field are left to the caller, except
+// bracketed ones, which are reported as unknown. Each value of a repeated
+// parameter becomes its own filter. All invalid parameters are reported
+// together in a *FilterError.
+func ParseFilters(query url.Values, specs ...FilterSpec) (Filters, error) {
+	var filters Filters
+	invalid := make(map[string]string)
+
+	for _, param := range slices.Sorted(maps.Keys(query)) {
+		field, op, bracketed := parseFilterParam(param)
+		i := slices.IndexFunc(specs, func(s FilterSpec) bool { return s.Field == field })
+		if i < 0 {
+			if bracketed {
+				invalid[param] = "unknown field"
+			}
+			continue
+		}
+		spec := specs[i]
+
+		if !spec.allows(op) || (op == OpContains && spec.Type != FilterString) {
+			invalid[param] = fmt.Sprintf("operator %q not supported", op)
+			continue
+		}
+
+		for _, raw := range query[param] {
+			value, err := parseFilterValue(spec.Type, raw)
+			if err != nil {
+				invalid[param] = err.Error()
+				break
+			}
+			filters = append(filters, Filter{Field: field, Op: op, Value: value})
+		}
+	}
+
+	if len(invalid) > 0 {
+		return nil, &FilterError{Params: invalid}
+	}
+	return filters, nil
+}
+
+func parseFilterParam(param string) (field string, op Operator, bracketed bool) {
+	name, rest, ok := strings.Cut(param, "[")
+	if !ok || !strings.HasSuffix(rest, "]") {
+		return param, OpEq, false
+	}
+	return name, Operator(strings.TrimSuffix(rest, "]")), true
+}
+
+func parseFilterValue(typ FilterType, raw string) (any, error) {
+	switch typ {
+	case FilterInt:
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", raw)
+		}
+		return v, nil
+	case FilterBool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", raw)
+		}
+		return v, nil
+	case FilterTime:
+		if v, err := time.Parse(time.RFC3339, raw); err == nil {
+			return v, nil
+		}
+		v, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an RFC 3339 time or date", raw)
+		}
+		return v, nil
+	default:
+		return raw, nil
+	}
+}
+
+// Match reports whether value satisfies f. Value must have the type f.Value
+// was parsed as; a value of another type never matches.
+func (f Filter) Match(value any) bool {
+	var c int
+	switch want := f.Value.(type) {
+	case string:
+		got, ok := value.(string)
+		if !ok {
+			return false
+		}
+		if f.Op == OpContains {
+			return strings.Contains(strings.ToLower(got), strings.ToLower(want))
+		}
+		c = cmp.Compare(got, want)
+	case int:
+		got, ok := value.(int)
+		if !ok {
+			return false
+		}
+		c = cmp.Compare(got, want)
+	case bool:
+		got, ok := value.(bool)
+		if !ok {
+			return false
+		}
+		switch f.Op {
+		case OpEq:
+			return got == want
+		case OpNe:
+			return got != want
+		}
+		return false
+	case time.Time:
+		got, ok := value.(time.Time)
+		if !ok {
+			return false
+		}
+		c = got.Compare(want)
+	default:
+		return false
+	}
+
+	switch f.Op {
+	case OpEq:
+		return c == 0
+	case OpNe:
+		return c != 0
+	case OpGt:
+		return c > 0
+	case OpGte:
+		return c >= 0
+	case OpLt:
+		return c < 0
+	case OpLte:
+		return c <= 0
+	}
+	return false
+}
+
+// ApplyFilters returns the items matching every filter, for services that
+// filter in memory. fields maps each filterable field to a function reading
+// it from an item, returning the type its FilterSpec declares. An item never
+// matches a filter on a field missing from fields.
+func ApplyFilters[T any](items []T, filters Filters, fields map[string]func(T) any) []T {
+	if len(filters) == 0 {
+		return items
+	}
+	matches := make([]T, 0, len(items))
+	for _, item := range items {
+		if matchAll(item, filters, fields) {
+			matches = append(matches, item)
+		}
+	}
+	return matches
+}
+
+func matchAll[T any](item T, filters Filters, fields map[string]func(T) any) bool {
+	for _, f := range filters {
+		get, ok := fields[f.Field]
+		if !ok || !f.Match(get(item)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package pagination
+
+import (
+	"errors"
+	"maps"
+	"net/url"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseFilters(t *testing.T) {
+	specs := []FilterSpec{
+		{Field: "status", Type: FilterString},
+		{Field: "name", Type: FilterString, Operators: []Operator{OpEq, OpContains}},
+		{Field: "count", Type: FilterInt, Operators: []Operator{OpEq, OpGt, OpLte}},
+		{Field: "active", Type: FilterBool},
+		{Field: "created_at", Type: FilterTime, Operators: []Operator{OpGte, OpLt, OpContains}},
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		want        Filters
+		wantInvalid []string
+	}{
+		{name: "none", query: ""},
+		{name: "undeclared plain parameter ignored", query: "page=2"},
+		{name: "equality", query: "status=active", want: Filters{{Field: "status", Op: OpEq, Value: "active"}}},
+		{name: "operator", query: "count[gt]=5", want: Filters{{Field: "count", Op: OpGt, Value: 5}}},
+		{name: "contains", query: "name[contains]=bot", want: Filters{{Field: "name", Op: OpContains, Value: "bot"}}},
+		{name: "boolean", query: "active=true", want: Filters{{Field: "active", Op: OpEq, Value: true}}},
+		{
+			name:  "date",
+			query: "created_at[gte]=2024-01-01",
+			want:  Filters{{Field: "created_at", Op: OpGte, Value: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		},
+		{
+			name:  "repeated",
+			query: "status=a&status=b",
+			want:  Filters{{Field: "status", Op: OpEq, Value: "a"}, {Field: "status", Op: OpEq, Value: "b"}},
+		},
+		{name: "default operators are eq only", query: "status[ne]=a", wantInvalid: []string{"status[ne]"}},
+		{name: "operator not declared", query: "count[gte]=5", wantInvalid: []string{"count[gte]"}},
+		{name: "contains on non-string", query: "created_at[contains]=2024", wantInvalid: []string{"created_at[contains]"}},
+		{name: "unknown bracketed field", query: "email[eq]=x", wantInvalid: []string{"email[eq]"}},
+		{name: "bad integer", query: "count=many", wantInvalid: []string{"count"}},
+		{name: "bad boolean", query: "active=maybe", wantInvalid: []string{"active"}},
+		{name: "bad time", query: "created_at[lt]=yesterday", wantInvalid: []string{"created_at[lt]"}},
+		{
+			name:        "every invalid parameter reported",
+			query:       "count=many&active=maybe&status=ok",
+			wantInvalid: []string{"active", "count"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ParseFilters(query, specs...)
+			if len(tt.wantInvalid) > 0 {
+				var ferr *FilterError
+				if !errors.As(err, &ferr) || !errors.Is(err, ErrInvalidFilter) {
+					t.Fatalf("ParseFilters() error = %v, want a *FilterError", err)
+				}
+				params := slices.Sorted(maps.Keys(ferr.Params))
+				if !slices.Equal(params, tt.wantInvalid) {
+					t.Errorf("invalid params = %q, want %q", params, tt.wantInvalid)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFilters() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFilters() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrInvalidKeyset is wrapped by the errors KeysetFromQuery and ParseCursor
+// return.
+var ErrInvalidKeyset = errors.New("invalid keyset")
+
+// Keyset bounds a page of a feed ordered by (time, id) descending. Before
+// selects items older than its key, for the next page, and After selects
+// items newer than its key, for the previous page or for polling. The ID
+// breaks ties between items sharing a timestamp; a bound without an ID
+// compares on time alone, excluding every item at that time. Zero times
+// leave that side unbounded.
+type Keyset struct {
+	Before   time.Time `json:"before,omitzero"`
+	BeforeID string    `json:"before_id,omitempty"`
+	After    time.Time `json:"after,omitzero"`
+	AfterID  string    `json:"after_id,omitempty"`
+}
+
+// KeysetFromQuery reads a Keyset from the before, before_id, after, and
+// after_id parameters, with times in RFC 3339, or from an opaque cursor
+// parameter produced by Keyset.Cursor. A cursor takes precedence over the
+// individual parameters.
+func KeysetFromQuery(query url.Values) (Keyset, error) {
+	if cursor := query.Get("cursor"); cursor != "" {
+		return ParseCursor(cursor)
+	}
+
+	var k Keyset
+	for param, field := range map[string]*time.Time{"before": &k.Before, "after": &k.After} {
+		raw := query.Get(param)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return Keyset{}, fmt.Errorf("%w: %s: %q is not an RFC 3339 time", ErrInvalidKeyset, param, raw)
+		}
+		*field = t
+	}
+	k.BeforeID = query.Get("before_id")
+	k.AfterID = query.Get("after_id")
+
+	if err := k.validate(); err != nil {
+		return Keyset{}, err
+	}
+	return k, nil
+}
+
+func (k Keyset) validate() error {
+	if k.BeforeID != "" && k.Before.IsZero() {
+		return fmt.Errorf("%w: before_id requires before", ErrInvalidKeyset)
+	}
+	if k.AfterID != "" && k.After.IsZero() {
+		return fmt.Errorf("%w: after_id requires after", ErrInvalidKeyset)
+	}
+	return nil
+}
+
+// Query returns the parameters KeysetFromQuery reads back as k, omitting
+// unset bounds.
+func (k Keyset) Query() url.Values {
+	query := url.Values{}
+	if !k.Before.IsZero() {
+		query.Set("before", k.Before.Format(time.RFC3339Nano))
+		if k.BeforeID != "" {
+			query.Set("before_id", k.BeforeID)
+		}
+	}
+	if !k.After.IsZero() {
+		query.Set("after", k.After.Format(time.RFC3339Nano))
+		if k.AfterID != "" {
+			query.Set("after_id", k.AfterID)
+		}
+	}
+	return query
+}
+
+// Cursor encodes k as an opaque, URL-safe string for clients that should not
+// depend on the keys, read back by ParseCursor. An unbounded keyset encodes
+// as an empty string.
+func (k Keyset) Cursor() string {
+	if k.IsZero() {
+		return ""
+	}
+	data, _ := json.Marshal(k)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseCursor decodes a cursor produced by Keyset.Cursor.
+func ParseCursor(cursor string) (Keyset, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return Keyset{}, fmt.Errorf("%w: malformed cursor", ErrInvalidKeyset)
+	}
+	var k Keyset
+	if err := json.Unmarshal(data, &k); err != nil {
+		return Keyset{}, fmt.Errorf("%w: malformed cursor", ErrInvalidKeyset)
+	}
+	if err := k.validate(); err != nil {
+		return Keyset{}, err
+	}
+	return k, nil
+}
+
+// IsZero reports whether k leaves both sides unbounded.
+func (k Keyset) IsZero() bool {
+	return k.Before.IsZero() && k.After.IsZero()
+}
+
+// SQL returns a WHERE condition selecting the rows within k, with ?
+// placeholders, and its arguments in order. timeColumn and idColumn are
+// written into the condition as given, so they must be trusted column names
+// rather than client input. An unbounded keyset returns an empty condition.
+// The caller orders the rows by timeColumn DESC, idColumn DESC.
+func (k Keyset) SQL(timeColumn, idColumn string) (string, []any) {
+	var conds []string
+	var args []any
+
+	bound := func(t time.Time, id, op string) {
+		if id == "" {
+			conds = append(conds, fmt.Sprintf("%s %s ?", timeColumn, op))
+			args = append(args, t)
+			return
+		}
+		conds = append(conds, fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))", timeColumn, op, timeColumn, idColumn, op))
+		args = append(args, t, t, id)
+	}
+	if !k.Before.IsZero() {
+		bound(k.Before, k.BeforeID, "<")
+	}
+	if !k.After.IsZero() {
+		bound(k.After, k.AfterID, ">")
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// Key is the (time, id) position of an item in a keyset-ordered feed.
+type Key struct {
+	Time time.Time `json:"time"`
+	ID   string    `json:"id"`
+}
+
+// KeysetResult wraps a page of a feed ordered by (time, id) descending with
+// the keys of its first (newest) and last (oldest) items, which the client
+// sends back for the adjacent pages. Both are nil for an empty page.
+type KeysetResult[T any] struct {
+	Data  []T  `json:"data"`
+	First *Key `json:"first,omitempty"`
+	Last  *Key `json:"last,omitempty"`
+}
+
+// NewKeysetResult wraps data, already ordered by (time, id) descending,
+// reading the boundary keys from its first and last items with key.
+func NewKeysetResult[T any](data []T, key func(T) Key) KeysetResult[T] {
+	if len(data) == 0 {
+		return KeysetResult[T]{Data: []T{}}
+	}
+	first, last := key(data[0]), key(data[len(data)-1])
+	return KeysetResult[T]{Data: data, First: &first, Last: &last}
+}
+
+// Next returns the keyset selecting the page after r, older than its last
+// item. It is unbounded for an empty page.
+func (r KeysetResult[T]) Next() Keyset {
+	if r.Last == nil {
+		return Keyset{}
+	}
+	return Keyset{Before: r.Last.Time, BeforeID: r.Last.ID}
+}
+
+// Prev returns the keyset selecting the items newer than r's first item,
+// for the previous page or for polling. It is unbounded for an empty page.
+func (r KeysetResult[T]) Prev() Keyset {
+	if r.First == nil {
+		return Keyset{}
+	}
+	return Keyset{After: r.First.Time, AfterID: r.First.ID}
+}
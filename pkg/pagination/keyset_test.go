@@ -0,0 +1,79 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC)
+
+	tests := []struct {
+		name   string
+		keyset Keyset
+	}{
+		{name: "before", keyset: Keyset{Before: at}},
+		{name: "before with id", keyset: Keyset{Before: at, BeforeID: "item-7"}},
+		{name: "after with id", keyset: Keyset{After: at, AfterID: "item-3"}},
+		{name: "both sides", keyset: Keyset{Before: at, BeforeID: "b", After: at.Add(-time.Hour), AfterID: "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := tt.keyset.Cursor()
+			if cursor == "" {
+				t.Fatal("Cursor() = \"\", want an encoded keyset")
+			}
+
+			got, err := ParseCursor(cursor)
+			if err != nil {
+				t.Fatalf("ParseCursor() error = %v", err)
+			}
+			if !got.Before.Equal(tt.keyset.Before) || got.BeforeID != tt.keyset.BeforeID ||
+				!got.After.Equal(tt.keyset.After) || got.AfterID != tt.keyset.AfterID {
+				t.Errorf("ParseCursor() = %+v, want %+v", got, tt.keyset)
+			}
+
+			fromQuery, err := KeysetFromQuery(url.Values{"cursor": {cursor}, "before": {"not a time"}})
+			if err != nil {
+				t.Fatalf("KeysetFromQuery() error = %v, want the cursor to take precedence", err)
+			}
+			if !fromQuery.Before.Equal(tt.keyset.Before) || fromQuery.BeforeID != tt.keyset.BeforeID {
+				t.Errorf("KeysetFromQuery() = %+v, want %+v", fromQuery, tt.keyset)
+			}
+		})
+	}
+
+	if got := (Keyset{}).Cursor(); got != "" {
+		t.Errorf("unbounded Cursor() = %q, want \"\"", got)
+	}
+}
+
+func TestParseCursorInvalid(t *testing.T) {
+	valid := Keyset{Before: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), BeforeID: "x"}.Cursor()
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "!!!"},
+		{name: "padded standard encoding", cursor: base64.StdEncoding.EncodeToString([]byte(`{"before":"2024-03-01T00:00:00Z"}`)) + "="},
+		{name: "not json", cursor: base64.RawURLEncoding.EncodeToString([]byte("not json"))},
+		{name: "bad time", cursor: base64.RawURLEncoding.EncodeToString([]byte(`{"before":"yesterday"}`))},
+		{name: "id without time", cursor: base64.RawURLEncoding.EncodeToString([]byte(`{"before_id":"x"}`))},
+		{name: "tampered", cursor: valid[:len(valid)-2] + "!!"},
+		{name: "truncated", cursor: valid[:len(valid)/2]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCursor(tt.cursor)
+			if !errors.Is(err, ErrInvalidKeyset) {
+				t.Errorf("ParseCursor(%q) error = %v, want ErrInvalidKeyset", tt.cursor, err)
+			}
+		})
+	}
+}
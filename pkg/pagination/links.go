@@ -0,0 +1,70 @@
+package pagination
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Links holds the URLs of the pages around a PageResult. Prev and Next are
+// empty on the first and last pages.
+type Links struct {
+	First string `json:"first"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last"`
+}
+
+// Links returns the first, previous, next, and last page URLs relative to
+// base, replacing its page and page_size parameters with those encoded by
+// PageRequest.Query while preserving others such as search and sort. A
+// result with no items has a single page, so first and last both point at
+// page 1.
+func (r PageResult[T]) Links(base *url.URL) Links {
+	last := max(r.TotalPages, 1)
+	links := Links{
+		First: r.pageURL(base, 1),
+		Last:  r.pageURL(base, last),
+	}
+	if r.Page > 1 {
+		links.Prev = r.pageURL(base, min(r.Page-1, last))
+	}
+	if r.Page < last {
+		links.Next = r.pageURL(base, r.Page+1)
+	}
+	return links
+}
+
+// WriteLinkHeader sets an RFC 8288 Link header with the page URLs from
+// Links, and X-Total-Count with the total number of items.
+func (r PageResult[T]) WriteLinkHeader(w http.ResponseWriter, base *url.URL) {
+	links := r.Links(base)
+
+	var values []string
+	for _, link := range []struct{ rel, href string }{
+		{"first", links.First},
+		{"prev", links.Prev},
+		{"next", links.Next},
+		{"last", links.Last},
+	} {
+		if link.href != "" {
+			values = append(values, fmt.Sprintf(`<%s>; rel="%s"`, link.href, link.rel))
+		}
+	}
+
+	w.Header().Set("Link", strings.Join(values, ", "))
+	w.Header().Set("X-Total-Count", strconv.Itoa(r.Total))
+}
+
+func (r PageResult[T]) pageURL(base *url.URL, page int) string {
+	u := *base
+	query := u.Query()
+	query.Del("page")
+	query.Del("page_size")
+	maps.Copy(query, PageRequest{Page: page, PageSize: r.PageSize}.Query())
+	u.RawQuery = query.Encode()
+	return u.String()
+}
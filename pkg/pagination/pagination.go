@@ -24,6 +24,42 @@ type PageResult[T any] struct {
 	TotalPages int `json:"total_pages"`
 }
 
+// NewPageResult wraps a page of data with metadata for req, where total is
+// the number of items across all pages. A total of 0 has 0 pages, and a
+// request past the last page reports the last page, so the metadata and
+// links always describe a page that exists. A PageSize below 1 is treated as
+// a single page holding every item.
+func NewPageResult[T any](data []T, total int, req PageRequest) PageResult[T] {
+	pageSize := req.PageSize
+	if pageSize < 1 {
+		pageSize = max(total, 1)
+	}
+	totalPages := (total + pageSize - 1) / pageSize
+	if data == nil {
+		data = []T{}
+	}
+	return PageResult[T]{
+		Data:       data,
+		Total:      total,
+		Page:       min(max(req.Page, 1), max(totalPages, 1)),
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}
+}
+
+// ApplyPage returns the page of items selected by req, for collections held
+// in memory. Items must already be filtered and sorted. As with
+// NewPageResult, a request past the last page returns the last page.
+func ApplyPage[T any](items []T, req PageRequest) PageResult[T] {
+	result := NewPageResult[T](nil, len(items), req)
+	start := min((result.Page-1)*result.PageSize, len(items))
+	end := min(start+result.PageSize, len(items))
+	if end > start {
+		result.Data = items[start:end]
+	}
+	return result
+}
+
 // PageRequestFromQuery extracts pagination parameters from URL query values.
 // It applies the configuration limits to ensure valid page sizes.
 func PageRequestFromQuery(query url.Values, cfg Config) PageRequest {
@@ -54,11 +90,41 @@ func (p *PageRequest) Normalize(cfg Config) {
 	}
 }
 
+// Query returns the query parameters that PageRequestFromQuery reads back
+// as p, omitting zero values and a page of 1, which are the defaults.
+func (p PageRequest) Query() url.Values {
+	query := url.Values{}
+	if p.Page > 1 {
+		query.Set("page", strconv.Itoa(p.Page))
+	}
+	if p.PageSize > 0 {
+		query.Set("page_size", strconv.Itoa(p.PageSize))
+	}
+	if p.Search != "" {
+		query.Set("search", p.Search)
+	}
+	if p.Sort != "" {
+		query.Set("sort", p.Sort)
+	}
+	return query
+}
+
+// Encode returns Query as a URL-encoded query string in key order, such as
+// "page=2&page_size=50&search=go+lit".
+func (p PageRequest) Encode() string {
+	return p.Query().Encode()
+}
+
 // Offset returns the zero-based offset for database queries.
 func (p *PageRequest) Offset() int {
 	return (p.Page - 1) * p.PageSize
 }
 
+// Sorts parses Sort with ParseSort, accepting only the allowed fields.
+func (p *PageRequest) Sorts(allowed ...string) ([]SortField, error) {
+	return ParseSort(p.Sort, allowed...)
+}
+
 // UnmarshalJSON provides flexible JSON parsing for PageRequest.
 // It handles both "page_size" and "pageSize" field names.
 func (p *PageRequest) UnmarshalJSON(data []byte) error {
@@ -0,0 +1,78 @@
+package pagination
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNewPageResult(t *testing.T) {
+	tests := []struct {
+		name           string
+		total          int
+		req            PageRequest
+		wantPage       int
+		wantPageSize   int
+		wantTotalPages int
+	}{
+		{name: "empty", total: 0, req: PageRequest{Page: 1, PageSize: 10}, wantPage: 1, wantPageSize: 10, wantTotalPages: 0},
+		{name: "empty past the end", total: 0, req: PageRequest{Page: 3, PageSize: 10}, wantPage: 1, wantPageSize: 10, wantTotalPages: 0},
+		{name: "single partial page", total: 3, req: PageRequest{Page: 1, PageSize: 10}, wantPage: 1, wantPageSize: 10, wantTotalPages: 1},
+		{name: "exact multiple", total: 20, req: PageRequest{Page: 2, PageSize: 10}, wantPage: 2, wantPageSize: 10, wantTotalPages: 2},
+		{name: "one past a multiple", total: 21, req: PageRequest{Page: 3, PageSize: 10}, wantPage: 3, wantPageSize: 10, wantTotalPages: 3},
+		{name: "past the last page", total: 21, req: PageRequest{Page: 9, PageSize: 10}, wantPage: 3, wantPageSize: 10, wantTotalPages: 3},
+		{name: "page below 1", total: 21, req: PageRequest{Page: 0, PageSize: 10}, wantPage: 1, wantPageSize: 10, wantTotalPages: 3},
+		{name: "no page size", total: 7, req: PageRequest{Page: 1}, wantPage: 1, wantPageSize: 7, wantTotalPages: 1},
+		{name: "no page size and empty", total: 0, req: PageRequest{Page: 1}, wantPage: 1, wantPageSize: 1, wantTotalPages: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewPageResult[int](nil, tt.total, tt.req)
+			if got.Page != tt.wantPage || got.PageSize != tt.wantPageSize || got.TotalPages != tt.wantTotalPages {
+				t.Errorf("page %d, size %d, pages %d; want page %d, size %d, pages %d",
+					got.Page, got.PageSize, got.TotalPages, tt.wantPage, tt.wantPageSize, tt.wantTotalPages)
+			}
+			if got.Total != tt.total {
+				t.Errorf("Total = %d, want %d", got.Total, tt.total)
+			}
+			if got.Data == nil {
+				t.Error("Data is nil, want an empty slice so it encodes as []")
+			}
+		})
+	}
+}
+
+func TestApplyPage(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7}
+
+	tests := []struct {
+		name     string
+		items    []int
+		req      PageRequest
+		want     []int
+		wantPage int
+	}{
+		{name: "first page", items: items, req: PageRequest{Page: 1, PageSize: 3}, want: []int{1, 2, 3}, wantPage: 1},
+		{name: "middle page", items: items, req: PageRequest{Page: 2, PageSize: 3}, want: []int{4, 5, 6}, wantPage: 2},
+		{name: "partial last page", items: items, req: PageRequest{Page: 3, PageSize: 3}, want: []int{7}, wantPage: 3},
+		{name: "offset past the end", items: items, req: PageRequest{Page: 10, PageSize: 3}, want: []int{7}, wantPage: 3},
+		{name: "page larger than items", items: items, req: PageRequest{Page: 1, PageSize: 50}, want: items, wantPage: 1},
+		{name: "no page size", items: items, req: PageRequest{Page: 1}, want: items, wantPage: 1},
+		{name: "empty", items: nil, req: PageRequest{Page: 2, PageSize: 3}, want: []int{}, wantPage: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ApplyPage(tt.items, tt.req)
+			if !slices.Equal(got.Data, tt.want) || got.Data == nil {
+				t.Errorf("Data = %v, want %v", got.Data, tt.want)
+			}
+			if got.Page != tt.wantPage {
+				t.Errorf("Page = %d, want %d", got.Page, tt.wantPage)
+			}
+			if got.Total != len(tt.items) {
+				t.Errorf("Total = %d, want %d", got.Total, len(tt.items))
+			}
+		})
+	}
+}
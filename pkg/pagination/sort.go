@@ -0,0 +1,59 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidSort is wrapped by the errors ParseSort returns.
+var ErrInvalidSort = errors.New("invalid sort")
+
+// SortField is a single field of a sort expression.
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// ParseSort parses a comma-separated sort expression such as
+// "-created_at,name", where a - prefix sorts the field in descending order.
+// Every field must be one of allowed and may appear only once, so with no
+// allowed fields any non-empty expression is rejected. An empty expression
+// returns no fields.
+func ParseSort(s string, allowed ...string) ([]SortField, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var fields []SortField
+	for part := range strings.SplitSeq(s, ",") {
+		part = strings.TrimSpace(part)
+		name, desc := strings.CutPrefix(part, "-")
+		switch {
+		case name == "":
+			return nil, fmt.Errorf("%w: empty field in %q", ErrInvalidSort, s)
+		case !slices.Contains(allowed, name):
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidSort, name)
+		case slices.ContainsFunc(fields, func(f SortField) bool { return f.Field == name }):
+			return nil, fmt.Errorf("%w: duplicate field %q", ErrInvalidSort, name)
+		}
+		fields = append(fields, SortField{Field: name, Descending: desc})
+	}
+	return fields, nil
+}
+
+// SQL returns the ORDER BY term for f, such as "created_at DESC", using the
+// column columnMap maps the field to. Only mapped column names reach the
+// query, so a field without a column is an error rather than being quoted
+// into SQL.
+func (f SortField) SQL(columnMap map[string]string) (string, error) {
+	column, ok := columnMap[f.Field]
+	if !ok || column == "" {
+		return "", fmt.Errorf("%w: no column for field %q", ErrInvalidSort, f.Field)
+	}
+	if f.Descending {
+		return column + " DESC", nil
+	}
+	return column + " ASC", nil
+}
@@ -0,0 +1,81 @@
+package pagination
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	allowed := []string{"name", "created_at"}
+
+	tests := []struct {
+		name    string
+		expr    string
+		allowed []string
+		want    []SortField
+		wantErr bool
+	}{
+		{name: "empty", expr: "", allowed: allowed},
+		{name: "blank", expr: "  ", allowed: allowed},
+		{name: "ascending", expr: "name", allowed: allowed, want: []SortField{{Field: "name"}}},
+		{name: "descending", expr: "-created_at", allowed: allowed, want: []SortField{{Field: "created_at", Descending: true}}},
+		{
+			name:    "several",
+			expr:    "-created_at, name",
+			allowed: allowed,
+			want:    []SortField{{Field: "created_at", Descending: true}, {Field: "name"}},
+		},
+		{name: "unknown field", expr: "email", allowed: allowed, wantErr: true},
+		{name: "unknown descending field", expr: "-email", allowed: allowed, wantErr: true},
+		{name: "no allowed fields", expr: "name", wantErr: true},
+		{name: "duplicate field", expr: "name,-name", allowed: allowed, wantErr: true},
+		{name: "bare dash", expr: "-", allowed: allowed, wantErr: true},
+		{name: "empty field", expr: "name,,created_at", allowed: allowed, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSort(tt.expr, tt.allowed...)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSort) {
+					t.Errorf("ParseSort(%q) error = %v, want ErrInvalidSort", tt.expr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSort(%q) error = %v", tt.expr, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseSort(%q) = %+v, want %+v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortFieldSQL(t *testing.T) {
+	columns := map[string]string{"created_at": "p.created_at"}
+
+	tests := []struct {
+		name    string
+		field   SortField
+		want    string
+		wantErr bool
+	}{
+		{name: "ascending", field: SortField{Field: "created_at"}, want: "p.created_at ASC"},
+		{name: "descending", field: SortField{Field: "created_at", Descending: true}, want: "p.created_at DESC"},
+		{name: "unmapped", field: SortField{Field: "name"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.field.SQL(columns)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SQL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("SQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
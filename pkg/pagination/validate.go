@@ -0,0 +1,124 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPageRequest is wrapped by the errors Validate and
+// PageRequestFromQueryStrict return.
+var ErrInvalidPageRequest = errors.New("invalid page request")
+
+// PageRequestError reports every page parameter that failed validation,
+// keyed by the query parameter, such as "page_size", with the reason.
+type PageRequestError struct {
+	Params map[string]string
+}
+
+func (e *PageRequestError) Error() string {
+	parts := make([]string, 0, len(e.Params))
+	for _, param := range slices.Sorted(maps.Keys(e.Params)) {
+		parts = append(parts, param+": "+e.Params[param])
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidPageRequest, strings.Join(parts, "; "))
+}
+
+func (e *PageRequestError) Unwrap() error {
+	return ErrInvalidPageRequest
+}
+
+// PageRequestFromQueryStrict is PageRequestFromQuery for handlers that
+// reject bad input rather than correct it. Absent page and page_size take
+// their defaults, but values that are not integers or that Validate rejects
+// are reported together in a *PageRequestError.
+func PageRequestFromQueryStrict(query url.Values, cfg Config) (PageRequest, error) {
+	req := PageRequest{
+		Page:     1,
+		PageSize: cfg.DefaultPageSize,
+		Search:   query.Get("search"),
+		Sort:     query.Get("sort"),
+	}
+	invalid := make(map[string]string)
+
+	for param, field := range map[string]*int{"page": &req.Page, "page_size": &req.PageSize} {
+		if !query.Has(param) {
+			continue
+		}
+		raw := query.Get(param)
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			invalid[param] = fmt.Sprintf("%q is not an integer", raw)
+			continue
+		}
+		*field = v
+	}
+
+	var perr *PageRequestError
+	if errors.As(req.Validate(cfg), &perr) {
+		for param, reason := range perr.Params {
+			if _, ok := invalid[param]; !ok {
+				invalid[param] = reason
+			}
+		}
+	}
+
+	if len(invalid) > 0 {
+		return req, &PageRequestError{Params: invalid}
+	}
+	return req, nil
+}
+
+// Validate reports the fields of p that Normalize would otherwise correct:
+// a page below 1, a page size below 1 or over the configured maximum, and
+// sort fields written with a direction other than the - prefix, such as
+// "+name" or "name desc". Sort field names are checked against an allow-list
+// by ParseSort, not here.
+func (p *PageRequest) Validate(cfg Config) error {
+	invalid := make(map[string]string)
+
+	if p.Page < 1 {
+		invalid["page"] = "must be at least 1"
+	}
+	switch {
+	case p.PageSize < 1:
+		invalid["page_size"] = "must be at least 1"
+	case cfg.MaxPageSize > 0 && p.PageSize > cfg.MaxPageSize:
+		invalid["page_size"] = fmt.Sprintf("must be at most %d", cfg.MaxPageSize)
+	}
+	if p.Sort != "" {
+		for part := range strings.SplitSeq(p.Sort, ",") {
+			if !validSortTerm(strings.TrimSpace(part)) {
+				invalid["sort"] = fmt.Sprintf("unknown sort direction in %q; prefix a field with - for descending", part)
+				break
+			}
+		}
+	}
+
+	if len(invalid) > 0 {
+		return &PageRequestError{Params: invalid}
+	}
+	return nil
+}
+
+// validSortTerm reports whether term is a field name with an optional -
+// prefix.
+func validSortTerm(term string) bool {
+	name := strings.TrimPrefix(term, "-")
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r == '.' || r >= '0' && r <= '9'):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,171 @@
+// Package registry provides a minimal typed service registry for sharing
+// services between modules during construction. Services are keyed by their
+// static type; there is no autowiring.
+//
+// A service provided through a Consumer view is owned by that consumer, and
+// resolving it from another consumer makes the resolver depend on the owner.
+// Start turns those dependencies into lifecycle startup hooks, so each
+// consumer starts after the consumers whose services it uses and a
+// dependency cycle fails boot.
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/JaimeStill/go-lit/pkg/lifecycle"
+)
+
+// Registry holds provided services and records failed resolutions.
+// Views returned by Consumer share the same services and failure record,
+// and attribute their resolutions to the named consumer.
+type Registry struct {
+	state    *state
+	consumer string
+}
+
+type state struct {
+	mu         sync.Mutex
+	services   map[reflect.Type]any
+	owners     map[reflect.Type]string
+	unresolved []error
+	consumers  []string
+	deps       map[string][]string
+	starts     map[string][]func(ctx context.Context) error
+}
+
+// New creates an empty Registry.
+func New() *Registry {
+	return &Registry{
+		state: &state{
+			services: make(map[reflect.Type]any),
+			owners:   make(map[reflect.Type]string),
+			deps:     make(map[string][]string),
+			starts:   make(map[string][]func(ctx context.Context) error),
+		},
+	}
+}
+
+// Consumer returns a view of the registry that attributes resolutions to
+// the named consumer, so missing dependencies can be reported by name.
+// Services provided through the view are owned by the consumer.
+func (r *Registry) Consumer(name string) *Registry {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	if !slices.Contains(r.state.consumers, name) {
+		r.state.consumers = append(r.state.consumers, name)
+	}
+	return &Registry{state: r.state, consumer: name}
+}
+
+// Verify returns an error describing every failed resolution, or nil if all
+// resolutions were satisfied. Call it after all modules are constructed.
+func (r *Registry) Verify() error {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	return errors.Join(r.state.unresolved...)
+}
+
+// Dependencies returns the consumers owning services the named consumer
+// resolved, in the order first resolved.
+func (r *Registry) Dependencies(consumer string) []string {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	return slices.Clone(r.state.deps[consumer])
+}
+
+// OnStart registers fn to run when the view's consumer starts. Functions
+// registered by the same consumer run in registration order. It panics on
+// a Registry not returned by Consumer.
+func (r *Registry) OnStart(fn func(ctx context.Context) error) {
+	if r.consumer == "" {
+		panic("registry: OnStart requires a consumer view")
+	}
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.starts[r.consumer] = append(r.state.starts[r.consumer], fn)
+}
+
+// Start registers a startup hook named after each consumer with lc, ordered
+// after the hooks of its dependencies, that runs the consumer's OnStart
+// functions. Every consumer gets a hook, even with no OnStart functions, so
+// its dependents can wait on it. Call it after Verify succeeds. It returns
+// an error if the dependencies form a cycle or a consumer name is already
+// registered as a startup hook; hooks registered before the error may then
+// wait indefinitely, so the caller must abort startup.
+func (r *Registry) Start(lc *lifecycle.Coordinator) error {
+	r.state.mu.Lock()
+	consumers := slices.Clone(r.state.consumers)
+	deps := make(map[string][]string, len(consumers))
+	starts := make(map[string][]func(ctx context.Context) error, len(consumers))
+	for _, name := range consumers {
+		deps[name] = slices.Clone(r.state.deps[name])
+		starts[name] = slices.Clone(r.state.starts[name])
+	}
+	r.state.mu.Unlock()
+
+	for _, name := range consumers {
+		fns := starts[name]
+		err := lc.OnStartupAfter(name, deps[name], func(ctx context.Context) error {
+			for _, fn := range fns {
+				if err := fn(ctx); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("registry: %w", err)
+		}
+	}
+	return nil
+}
+
+// Provide registers value as the service for type T, owned by the view's
+// consumer if any. Panics if a service of type T has already been provided.
+func Provide[T any](r *Registry, value T) {
+	key := reflect.TypeFor[T]()
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if _, ok := r.state.services[key]; ok {
+		panic(fmt.Sprintf("registry: %s already provided", key))
+	}
+	r.state.services[key] = value
+	if r.consumer != "" {
+		r.state.owners[key] = r.consumer
+	}
+}
+
+// Resolve returns the service provided for type T, recording the service's
+// owner as a dependency of the view's consumer.
+// Returns an error naming the consumer and the missing type if none was provided.
+func Resolve[T any](r *Registry) (T, error) {
+	key := reflect.TypeFor[T]()
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	if value, ok := r.state.services[key]; ok {
+		owner := r.state.owners[key]
+		if owner != "" && r.consumer != "" && owner != r.consumer && !slices.Contains(r.state.deps[r.consumer], owner) {
+			r.state.deps[r.consumer] = append(r.state.deps[r.consumer], owner)
+		}
+		return value.(T), nil
+	}
+
+	consumer := r.consumer
+	if consumer == "" {
+		consumer = "unknown consumer"
+	}
+	err := fmt.Errorf("%s: missing dependency %s", consumer, key)
+	r.state.unresolved = append(r.state.unresolved, err)
+
+	var zero T
+	return zero, err
+}
@@ -0,0 +1,224 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/JaimeStill/go-lit/pkg/lifecycle"
+)
+
+type store struct{ name string }
+
+type clock struct{}
+
+func TestResolveMissing(t *testing.T) {
+	tests := []struct {
+		name     string
+		consumer string
+		want     []string
+	}{
+		{
+			name:     "named consumer",
+			consumer: "api",
+			want:     []string{"api: missing dependency *registry.store"},
+		},
+		{
+			name: "root registry",
+			want: []string{"unknown consumer: missing dependency *registry.store"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := New()
+			view := reg
+			if tt.consumer != "" {
+				view = reg.Consumer(tt.consumer)
+			}
+
+			got, err := Resolve[*store](view)
+			if err == nil || got != nil {
+				t.Fatalf("Resolve() = %v, %v, want nil and an error", got, err)
+			}
+
+			verr := reg.Verify()
+			if verr == nil {
+				t.Fatal("Verify() = nil, want the unresolved dependency")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(verr.Error(), want) {
+					t.Errorf("Verify() = %q, want it to contain %q", verr, want)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyReportsEveryConsumer(t *testing.T) {
+	reg := New()
+	Provide(reg, clock{})
+
+	Resolve[clock](reg.Consumer("prompts"))
+	Resolve[*store](reg.Consumer("agents"))
+	Resolve[*store](reg.Consumer("conversations"))
+
+	err := reg.Verify()
+	if err == nil {
+		t.Fatal("Verify() = nil, want the unresolved dependencies")
+	}
+	want := "agents: missing dependency *registry.store\nconversations: missing dependency *registry.store"
+	if err.Error() != want {
+		t.Errorf("Verify() = %q, want %q", err, want)
+	}
+}
+
+func TestSharedInstance(t *testing.T) {
+	reg := New()
+	provided := &store{name: "prompts"}
+	Provide(reg, provided)
+
+	a, err := Resolve[*store](reg.Consumer("agents"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Resolve[*store](reg.Consumer("conversations"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a != provided || b != provided {
+		t.Errorf("resolved %p and %p, want both %p", a, b, provided)
+	}
+	if err := reg.Verify(); err != nil {
+		t.Errorf("Verify() = %v, want nil", err)
+	}
+}
+
+func TestProvideTwicePanics(t *testing.T) {
+	reg := New()
+	Provide(reg, &store{})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("second Provide did not panic")
+		}
+	}()
+	Provide(reg.Consumer("api"), &store{})
+}
+
+func TestStart(t *testing.T) {
+	tests := []struct {
+		name      string
+		wire      func(reg *Registry, record func(string))
+		wantOrder []string
+		wantErr   string
+	}{
+		{
+			name: "dependency starts first",
+			wire: func(reg *Registry, record func(string)) {
+				agents := reg.Consumer("agents")
+				prompts := reg.Consumer("prompts")
+				Provide(prompts, &store{})
+				Resolve[*store](agents)
+				agents.OnStart(func(context.Context) error { record("agents"); return nil })
+				prompts.OnStart(func(context.Context) error { record("prompts"); return nil })
+			},
+			wantOrder: []string{"prompts", "agents"},
+		},
+		{
+			name: "consumer without start functions",
+			wire: func(reg *Registry, record func(string)) {
+				agents := reg.Consumer("agents")
+				Provide(reg.Consumer("prompts"), &store{})
+				Resolve[*store](agents)
+				agents.OnStart(func(context.Context) error { record("agents"); return nil })
+			},
+			wantOrder: []string{"agents"},
+		},
+		{
+			name: "root services add no dependency",
+			wire: func(reg *Registry, record func(string)) {
+				Provide(reg, &store{})
+				Resolve[*store](reg.Consumer("agents"))
+				reg.Consumer("agents").OnStart(func(context.Context) error { record("agents"); return nil })
+			},
+			wantOrder: []string{"agents"},
+		},
+		{
+			name: "cycle",
+			wire: func(reg *Registry, record func(string)) {
+				agents := reg.Consumer("agents")
+				prompts := reg.Consumer("prompts")
+				Provide(agents, clock{})
+				Provide(prompts, &store{})
+				Resolve[*store](agents)
+				Resolve[clock](prompts)
+			},
+			wantErr: "dependency cycle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var order []string
+			record := func(name string) {
+				mu.Lock()
+				defer mu.Unlock()
+				order = append(order, name)
+			}
+
+			reg := New()
+			tt.wire(reg, record)
+
+			lc := lifecycle.New()
+			err := reg.Start(lc)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Start() = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Start() = %v", err)
+			}
+			lc.WaitForStartup()
+			if !slices.Equal(order, tt.wantOrder) {
+				t.Errorf("start order = %v, want %v", order, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestStartFailurePropagates(t *testing.T) {
+	reg := New()
+	agents := reg.Consumer("agents")
+	prompts := reg.Consumer("prompts")
+	Provide(prompts, &store{})
+	Resolve[*store](agents)
+	if deps := reg.Dependencies("agents"); !slices.Equal(deps, []string{"prompts"}) {
+		t.Fatalf("Dependencies(agents) = %v, want [prompts]", deps)
+	}
+
+	ran := false
+	prompts.OnStart(func(context.Context) error { return errors.New("load failed") })
+	agents.OnStart(func(context.Context) error { ran = true; return nil })
+
+	lc := lifecycle.New()
+	if err := reg.Start(lc); err != nil {
+		t.Fatal(err)
+	}
+	err := lc.WaitForStartupContext(context.Background())
+
+	var startupErr *lifecycle.StartupError
+	if !errors.As(err, &startupErr) || len(startupErr.Failed) != 2 {
+		t.Fatalf("WaitForStartupContext() = %v, want prompts and agents failed", err)
+	}
+	if ran {
+		t.Error("agents started after its dependency failed")
+	}
+}
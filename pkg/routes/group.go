@@ -1,82 +1,465 @@
 package routes
 
 import (
+	"errors"
+	"fmt"
 	"maps"
 	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
 
 	"github.com/JaimeStill/go-lit/pkg/openapi"
 )
 
 // Group represents a collection of routes under a common URL prefix.
 // Groups can contain child groups for hierarchical route organization.
+//
+// Middleware wraps every route in the group and its children. A parent's
+// middleware runs outside a child's, and both run outside route middleware.
+// Middleware does not affect the OpenAPI documentation.
+//
+// MethodNotAllowed, which children inherit, answers requests to the group's
+// paths that use an unregistered method with 405 and an Allow header listing
+// the registered methods. OPTIONS requests receive 204 with the same header
+// unless a route registers OPTIONS itself. These responses bypass group and
+// route middleware.
+//
+// Version and Undocumented are inherited by children. Version tags every
+// operation with the version and prefixes explicit operation IDs with it, so
+// the same operations can be documented under several versions; it is set by
+// Versioned. Undocumented routes are served but left out of the specification,
+// such as a deprecated version kept running during a migration.
+//
+// Enabled gates the group and its children like Route.Enabled; a disabled
+// group's routes and schemas are left out entirely.
+//
+// Host restricts the group and its children to requests for that host, such as
+// admin.example.com, producing patterns of the form "METHOD host/path". The
+// group's operations document the host as their server, using the scheme of the
+// specification's first server. A child may repeat its parent's host but not
+// set a different one.
 type Group struct {
 	Prefix      string
+	Host        string
 	Tags        []string
 	Description string
 	Routes      []Route
 	Children    []Group
 	Schemas     map[string]*openapi.Schema
+	Parameters  map[string]*openapi.Parameter
+	Middleware  []func(http.Handler) http.Handler
+
+	MethodNotAllowed bool
+	Version          string
+	Undocumented     bool
+	Enabled          func() bool
+}
+
+func (g Group) enabled() bool {
+	return g.Enabled == nil || g.Enabled()
 }
 
-// AddToSpec adds the group's routes and schemas to the OpenAPI specification.
+// AddToSpec adds the group's routes, schemas, parameters, and tag to the OpenAPI specification.
+// The group's first tag is registered with the group's Description.
+// Panics if a documented route uses a method the specification cannot
+// represent; Register reports such routes as an error instead.
+//
+// Operations without an OperationID receive one derived from the method and
+// the route path relative to basePath: the lowercased method followed by each
+// static segment in PascalCase, with path parameters rendered as By{Param}.
+// For example, GET /prompts/{id} becomes getPromptsById.
 func (g *Group) AddToSpec(basePath string, spec *openapi.Spec) {
-	g.addOperations(basePath, spec)
+	g.addOperations(basePath, basePath, docScope{}, spec)
 }
 
-func (g *Group) addOperations(parentPrefix string, spec *openapi.Spec) {
+// docScope holds the documentation settings a group passes to its children.
+type docScope struct {
+	version      string
+	undocumented bool
+	host         string
+}
+
+func (g *Group) addOperations(basePath, parentPrefix string, scope docScope, spec *openapi.Spec) {
+	if !g.enabled() {
+		return
+	}
 	fullPrefix := parentPrefix + g.Prefix
+	if g.Version != "" {
+		scope.version = g.Version
+	}
+	if g.Host != "" {
+		scope.host = g.Host
+	}
+	scope.undocumented = scope.undocumented || g.Undocumented
+	if scope.undocumented {
+		return
+	}
 
 	maps.Copy(spec.Components.Schemas, g.Schemas)
+	if len(g.Parameters) > 0 {
+		spec.Components.AddParameters(g.Parameters)
+	}
+
+	if len(g.Tags) > 0 {
+		spec.AddTag(g.Tags[0], g.Description)
+	}
+	if g.Version != "" {
+		spec.AddTag(g.Version, "")
+	}
 
 	for _, route := range g.Routes {
-		if route.OpenAPI == nil {
+		if route.OpenAPI == nil || !route.enabled() {
 			continue
 		}
 
 		path := fullPrefix + route.Pattern
-		op := route.OpenAPI
+		copied := *route.OpenAPI
+		op := &copied
 
 		if len(op.Tags) == 0 {
 			op.Tags = g.Tags
 		}
 
+		if scope.host != "" {
+			op.Servers = []*openapi.Server{hostServer(spec, scope.host)}
+		}
+
+		if scope.version != "" {
+			op.Tags = append(slices.Clone(op.Tags), scope.version)
+			if op.OperationID != "" {
+				op.OperationID = scope.version + pascalCase(op.OperationID)
+			}
+		}
+
+		if route.Deprecated {
+			op.Deprecated = true
+		}
+
+		if op.OperationID == "" {
+			op.OperationID = deriveOperationID(route.Method, strings.TrimPrefix(path, basePath))
+		}
+
 		if spec.Paths[path] == nil {
 			spec.Paths[path] = &openapi.PathItem{}
 		}
 
-		switch route.Method {
-		case "GET":
-			spec.Paths[path].Get = op
-		case "POST":
-			spec.Paths[path].Post = op
-		case "PUT":
-			spec.Paths[path].Put = op
-		case "DELETE":
-			spec.Paths[path].Delete = op
+		op = withPathParams(spec, spec.Paths[path], op, path, route.PathParams)
+
+		if err := spec.Paths[path].SetOperation(route.Method, op); err != nil {
+			panic(fmt.Errorf("route %s %s: %w", route.Method, path, err))
 		}
 	}
 
 	for _, child := range g.Children {
-		child.addOperations(fullPrefix, spec)
+		child.addOperations(basePath, fullPrefix, scope, spec)
+	}
+}
+
+// hostServer returns a server for host using the scheme of the specification's
+// first server, defaulting to https.
+func hostServer(spec *openapi.Spec, host string) *openapi.Server {
+	scheme := "https"
+	if len(spec.Servers) > 0 {
+		if u, err := url.Parse(spec.Servers[0].URL); err == nil && u.Scheme != "" {
+			scheme = u.Scheme
+		}
+	}
+	return &openapi.Server{URL: scheme + "://" + host}
+}
+
+// groupHost returns the host a group serves, given the host it inherits.
+func groupHost(inherited string, group Group) string {
+	if group.Host != "" {
+		return group.Host
+	}
+	return inherited
+}
+
+// withPathParams returns op with a parameter added for each path template
+// segment it does not document, either directly or at the path level.
+// The operation is copied when parameters are added so shared definitions
+// are not modified.
+func withPathParams(spec *openapi.Spec, item *openapi.PathItem, op *openapi.Operation, path string, schemas map[string]*openapi.Schema) *openapi.Operation {
+	var derived []*openapi.Parameter
+	for _, name := range openapi.PathTemplateParams(path) {
+		declared := slices.ContainsFunc(item.EffectiveParameters(op), func(p *openapi.Parameter) bool {
+			p = spec.ResolveParameter(p)
+			return p != nil && p.In == "path" && p.Name == name
+		})
+		if declared {
+			continue
+		}
+
+		schema := schemas[name]
+		if schema == nil {
+			schema = &openapi.Schema{Type: "string"}
+		}
+		derived = append(derived, &openapi.Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   schema,
+		})
+	}
+
+	if len(derived) == 0 {
+		return op
+	}
+
+	result := *op
+	result.Parameters = append(slices.Clone(op.Parameters), derived...)
+	return &result
+}
+
+func deriveOperationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+
+	for segment := range strings.SplitSeq(path, "/") {
+		if segment == "" {
+			continue
+		}
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			b.WriteString("By")
+			segment = strings.TrimSuffix(strings.Trim(segment, "{}"), "...")
+		}
+		b.WriteString(pascalCase(segment))
+	}
+
+	return b.String()
+}
+
+func pascalCase(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if r == '-' || r == '_' || r == '.' || r == '$' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
 	}
+	return b.String()
 }
 
 // Register registers route groups with the HTTP mux and adds their OpenAPI documentation.
-func Register(mux *http.ServeMux, basePath string, spec *openapi.Spec, groups ...Group) {
+// The standard error responses are installed so routes can reference them, and
+// parameters shared by every operation on a path are hoisted to the path level.
+//
+// Nothing is registered if a route sets no handler or both Handler and
+// HTTPHandler, if a documented route uses a method the specification cannot
+// represent, or if the groups conflict: routes that share a method and full
+// pattern, or schemas that share a name with different definitions, are
+// reported as an error naming both owners. Identical schemas are deduplicated.
+// Patterns the mux rejects, such as overlapping patterns neither of which is
+// more specific, are found by mounting the groups on a scratch mux first, so
+// they too are returned before anything is registered. Only a conflict with a
+// route registered on mux before the call can leave the groups partially
+// mounted.
+func Register(mux *http.ServeMux, basePath string, spec *openapi.Spec, groups ...Group) error {
+	openapi.RegisterStandardResponses(spec)
+	if err := checkConflicts(spec, groups); err != nil {
+		return err
+	}
+	if err := mount(http.NewServeMux(), groups); err != nil {
+		return err
+	}
+	if err := mount(mux, groups); err != nil {
+		return err
+	}
+
 	for _, group := range groups {
 		group.AddToSpec(basePath, spec)
-		registerGroup(mux, "", group)
 	}
+	for _, item := range spec.Paths {
+		item.HoistParameters()
+	}
+	return nil
+}
+
+// mount registers the groups' routes and method fallbacks with mux.
+func mount(mux *http.ServeMux, groups []Group) error {
+	for _, group := range groups {
+		if err := registerGroup(mux, "", "", nil, group); err != nil {
+			return err
+		}
+	}
+	return registerMethodFallbacks(mux, groups)
+}
+
+// checkConflicts reports every route without exactly one handler, every
+// documented route whose method has no OpenAPI operation, every child group
+// setting a host other than its parent's, every method and full pattern
+// registered by more than one route, and every schema name
+// defined differently by more than one group or by a group and the existing
+// specification.
+func checkConflicts(spec *openapi.Spec, groups []Group) error {
+	routeOwners := make(map[string]string)
+	type schemaOwner struct {
+		label  string
+		schema *openapi.Schema
+	}
+	schemaOwners := make(map[string]schemaOwner)
+	for name, schema := range spec.Components.Schemas {
+		schemaOwners[name] = schemaOwner{"the specification", schema}
+	}
+	var errs []error
+
+	var walk func(parentPrefix, parentHost string, undocumented bool, group Group)
+	walk = func(parentPrefix, parentHost string, undocumented bool, group Group) {
+		if !group.enabled() {
+			return
+		}
+		undocumented = undocumented || group.Undocumented
+		fullPrefix := parentPrefix + group.Prefix
+		label := groupLabel(fullPrefix, group)
+		if parentHost != "" && group.Host != "" && group.Host != parentHost {
+			errs = append(errs, fmt.Errorf("%s: host %s differs from inherited host %s", label, group.Host, parentHost))
+		}
+		host := groupHost(parentHost, group)
+		for _, route := range group.Routes {
+			if !route.enabled() {
+				continue
+			}
+			pattern := route.Method + " " + host + fullPrefix + route.Pattern
+			if err := route.validate(); err != nil {
+				errs = append(errs, fmt.Errorf("route %s in %s: %w", pattern, label, err))
+			}
+			if route.OpenAPI != nil && !undocumented {
+				if err := new(openapi.PathItem).SetOperation(route.Method, route.OpenAPI); err != nil {
+					errs = append(errs, fmt.Errorf("route %s in %s: %w", pattern, label, err))
+				}
+			}
+			if prev, ok := routeOwners[pattern]; ok {
+				errs = append(errs, fmt.Errorf("duplicate route %s: registered by %s and %s", pattern, prev, label))
+				continue
+			}
+			routeOwners[pattern] = label
+		}
+		for _, name := range slices.Sorted(maps.Keys(group.Schemas)) {
+			schema := group.Schemas[name]
+			prev, ok := schemaOwners[name]
+			if !ok {
+				schemaOwners[name] = schemaOwner{label, schema}
+				continue
+			}
+			if !reflect.DeepEqual(prev.schema, schema) {
+				errs = append(errs, fmt.Errorf("schema %q: defined differently by %s and %s", name, prev.label, label))
+			}
+		}
+		for _, child := range group.Children {
+			walk(fullPrefix, host, undocumented, child)
+		}
+	}
+
+	for _, group := range groups {
+		walk("", "", false, group)
+	}
+	return errors.Join(errs...)
+}
+
+func groupLabel(fullPrefix string, group Group) string {
+	if fullPrefix == "" {
+		fullPrefix = "/"
+	}
+	if len(group.Tags) > 0 {
+		return fmt.Sprintf("group %q (%s)", group.Tags[0], fullPrefix)
+	}
+	return fmt.Sprintf("group %s", fullPrefix)
 }
 
-func registerGroup(mux *http.ServeMux, parentPrefix string, group Group) {
+// RegisterMerged registers route groups with the HTTP mux like Register, but
+// documents them in a standalone fragment that is combined into spec with
+// openapi.Merge. Operations, schemas, and parameters that conflict with
+// definitions already in spec are returned as an error instead of replacing them,
+// letting independently built modules contribute to a single document.
+func RegisterMerged(mux *http.ServeMux, basePath string, spec *openapi.Spec, groups ...Group) error {
+	fragment := &openapi.Spec{
+		Servers:    spec.Servers,
+		Paths:      make(map[string]*openapi.PathItem),
+		Components: &openapi.Components{Schemas: make(map[string]*openapi.Schema)},
+	}
+
+	if err := Register(mux, basePath, fragment, groups...); err != nil {
+		return err
+	}
+
+	return openapi.Merge(spec, fragment)
+}
+
+// registerGroup registers the group's routes wrapped in the inherited middleware
+// followed by the group's own. The chain is built as a new slice at each level
+// so neither the parent's nor the group's slices are modified.
+func registerGroup(mux *http.ServeMux, parentPrefix, parentHost string, inherited []func(http.Handler) http.Handler, group Group) error {
+	if !group.enabled() {
+		return nil
+	}
 	fullPrefix := parentPrefix + group.Prefix
+	host := groupHost(parentHost, group)
+	chain := slices.Concat(inherited, group.Middleware)
+
 	for _, route := range group.Routes {
-		pattern := route.Method + " " + fullPrefix + route.Pattern
-		mux.HandleFunc(pattern, route.Handler)
+		if !route.enabled() {
+			continue
+		}
+		pattern := route.Method + " " + host + fullPrefix + route.Pattern
+		h := route.handler()
+		for i := len(chain) - 1; i >= 0; i-- {
+			h = chain[i](h)
+		}
+		if err := handle(mux, pattern, h); err != nil {
+			return fmt.Errorf("%s: %w", groupLabel(fullPrefix, group), err)
+		}
 	}
 	for _, child := range group.Children {
-		registerGroup(mux, fullPrefix, child)
+		if err := registerGroup(mux, fullPrefix, host, chain, child); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
+// handle registers the pattern, converting the panic ServeMux raises for
+// invalid or conflicting patterns into an error.
+func handle(mux *http.ServeMux, pattern string, h http.Handler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("register %s: %v", pattern, r)
+		}
+	}()
+	mux.Handle(pattern, h)
+	return nil
+}
+
+// Disabled returns the method and full pattern of every route left out because
+// it or an enclosing group is disabled, for logging at startup.
+func Disabled(groups ...Group) []string {
+	var disabled []string
+
+	var walk func(parentPrefix, parentHost string, off bool, group Group)
+	walk = func(parentPrefix, parentHost string, off bool, group Group) {
+		fullPrefix := parentPrefix + group.Prefix
+		host := groupHost(parentHost, group)
+		off = off || !group.enabled()
+		for _, route := range group.Routes {
+			if off || !route.enabled() {
+				disabled = append(disabled, route.Method+" "+host+fullPrefix+route.Pattern)
+			}
+		}
+		for _, child := range group.Children {
+			walk(fullPrefix, host, off, child)
+		}
+	}
+
+	for _, group := range groups {
+		walk("", "", false, group)
+	}
+	return disabled
+}
@@ -0,0 +1,94 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/go-lit/pkg/openapi"
+)
+
+func ok(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestRegisterRejectsBeforeMounting(t *testing.T) {
+	valid := Group{
+		Prefix: "/items",
+		Routes: []Route{{Method: "GET", Pattern: "", Handler: ok}},
+	}
+
+	tests := []struct {
+		name    string
+		group   Group
+		wantErr string
+	}{
+		{
+			name: "undocumentable method",
+			group: Group{
+				Prefix: "/trace",
+				Routes: []Route{{Method: "TRACE", Pattern: "", Handler: ok, OpenAPI: &openapi.Operation{}}},
+			},
+			wantErr: "unsupported operation method: TRACE",
+		},
+		{
+			name: "overlapping patterns",
+			group: Group{
+				Routes: []Route{
+					{Method: "GET", Pattern: "/a/{x}", Handler: ok},
+					{Method: "GET", Pattern: "/{y}/b", Handler: ok},
+				},
+			},
+			wantErr: "conflicts with pattern",
+		},
+		{
+			name: "missing handler",
+			group: Group{
+				Prefix: "/none",
+				Routes: []Route{{Method: "GET", Pattern: ""}},
+			},
+			wantErr: "no handler set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			spec := openapi.NewSpec("test", "1.0.0")
+
+			err := Register(mux, "", spec, valid, tt.group)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Register() error = %v, want containing %q", err, tt.wantErr)
+			}
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest("GET", "/items", nil))
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("GET /items = %d, want 404 with nothing mounted", rec.Code)
+			}
+			if len(spec.Paths) != 0 {
+				t.Errorf("spec paths = %v, want none documented", spec.Paths)
+			}
+		})
+	}
+}
+
+func TestRegisterUndocumentedMethod(t *testing.T) {
+	mux := http.NewServeMux()
+	group := Group{
+		Prefix:       "/trace",
+		Undocumented: true,
+		Routes:       []Route{{Method: "TRACE", Pattern: "", Handler: ok, OpenAPI: &openapi.Operation{}}},
+	}
+
+	if err := Register(mux, "", openapi.NewSpec("test", "1.0.0"), group); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("TRACE", "/trace", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("TRACE /trace = %d, want 200", rec.Code)
+	}
+}
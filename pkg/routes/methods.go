@@ -0,0 +1,80 @@
+package routes
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/JaimeStill/go-lit/pkg/handlers"
+)
+
+// registerMethodFallbacks registers a method-less handler for each path served
+// by a group with MethodNotAllowed set. Method-qualified patterns take precedence
+// in ServeMux, so the fallback only receives requests for methods the path does
+// not serve: OPTIONS is answered with 204 and every other method with 405, both
+// listing the supported methods in the Allow header.
+//
+// Methods registered for the path by any group are listed, so a path split across
+// groups reports all of them.
+func registerMethodFallbacks(mux *http.ServeMux, groups []Group) error {
+	methods := make(map[string][]string)
+	enabled := make(map[string]bool)
+
+	var walk func(parentPrefix, parentHost string, inherited bool, group Group)
+	walk = func(parentPrefix, parentHost string, inherited bool, group Group) {
+		if !group.enabled() {
+			return
+		}
+		fullPrefix := parentPrefix + group.Prefix
+		host := groupHost(parentHost, group)
+		on := inherited || group.MethodNotAllowed
+		for _, route := range group.Routes {
+			if !route.enabled() {
+				continue
+			}
+			path := host + fullPrefix + route.Pattern
+			methods[path] = append(methods[path], route.Method)
+			if on {
+				enabled[path] = true
+			}
+		}
+		for _, child := range group.Children {
+			walk(fullPrefix, host, on, child)
+		}
+	}
+	for _, group := range groups {
+		walk("", "", false, group)
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(enabled)) {
+		if err := handle(mux, path, methodFallback(methods[path])); err != nil {
+			return fmt.Errorf("method fallback: %w", err)
+		}
+	}
+	return nil
+}
+
+// methodFallback responds to methods a path does not register. GET implies
+// HEAD, since ServeMux serves HEAD requests with GET handlers.
+func methodFallback(registered []string) http.HandlerFunc {
+	allowed := slices.Clone(registered)
+	if slices.Contains(allowed, http.MethodGet) {
+		allowed = append(allowed, http.MethodHead)
+	}
+	allowed = append(allowed, http.MethodOptions)
+	slices.Sort(allowed)
+	allow := strings.Join(slices.Compact(allowed), ", ")
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", allow)
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		handlers.RespondJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": fmt.Sprintf("method %s not allowed", r.Method),
+		})
+	}
+}
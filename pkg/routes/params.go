@@ -0,0 +1,118 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrMissingParam = errors.New("missing parameter")
+	ErrInvalidParam = errors.New("invalid parameter")
+)
+
+// ParamError reports a path or query parameter that is missing or cannot be
+// parsed as the format its OpenAPI declaration documents. It unwraps to
+// ErrMissingParam or ErrInvalidParam, both of which map to 400 Bad Request.
+type ParamError struct {
+	Name   string
+	In     string
+	Reason string
+	Err    error
+}
+
+func (e *ParamError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("%s: %s %q", e.Err, e.In, e.Name)
+	}
+	return fmt.Sprintf("%s: %s %q: %s", e.Err, e.In, e.Name, e.Reason)
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// PathUUID parses the named path value as a UUID, the format declared by
+// openapi.PathParam.
+func PathUUID(r *http.Request, name string) (uuid.UUID, error) {
+	v, err := pathValue(r, name)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return uuid.Nil, invalidParam(name, "path", "must be a uuid")
+	}
+	return id, nil
+}
+
+// PathInt parses the named path value as a base 10 integer.
+func PathInt(r *http.Request, name string) (int, error) {
+	v, err := pathValue(r, name)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, invalidParam(name, "path", "must be an integer")
+	}
+	return n, nil
+}
+
+// QueryInt parses the named query value as a base 10 integer, returning def
+// when the value is absent or empty.
+func QueryInt(r *http.Request, name string, def int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def, invalidParam(name, "query", "must be an integer")
+	}
+	return n, nil
+}
+
+// QueryBool parses the named query value with strconv.ParseBool, returning def
+// when the value is absent or empty.
+func QueryBool(r *http.Request, name string, def bool) (bool, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return def, invalidParam(name, "query", "must be a boolean")
+	}
+	return b, nil
+}
+
+// QueryTime parses the named query value as an RFC 3339 timestamp, the
+// date-time format, returning def when the value is absent or empty.
+func QueryTime(r *http.Request, name string, def time.Time) (time.Time, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return def, invalidParam(name, "query", "must be an RFC 3339 date-time")
+	}
+	return t, nil
+}
+
+func pathValue(r *http.Request, name string) (string, error) {
+	v := r.PathValue(name)
+	if v == "" {
+		return "", &ParamError{Name: name, In: "path", Err: ErrMissingParam}
+	}
+	return v, nil
+}
+
+func invalidParam(name, in, reason string) error {
+	return &ParamError{Name: name, In: in, Reason: reason, Err: ErrInvalidParam}
+}
@@ -0,0 +1,199 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// pathRequest returns a request whose path value name is set to value,
+// or left unset when value is empty.
+func pathRequest(name, value string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if value != "" {
+		r.SetPathValue(name, value)
+	}
+	return r
+}
+
+func queryRequest(query string) *http.Request {
+	return httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+}
+
+// checkParamErr reports whether err matches want, an ErrMissingParam or
+// ErrInvalidParam sentinel or nil, and is a *ParamError naming the parameter.
+func checkParamErr(t *testing.T, err, want error, name, in string) {
+	t.Helper()
+	if want == nil {
+		if err != nil {
+			t.Fatalf("error = %v, want nil", err)
+		}
+		return
+	}
+	if !errors.Is(err, want) {
+		t.Fatalf("error = %v, want %v", err, want)
+	}
+	var pe *ParamError
+	if !errors.As(err, &pe) {
+		t.Fatalf("error = %T, want *ParamError", err)
+	}
+	if pe.Name != name || pe.In != in {
+		t.Errorf("ParamError names %s %q, want %s %q", pe.In, pe.Name, in, name)
+	}
+}
+
+func TestPathUUID(t *testing.T) {
+	id := uuid.New()
+	tests := []struct {
+		name    string
+		value   string
+		want    uuid.UUID
+		wantErr error
+	}{
+		{name: "valid", value: id.String(), want: id},
+		{name: "missing", wantErr: ErrMissingParam},
+		{name: "malformed", value: "not-a-uuid", wantErr: ErrInvalidParam},
+		{name: "integer", value: "42", wantErr: ErrInvalidParam},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PathUUID(pathRequest("id", tt.value), "id")
+			checkParamErr(t, err, tt.wantErr, "id", "path")
+			if got != tt.want {
+				t.Errorf("PathUUID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathInt(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr error
+	}{
+		{name: "valid", value: "42", want: 42},
+		{name: "negative", value: "-7", want: -7},
+		{name: "missing", wantErr: ErrMissingParam},
+		{name: "malformed", value: "4x2", wantErr: ErrInvalidParam},
+		{name: "decimal", value: "4.2", wantErr: ErrInvalidParam},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PathInt(pathRequest("n", tt.value), "n")
+			checkParamErr(t, err, tt.wantErr, "n", "path")
+			if got != tt.want {
+				t.Errorf("PathInt() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryInt(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		def     int
+		want    int
+		wantErr error
+	}{
+		{name: "valid", query: "page=3", def: 1, want: 3},
+		{name: "absent uses default", query: "", def: 1, want: 1},
+		{name: "empty uses default", query: "page=", def: 1, want: 1},
+		{name: "malformed returns default", query: "page=three", def: 1, want: 1, wantErr: ErrInvalidParam},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := QueryInt(queryRequest(tt.query), "page", tt.def)
+			checkParamErr(t, err, tt.wantErr, "page", "query")
+			if got != tt.want {
+				t.Errorf("QueryInt() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryBool(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		def     bool
+		want    bool
+		wantErr error
+	}{
+		{name: "true", query: "all=true", want: true},
+		{name: "numeric false", query: "all=0", def: true, want: false},
+		{name: "absent uses default", query: "", def: true, want: true},
+		{name: "malformed returns default", query: "all=yes", def: true, want: true, wantErr: ErrInvalidParam},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := QueryBool(queryRequest(tt.query), "all", tt.def)
+			checkParamErr(t, err, tt.wantErr, "all", "query")
+			if got != tt.want {
+				t.Errorf("QueryBool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryTime(t *testing.T) {
+	def := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		query   string
+		want    time.Time
+		wantErr error
+	}{
+		{name: "valid", query: "since=2024-05-01T12:30:00Z", want: time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)},
+		{name: "absent uses default", query: "", want: def},
+		{name: "date only", query: "since=2024-05-01", want: def, wantErr: ErrInvalidParam},
+		{name: "malformed returns default", query: "since=yesterday", want: def, wantErr: ErrInvalidParam},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := QueryTime(queryRequest(tt.query), "since", def)
+			checkParamErr(t, err, tt.wantErr, "since", "query")
+			if !got.Equal(tt.want) {
+				t.Errorf("QueryTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParamErrorMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "missing",
+			err:  &ParamError{Name: "id", In: "path", Err: ErrMissingParam},
+			want: `missing parameter: path "id"`,
+		},
+		{
+			name: "invalid",
+			err:  invalidParam("page", "query", "must be an integer"),
+			want: `invalid parameter: query "page": must be an integer`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
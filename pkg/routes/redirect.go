@@ -0,0 +1,86 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/JaimeStill/go-lit/pkg/openapi"
+)
+
+// Redirect returns a route redirecting requests for from to to, preserving the
+// query string. Permanent redirects use 308 and temporary ones 307, so clients
+// repeat the original method and body. The route is documented as a deprecated
+// operation pointing to the new path.
+//
+// A target without a scheme is a path relative to the module, like a route
+// pattern, and resolves against the module prefix the request arrived under.
+// Path parameters in from, such as {id}, are substituted into to by name.
+func Redirect(method, from, to string, permanent bool) Route {
+	status := http.StatusTemporaryRedirect
+	kind := "Temporary"
+	if permanent {
+		status = http.StatusPermanentRedirect
+		kind = "Permanent"
+	}
+
+	op := openapi.NewOperation("Redirect to "+to).
+		Description(fmt.Sprintf("Deprecated: moved to %s. Requests are redirected with %d %s.", to, status, http.StatusText(status))).
+		Response(openapi.StatusCode(status), &openapi.Response{
+			Description: kind + " redirect to " + to,
+			Headers: openapi.Headers(
+				openapi.HeaderString("Location", "URL of the new location, including the original query string"),
+			),
+		}).
+		Deprecated().
+		Build()
+
+	return Route{
+		Method:     method,
+		Pattern:    from,
+		Handler:    redirectHandler(from, to, status),
+		OpenAPI:    op,
+		Deprecated: true,
+	}
+}
+
+func redirectHandler(from, to string, status int) http.HandlerFunc {
+	absolute := false
+	if u, err := url.Parse(to); err == nil && u.Scheme != "" {
+		absolute = true
+	}
+	params := openapi.PathTemplateParams(from)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		target := to
+		for _, name := range params {
+			placeholder := "{" + name + "}"
+			if !strings.Contains(target, placeholder) {
+				placeholder = "{" + name + "...}"
+			}
+			target = strings.ReplaceAll(target, placeholder, r.PathValue(name))
+		}
+		if !absolute {
+			target = modulePrefix(r) + "/" + strings.TrimPrefix(target, "/")
+		}
+		if r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, target, status)
+	}
+}
+
+// modulePrefix returns the prefix a module stripped from the request path,
+// recovered by comparing the original request URI with the routed path.
+func modulePrefix(r *http.Request) string {
+	original, _, _ := strings.Cut(r.RequestURI, "?")
+	if u, err := url.PathUnescape(original); err == nil {
+		original = u
+	}
+	prefix, ok := strings.CutSuffix(original, r.URL.Path)
+	if !ok {
+		return ""
+	}
+	return prefix
+}
@@ -4,16 +4,64 @@
 package routes
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/JaimeStill/go-lit/pkg/openapi"
 )
 
 // Route defines an HTTP endpoint with its method, pattern, handler,
-// and optional OpenAPI documentation.
+// and optional OpenAPI documentation. Deprecated marks the documented
+// operation as deprecated when the specification is built.
+//
+// Middleware wraps only this route's handler, with the first entry outermost.
+// Module-level middleware still runs outside it, since it wraps the whole mux.
+//
+// Path parameters in the pattern that the operation does not declare are
+// documented automatically as required strings. PathParams overrides the
+// schema of a derived parameter by name, such as {"id": {Type: "string", Format: "uuid"}}.
+//
+// Handler accepts handler functions and methods directly. HTTPHandler serves
+// types implementing http.Handler, such as an httputil.ReverseProxy, without
+// adapting them. Exactly one of the two must be set.
+//
+// Enabled gates the route behind a feature flag: when it reports false the
+// route is neither registered nor documented. A nil Enabled is always enabled.
 type Route struct {
-	Method  string
-	Pattern string
-	Handler http.HandlerFunc
-	OpenAPI *openapi.Operation
+	Method      string
+	Pattern     string
+	Handler     http.HandlerFunc
+	HTTPHandler http.Handler
+	OpenAPI     *openapi.Operation
+	Deprecated  bool
+	PathParams  map[string]*openapi.Schema
+	Middleware  []func(http.Handler) http.Handler
+	Enabled     func() bool
+}
+
+func (r Route) enabled() bool {
+	return r.Enabled == nil || r.Enabled()
+}
+
+// handler returns the route handler wrapped in its middleware.
+func (r Route) handler() http.Handler {
+	var h http.Handler = r.Handler
+	if r.HTTPHandler != nil {
+		h = r.HTTPHandler
+	}
+	for i := len(r.Middleware) - 1; i >= 0; i-- {
+		h = r.Middleware[i](h)
+	}
+	return h
+}
+
+// validate reports whether the route sets exactly one handler.
+func (r Route) validate() error {
+	switch {
+	case r.Handler == nil && r.HTTPHandler == nil:
+		return errors.New("no handler set")
+	case r.Handler != nil && r.HTTPHandler != nil:
+		return errors.New("both Handler and HTTPHandler set")
+	}
+	return nil
 }
@@ -0,0 +1,61 @@
+package routes
+
+import "slices"
+
+// RouteInfo describes a route as it is registered with the mux.
+// Path is the full pattern path relative to the mux, without the base path,
+// and Host is set for routes in host-scoped groups. HasOpenAPI reports
+// whether the route is documented, which it is not in an Undocumented group.
+type RouteInfo struct {
+	Method     string
+	Host       string
+	Path       string
+	GroupTags  []string
+	HasOpenAPI bool
+}
+
+// RouteTable lists routes in registration order.
+type RouteTable []RouteInfo
+
+// Walk calls fn for every route the groups register, in registration order,
+// without registering anything. Routes disabled by a feature flag are skipped.
+// GroupTags are the tags of the group that declares the route.
+func Walk(fn func(RouteInfo), groups ...Group) {
+	var walk func(parentPrefix, parentHost string, undocumented bool, group Group)
+	walk = func(parentPrefix, parentHost string, undocumented bool, group Group) {
+		if !group.enabled() {
+			return
+		}
+		undocumented = undocumented || group.Undocumented
+		fullPrefix := parentPrefix + group.Prefix
+		host := groupHost(parentHost, group)
+		for _, route := range group.Routes {
+			if !route.enabled() {
+				continue
+			}
+			fn(RouteInfo{
+				Method:     route.Method,
+				Host:       host,
+				Path:       fullPrefix + route.Pattern,
+				GroupTags:  slices.Clone(group.Tags),
+				HasOpenAPI: route.OpenAPI != nil && !undocumented,
+			})
+		}
+		for _, child := range group.Children {
+			walk(fullPrefix, host, undocumented, child)
+		}
+	}
+
+	for _, group := range groups {
+		walk("", "", false, group)
+	}
+}
+
+// Table returns the routes the groups register.
+func Table(groups ...Group) RouteTable {
+	var table RouteTable
+	Walk(func(info RouteInfo) {
+		table = append(table, info)
+	}, groups...)
+	return table
+}
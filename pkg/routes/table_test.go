@@ -0,0 +1,162 @@
+package routes
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/JaimeStill/go-lit/pkg/openapi"
+)
+
+func documented(summary string) *openapi.Operation {
+	return openapi.NewOperation(summary).Response(200, openapi.ResponseEmpty("OK")).Build()
+}
+
+func TestTable(t *testing.T) {
+	off := func() bool { return false }
+	groups := []Group{
+		{
+			Prefix: "/items",
+			Tags:   []string{"Items"},
+			Routes: []Route{
+				{Method: "GET", Pattern: "", Handler: ok, OpenAPI: documented("List items")},
+				{Method: "POST", Pattern: "", Handler: ok},
+				{Method: "DELETE", Pattern: "/{id}", Handler: ok, OpenAPI: documented("Delete item"), Enabled: off},
+			},
+			Children: []Group{
+				{
+					Prefix: "/{id}/notes",
+					Tags:   []string{"Notes"},
+					Routes: []Route{{Method: "GET", Pattern: "", Handler: ok, OpenAPI: documented("List notes")}},
+				},
+				{
+					Prefix:  "/archive",
+					Enabled: off,
+					Routes:  []Route{{Method: "GET", Pattern: "", Handler: ok, OpenAPI: documented("List archive")}},
+				},
+			},
+		},
+		{
+			Prefix: "/admin",
+			Host:   "admin.example.com",
+			Tags:   []string{"Admin"},
+			Routes: []Route{{Method: "GET", Pattern: "/stats", Handler: ok, OpenAPI: documented("Stats")}},
+			Children: []Group{
+				{
+					Prefix: "/jobs",
+					Routes: []Route{{Method: "GET", Pattern: "", Handler: ok, OpenAPI: documented("List jobs")}},
+				},
+			},
+		},
+		{
+			Prefix:       "/v0",
+			Undocumented: true,
+			Routes:       []Route{{Method: "GET", Pattern: "/items", Handler: ok, OpenAPI: documented("Legacy items")}},
+			Children: []Group{
+				{
+					Prefix: "/notes",
+					Routes: []Route{{Method: "GET", Pattern: "", Handler: ok, OpenAPI: documented("Legacy notes")}},
+				},
+			},
+		},
+	}
+
+	want := RouteTable{
+		{Method: "GET", Path: "/items", GroupTags: []string{"Items"}, HasOpenAPI: true},
+		{Method: "POST", Path: "/items", GroupTags: []string{"Items"}},
+		{Method: "GET", Path: "/items/{id}/notes", GroupTags: []string{"Notes"}, HasOpenAPI: true},
+		{Method: "GET", Host: "admin.example.com", Path: "/admin/stats", GroupTags: []string{"Admin"}, HasOpenAPI: true},
+		{Method: "GET", Host: "admin.example.com", Path: "/admin/jobs", HasOpenAPI: true},
+		{Method: "GET", Path: "/v0/items"},
+		{Method: "GET", Path: "/v0/notes"},
+	}
+
+	got := Table(groups...)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Table() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	mux := http.NewServeMux()
+	spec := openapi.NewSpec("test", "1.0.0")
+	if err := Register(mux, "", spec, groups...); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	for _, info := range got {
+		documented := spec.HasPath(info.Path) && spec.Paths[info.Path].Operation(info.Method) != nil
+		if documented != info.HasOpenAPI {
+			t.Errorf("%s %s: HasOpenAPI = %v, but documented = %v", info.Method, info.Path, info.HasOpenAPI, documented)
+		}
+	}
+}
+
+func TestOperationsMatchTable(t *testing.T) {
+	group := Group{
+		Prefix:  "/items",
+		Tags:    []string{"Items"},
+		Schemas: map[string]*openapi.Schema{"Item": {Type: "object"}},
+		Routes: []Route{
+			{Method: "DELETE", Pattern: "/{id}", Handler: ok, OpenAPI: documented("Delete item")},
+			{Method: "PUT", Pattern: "/{id}", Handler: ok, OpenAPI: documented("Update item")},
+			{Method: "GET", Pattern: "/{id}", Handler: ok, OpenAPI: documented("Find item")},
+			{Method: "POST", Pattern: "", Handler: ok, OpenAPI: documented("Create item")},
+			{Method: "GET", Pattern: "", Handler: ok, OpenAPI: documented("List items")},
+			{Method: "HEAD", Pattern: "", Handler: ok},
+		},
+		Children: []Group{
+			{
+				Prefix: "/{id}/archive",
+				Routes: []Route{{Method: "POST", Pattern: "", Handler: ok, OpenAPI: documented("Archive item")}},
+			},
+		},
+	}
+
+	mux := http.NewServeMux()
+	spec := openapi.NewSpec("test", "1.0.0")
+	if err := Register(mux, "", spec, group); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	var got []string
+	for _, ref := range spec.Operations() {
+		got = append(got, ref.Method+" "+ref.Path)
+	}
+	want := []string{
+		"GET /items",
+		"POST /items",
+		"GET /items/{id}",
+		"PUT /items/{id}",
+		"DELETE /items/{id}",
+		"POST /items/{id}/archive",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Operations() = %q, want %q", got, want)
+	}
+
+	documentedRoutes := make(map[string]bool)
+	for _, info := range Table(group) {
+		if info.HasOpenAPI {
+			documentedRoutes[info.Method+" "+info.Path] = true
+		}
+		if !spec.HasPath(info.Path) {
+			t.Errorf("HasPath(%q) = false, want true", info.Path)
+		}
+	}
+	if len(documentedRoutes) != len(got) {
+		t.Errorf("table documents %d routes, spec has %d operations", len(documentedRoutes), len(got))
+	}
+	for _, op := range got {
+		if !documentedRoutes[op] {
+			t.Errorf("operation %s is not a documented route in the table", op)
+		}
+	}
+
+	if spec.Schema("Item") == nil {
+		t.Error("Schema(\"Item\") = nil, want the group's schema")
+	}
+	if spec.Schema("Missing") != nil {
+		t.Error("Schema(\"Missing\") != nil, want nil")
+	}
+	if spec.HasPath("/missing") {
+		t.Error("HasPath(\"/missing\") = true, want false")
+	}
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"slices"
+
+	"github.com/JaimeStill/go-lit/pkg/openapi"
+)
+
+// Versioned returns the group served under /{version}, with its operations
+// tagged with the version. Registering several versions of the same group
+// serves each at its own prefix, for example during a migration window:
+//
+//	v1 := routes.Versioned("v1", handler.Routes())
+//	v1.Undocumented = true
+//	routes.Register(mux, basePath, spec, v1, routes.Versioned("v2", handler.Routes()))
+func Versioned(version string, group Group) Group {
+	group.Prefix = "/" + version + group.Prefix
+	group.Version = version
+	return group
+}
+
+// VersionSpec returns the operations spec documents for version, for serving a
+// separate document per version. Client generators handle a single-version
+// document more reliably than a combined one.
+func VersionSpec(spec *openapi.Spec, version string) *openapi.Spec {
+	return spec.Filter(func(ref openapi.OperationRef) bool {
+		return slices.Contains(ref.Operation.Tags, version)
+	})
+}
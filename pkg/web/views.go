@@ -9,6 +9,9 @@ import (
 	"html/template"
 	"io/fs"
 	"net/http"
+
+	"github.com/JaimeStill/go-lit/pkg/middleware"
+	"github.com/JaimeStill/go-lit/pkg/module"
 )
 
 // ViewDef defines a page with its route, template file, title, and bundle name.
@@ -78,7 +81,7 @@ func (ts *TemplateSet) ErrorHandler(layout string, view ViewDef, status int) htt
 		data := ViewData{
 			Title:    view.Title,
 			Bundle:   view.Bundle,
-			BasePath: ts.basePath,
+			BasePath: ts.basePathFor(r),
 		}
 		if err := ts.Render(w, layout, view.Template, data); err != nil {
 			http.Error(w, http.StatusText(status), status)
@@ -92,7 +95,7 @@ func (ts *TemplateSet) ViewHandler(layout string, view ViewDef) http.HandlerFunc
 		data := ViewData{
 			Title:    view.Title,
 			Bundle:   view.Bundle,
-			BasePath: ts.basePath,
+			BasePath: ts.basePathFor(r),
 		}
 		if err := ts.Render(w, layout, view.Template, data); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -100,6 +103,16 @@ func (ts *TemplateSet) ViewHandler(layout string, view ViewDef) http.HandlerFunc
 	}
 }
 
+// basePathFor returns the base path as clients see it: the serving module's
+// external prefix, or the configured base path behind any forwarded prefix
+// when rendering outside a module.
+func (ts *TemplateSet) basePathFor(r *http.Request) string {
+	if prefix := module.ExternalPrefixFromContext(r.Context()); prefix != "" {
+		return prefix
+	}
+	return middleware.ForwardedPrefixFromContext(r.Context()) + ts.basePath
+}
+
 // Render executes the named layout template with the given page data.
 // It sets the Content-Type header to text/html.
 func (ts *TemplateSet) Render(w http.ResponseWriter, layoutName, viewPath string, data ViewData) error {
@@ -20,6 +20,9 @@ var layoutFS embed.FS
 //go:embed server/views/*
 var viewFS embed.FS
 
+//go:embed server/maintenance.html
+var maintenancePage []byte
+
 var publicFiles = []string{
 	"favicon.ico",
 	"favicon-16x16.png",
@@ -36,7 +39,33 @@ var views = []web.ViewDef{
 
 // NewModule creates the app module configured for the given base path.
 func NewModule(basePath string) (*module.Module, error) {
-	ts, err := web.NewTemplateSet(
+	ts, err := newTemplateSet(basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	router := buildRouter(ts)
+	return module.New(basePath, router, module.WithName("app"), module.WithDescription("Web client")), nil
+}
+
+// NotFoundHandler renders the app shell with a 404 status for paths outside
+// the app module, letting the client router display its not-found view.
+func NotFoundHandler(basePath string) (http.HandlerFunc, error) {
+	ts, err := newTemplateSet(basePath)
+	if err != nil {
+		return nil, err
+	}
+	return ts.ErrorHandler("app.html", views[0], http.StatusNotFound), nil
+}
+
+// MaintenancePage returns a self-contained HTML page shown to browsers while
+// the service is in maintenance, when the app's own assets are unavailable.
+func MaintenancePage() []byte {
+	return maintenancePage
+}
+
+func newTemplateSet(basePath string) (*web.TemplateSet, error) {
+	return web.NewTemplateSet(
 		layoutFS,
 		viewFS,
 		"server/layouts/*.html",
@@ -44,12 +73,6 @@ func NewModule(basePath string) (*module.Module, error) {
 		basePath,
 		views,
 	)
-	if err != nil {
-		return nil, err
-	}
-
-	router := buildRouter(ts)
-	return module.New(basePath, router), nil
 }
 
 func buildRouter(ts *web.TemplateSet) http.Handler {
@@ -16,7 +16,7 @@ var staticFS embed.FS
 // NewModule creates the Scalar documentation module at the given base path.
 func NewModule(basePath string) *module.Module {
 	router := buildRouter(basePath)
-	return module.New(basePath, router)
+	return module.New(basePath, router, module.WithName("scalar"), module.WithDescription("Interactive API reference"))
 }
 
 func buildRouter(basePath string) http.Handler {